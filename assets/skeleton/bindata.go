@@ -10,7 +10,7 @@
 // etc/skeleton/driver/fixtures/fixtures_test.go.tpl (1.2kB)
 // etc/skeleton/driver/impl/impl.go (251B)
 // etc/skeleton/driver/main.go.tpl (254B)
-// etc/skeleton/driver/normalizer/annotation.go (757B)
+// etc/skeleton/driver/normalizer/annotation.go (794B)
 // etc/skeleton/driver/normalizer/normalizer.go (1.216kB)
 // etc/skeleton/driver/normalizer/transforms.go.tpl (258B)
 // etc/skeleton/driver/sdk_test.go (394B)
@@ -1209,17 +1209,12 @@ import (
 )
 
 // Native is the of list ` + "`") + (`transformer.Transformer` + ("`" + ` to apply to a native AST.
+// It applies all the Annotations and removes duplicate roles assigned by multiple
+// transformation rules. Driver-specific annotations can be layered on top of a
+// shared list by passing them as the second argument to BuildPipeline.
 // To learn more about the Transformers and the available ones take a look to:
 // https://godoc.org/github.com/bblfsh/sdk/v3/uast/transformer
-var Native = Transformers([][]Transformer{
-	// The main block of transformation rules.
-	{Mappings(Annotations...)},
-	{
-		// RolesDedup is used to remove duplicate roles assigned by multiple
-		// transformation rules.
-		RolesDedup(),
-	},
-}...)
+var Native = BuildPipeline(Annotations, nil)
 
 // Annotations is a list of individual transformations to annotate a native AST with roles.
 var Annotations = []Mapping{
@@ -1237,8 +1232,8 @@ func driverNormalizerAnnotationGo() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "driver/normalizer/annotation.go", size: 757, mode: os.FileMode(0644), modTime: time.Unix(1, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x86, 0xe5, 0xd0, 0x8, 0x28, 0xcf, 0x48, 0x40, 0x40, 0x5, 0xbe, 0x2a, 0x84, 0x5e, 0xf8, 0xfc, 0xaf, 0xd, 0xed, 0xcc, 0xe4, 0x6d, 0xe, 0xcf, 0x88, 0xaa, 0x51, 0x2b, 0x5c, 0x20, 0xe1, 0x1a}}
+	info := bindataFileInfo{name: "driver/normalizer/annotation.go", size: 794, mode: os.FileMode(0644), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x67, 0x86, 0xc7, 0xc0, 0x85, 0x66, 0x0, 0x52, 0x5e, 0xf7, 0x4b, 0x80, 0x9a, 0x8d, 0x9f, 0x1, 0xa4, 0x16, 0x56, 0xeb, 0xb, 0x41, 0x63, 0x3c, 0x81, 0xde, 0x52, 0xf2, 0x38, 0xf4, 0x18, 0x37}}
 	return a, nil
 }
 
@@ -4,6 +4,7 @@ package driver
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"gopkg.in/src-d/go-errors.v1"
@@ -58,6 +59,17 @@ const (
 // ErrMulti joins multiple errors.
 type ErrMulti = derrors.ErrMulti
 
+// PositionedError is a parsing error with an optional location in the source file.
+type PositionedError = derrors.PositionedError
+
+// Severity of a parsing error.
+type Severity = derrors.Severity
+
+const (
+	SeverityError   = derrors.SeverityError
+	SeverityWarning = derrors.SeverityWarning
+)
+
 // Join multiple errors into a single error value.
 func JoinErrors(errs []error) error {
 	return derrors.Join(errs)
@@ -97,6 +109,102 @@ type ParseOptions struct {
 	Mode     Mode
 	Language string
 	Filename string
+	// Options is a set of driver-specific hints, like a parser dialect or a language version
+	// (e.g. "python_version=3.11").
+	//
+	// Options are advisory and applied on a best-effort basis. Drivers must ignore options
+	// they don't understand instead of failing the request.
+	Options map[string]string
+}
+
+type parseOptionsKey struct{}
+
+// WithParseOptions returns a new context that carries parse options.
+func WithParseOptions(ctx context.Context, opts *ParseOptions) context.Context {
+	return context.WithValue(ctx, parseOptionsKey{}, opts)
+}
+
+// ParseOptionsFromContext returns parse options associated with the context, if any.
+// Native drivers may use it to read driver-specific hints (see ParseOptions.Options).
+func ParseOptionsFromContext(ctx context.Context) *ParseOptions {
+	opts, _ := ctx.Value(parseOptionsKey{}).(*ParseOptions)
+	return opts
+}
+
+type warningsKey struct{}
+
+// Warnings collects non-fatal issues reported by drivers during a Parse call. Warnings do not indicate
+// a parsing failure, thus they are reported separately from errors. It is safe for concurrent use.
+type Warnings struct {
+	mu   sync.Mutex
+	list []string
+}
+
+// List returns all collected warnings.
+func (w *Warnings) List() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.list) == 0 {
+		return nil
+	}
+	return append([]string{}, w.list...)
+}
+
+// WithWarnings returns a new context that collects warnings reported with AddWarnings.
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// AddWarnings reports non-fatal parsing issues. Warnings are ignored if the context was not created with WithWarnings.
+func AddWarnings(ctx context.Context, msgs ...string) {
+	w, _ := ctx.Value(warningsKey{}).(*Warnings)
+	if w == nil || len(msgs) == 0 {
+		return
+	}
+	w.mu.Lock()
+	w.list = append(w.list, msgs...)
+	w.mu.Unlock()
+}
+
+// Parsing phases reported with ReportProgress by the driver and the server.
+const (
+	PhaseDecoding     = "decoding"
+	PhaseNativeParse  = "native-parse"
+	PhaseTransforming = "transforming"
+)
+
+// ProgressFunc is called for each progress event reported during a Parse call.
+// Percent is an estimated completion percentage of the whole request, in the range [0, 100].
+type ProgressFunc func(phase string, percent float64)
+
+type progressKey struct{}
+
+// WithProgress returns a new context that calls fnc for each progress event reported with ReportProgress.
+// Calls to fnc are serialized.
+func WithProgress(ctx context.Context, fnc ProgressFunc) context.Context {
+	var mu sync.Mutex
+	return context.WithValue(ctx, progressKey{}, ProgressFunc(func(phase string, percent float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		fnc(phase, percent)
+	}))
+}
+
+// ReportProgress reports the progress of a long-running parse. Native drivers that can estimate their progress
+// may call it periodically, others will only report transitions between phases, as done by the SDK.
+// Reports are ignored if the context was not created with WithProgress.
+func ReportProgress(ctx context.Context, phase string, percent float64) {
+	fnc, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	if fnc == nil {
+		return
+	}
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	fnc(phase, percent)
 }
 
 // Driver is an interface for a language driver that returns UAST.
@@ -111,6 +219,7 @@ type Driver interface {
 	//
 	// Syntax errors are indicated by returning ErrSyntax.
 	// In this case a non-empty UAST may be returned, if driver supports partial parsing.
+	// A partial UAST is transformed according to the mode in the same way as a complete one.
 	//
 	// Native driver failures are indicated by ErrDriverFailure and UAST transformation are indicated by ErrTransformFailure.
 	// All other errors indicate a protocol or server failure.
@@ -125,8 +234,9 @@ type Driver interface {
 
 // Version information for driver or the server.
 type Version struct {
-	Version string    // the version label for the driver, e.g., 'v1.2.3-tag' or 'undefined'
-	Build   time.Time // the timestamp when the driver was built
+	Version         string    // the version label for the driver, e.g., 'v1.2.3-tag' or 'undefined'
+	Build           time.Time // the timestamp when the driver was built
+	LanguageVersion string    // the version of the native parser or language runtime, if known
 }
 
 // DriverModule is an interface for a driver instance.
@@ -140,5 +250,8 @@ type Native interface {
 	Module
 	// Parse reads the input string and constructs an AST representation of it.
 	// All errors are considered ErrSyntax, unless they are wrapped into ErrDriverFailure.
+	//
+	// Error-tolerant parsers may return a partial AST together with a syntax error. It will be preserved
+	// and returned to the client with the error. The AST is always dropped for ErrDriverFailure.
 	Parse(ctx context.Context, src string) (nodes.Node, error)
 }
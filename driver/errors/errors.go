@@ -1,9 +1,12 @@
 package errors
 
 import (
+	"fmt"
 	"strings"
 
 	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/bblfsh/sdk/v3/uast"
 )
 
 var (
@@ -38,3 +41,34 @@ func (e *ErrMulti) Error() string {
 	}
 	return buf.String()
 }
+
+// Severity of a parsing error.
+type Severity int
+
+const (
+	// SeverityError indicates that the parser failed to process a part of the source file.
+	SeverityError Severity = iota
+	// SeverityWarning indicates a recoverable issue that does not affect the resulting tree.
+	SeverityWarning
+)
+
+// PositionedError is a parsing error with an optional location in the source file.
+// It allows clients to locate syntax errors without parsing error messages.
+type PositionedError struct {
+	// Text is an error message without the position.
+	Text     string
+	Severity Severity
+	// Position is a location of the error. It is nil if the location is unknown.
+	Position *uast.Position
+}
+
+func (e *PositionedError) Error() string {
+	p := e.Position
+	switch {
+	case p == nil:
+		return e.Text
+	case p.HasLineCol():
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Col, e.Text)
+	}
+	return fmt.Sprintf("offset %d: %s", p.Offset, e.Text)
+}
@@ -42,6 +42,12 @@ func (d *driverImpl) Close() error {
 	return d.d.Close()
 }
 
+// Rough estimates of the request completion at the start of each phase. They are only used for progress reports.
+const (
+	progressNativeParse  = 10
+	progressTransforming = 60
+)
+
 // Parse process a protocol.ParseRequest, calling to the native driver. It a
 // parser request is done to the internal native driver and the the returned
 // native AST is transform to UAST.
@@ -52,23 +58,34 @@ func (d *driverImpl) Parse(rctx context.Context, src string, opts *ParseOptions)
 	if opts == nil {
 		opts = &ParseOptions{}
 	}
-	ast, err := d.d.Parse(ctx, src)
+	ReportProgress(ctx, PhaseNativeParse, progressNativeParse)
+	ast, err := d.d.Parse(WithParseOptions(ctx, opts), src)
 	if err != nil {
-		if !ErrDriverFailure.Is(err) {
-			// all other errors are considered syntax errors
-			err = ErrSyntax.Wrap(err)
-		} else {
-			ast = nil
+		if ErrDriverFailure.Is(err) {
+			return nil, err
+		}
+		// all other errors are considered syntax errors
+		err = ErrSyntax.Wrap(err)
+		if ast == nil {
+			return nil, err
 		}
-		return ast, err
 	}
 	if opts.Language == "" {
 		opts.Language = d.m.Language
 	}
 
-	ast, err = d.t.Do(ctx, opts.Mode, src, ast)
+	ReportProgress(ctx, PhaseTransforming, progressTransforming)
+	ast, terr := d.t.Do(ctx, opts.Mode, src, ast)
 	if err != nil {
-		err = ErrTransformFailure.Wrap(err)
+		// partial AST - transform it the same way as a complete one,
+		// but drop it if the transformation fails, since it might be incomplete
+		if terr != nil {
+			ast = nil
+		}
+		return ast, err
+	}
+	if terr != nil {
+		err = ErrTransformFailure.Wrap(terr)
 	}
 	return ast, err
 }
@@ -76,8 +93,9 @@ func (d *driverImpl) Parse(rctx context.Context, src string, opts *ParseOptions)
 // Version returns driver version.
 func (d *driverImpl) Version(ctx context.Context) (Version, error) {
 	return Version{
-		Version: d.m.Version,
-		Build:   d.m.Build,
+		Version:         d.m.Version,
+		Build:           d.m.Build,
+		LanguageVersion: d.m.Runtime.NativeVersion,
 	}, nil
 }
 
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/bblfsh/sdk/v3/driver"
 	"github.com/bblfsh/sdk/v3/driver/native"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 )
@@ -24,6 +26,16 @@ func (mockDriver) Parse(ctx context.Context, src string) (nodes.Node, error) {
 		// protocol runs on stdout, break it and then exit
 		fmt.Println("crash command received")
 		os.Exit(0)
+	case "partial":
+		// syntax error with a partial AST
+		return nodes.Object{
+			"root": nodes.Object{
+				"key": nodes.String(src),
+			},
+		}, errors.New("unexpected EOF")
+	case "warn":
+		// non-fatal issue, the response is still successful
+		driver.AddWarnings(ctx, "deprecated syntax")
 	}
 	return nodes.Object{
 		"root": nodes.Object{
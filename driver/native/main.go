@@ -29,15 +29,15 @@ func Main(d driver.Native) {
 	}
 }
 
-func errToStrings(err error) []string {
+func toParseErrors(err error) []parseError {
 	if e, ok := err.(*driver.ErrMulti); ok {
-		str := make([]string, 0, len(e.Errors))
+		errs := make([]parseError, 0, len(e.Errors))
 		for _, e := range e.Errors {
-			str = append(str, e.Error())
+			errs = append(errs, newParseError(e))
 		}
-		return str
+		return errs
 	}
-	return []string{err.Error()}
+	return []parseError{newParseError(err)}
 }
 
 type nativeServer struct {
@@ -49,23 +49,26 @@ func (s *nativeServer) parse(ctx context.Context, req *parseRequest) *parseRespo
 	if err != nil {
 		return &parseResponse{
 			Status: statusFatal,
-			Errors: errToStrings(err),
+			Errors: toParseErrors(err),
 		}
 	}
+	ctx, w := driver.WithWarnings(ctx)
 	ast, err := s.d.Parse(ctx, src)
 	if driver.ErrDriverFailure.Is(err) {
 		return &parseResponse{
 			Status: statusFatal,
-			Errors: errToStrings(err),
+			Errors: toParseErrors(err),
 		}
 	}
 	if err != nil {
 		return &parseResponse{
-			Status: statusError,
-			AST:    ast, Errors: errToStrings(err),
+			Status:   statusError,
+			AST:      ast,
+			Errors:   toParseErrors(err),
+			Warnings: w.List(),
 		}
 	}
-	return &parseResponse{Status: statusOK, AST: ast}
+	return &parseResponse{Status: statusOK, AST: ast, Warnings: w.List()}
 }
 
 func (s *nativeServer) Serve(c io.ReadWriter) error {
@@ -80,7 +83,7 @@ func (s *nativeServer) Serve(c io.ReadWriter) error {
 		} else if err != nil {
 			resp := &parseResponse{
 				Status: statusFatal,
-				Errors: []string{fmt.Sprintf("failed to decode request: %v", err)},
+				Errors: []parseError{{Text: fmt.Sprintf("failed to decode request: %v", err)}},
 			}
 			if err = enc.Encode(resp); err != nil {
 				return err
@@ -18,6 +18,7 @@ import (
 	"github.com/bblfsh/sdk/v3/driver"
 	derrors "github.com/bblfsh/sdk/v3/driver/errors"
 	"github.com/bblfsh/sdk/v3/driver/native/jsonlines"
+	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 	serrors "gopkg.in/src-d/go-errors.v1"
 )
@@ -133,24 +134,94 @@ func (d *Driver) Start() error {
 // parseRequest is the request used to communicate the driver with the
 // native driver via json.
 type parseRequest struct {
-	Content  string   `json:"content"`
-	Encoding Encoding `json:"Encoding"`
+	Content  string            `json:"content"`
+	Encoding Encoding          `json:"Encoding"`
+	Options  map[string]string `json:"options,omitempty"`
 }
 
 var _ json.Unmarshaler = (*parseResponse)(nil)
 
+var (
+	_ json.Marshaler   = parseError{}
+	_ json.Unmarshaler = (*parseError)(nil)
+)
+
+// parseError is an error reported by the native parser. It is encoded either as a plain error message,
+// or as an object with the message, an optional position and severity ("error" or "warning").
+type parseError struct {
+	Text     string
+	Severity derrors.Severity
+	Pos      *uast.Position
+}
+
+type parseErrorJSON struct {
+	Text     string         `json:"text"`
+	Severity string         `json:"severity,omitempty"`
+	Pos      *uast.Position `json:"pos,omitempty"`
+}
+
+// newParseError converts an error to the native representation. Position and severity are preserved for
+// PositionedError.
+func newParseError(err error) parseError {
+	if e, ok := err.(*derrors.PositionedError); ok {
+		return parseError{Text: e.Text, Severity: e.Severity, Pos: e.Position}
+	}
+	return parseError{Text: err.Error()}
+}
+
+// toError converts the parser error to a Go error.
+func (e parseError) toError() error {
+	if e.Pos == nil && e.Severity == derrors.SeverityError {
+		return errors.New(e.Text)
+	}
+	return &derrors.PositionedError{Text: e.Text, Severity: e.Severity, Position: e.Pos}
+}
+
+func (e parseError) MarshalJSON() ([]byte, error) {
+	if e.Pos == nil && e.Severity == derrors.SeverityError {
+		return json.Marshal(e.Text)
+	}
+	v := parseErrorJSON{Text: e.Text, Pos: e.Pos}
+	if e.Severity == derrors.SeverityWarning {
+		v.Severity = "warning"
+	}
+	return json.Marshal(v)
+}
+
+func (e *parseError) UnmarshalJSON(data []byte) error {
+	if len(data) != 0 && data[0] == '"' {
+		*e = parseError{}
+		return json.Unmarshal(data, &e.Text)
+	}
+	var v parseErrorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = parseError{Text: v.Text, Pos: v.Pos}
+	switch v.Severity {
+	case "", "error":
+	case "warning":
+		e.Severity = derrors.SeverityWarning
+	default:
+		return fmt.Errorf("unsupported error severity: %q", v.Severity)
+	}
+	return nil
+}
+
 // parseResponse is the reply to parseRequest by the native parser.
 type parseResponse struct {
-	Status status     `json:"status"`
-	Errors []string   `json:"errors"`
-	AST    nodes.Node `json:"ast"`
+	Status   status       `json:"status"`
+	Errors   []parseError `json:"errors"`
+	Warnings []string     `json:"warnings,omitempty"`
+	AST      nodes.Node   `json:"ast"`
 }
 
 func (r *parseResponse) UnmarshalJSON(data []byte) error {
 	var resp struct {
-		Status status      `json:"status"`
-		Errors []string    `json:"errors"`
-		AST    interface{} `json:"ast"`
+		Status   status       `json:"status"`
+		Errors   []parseError `json:"errors"`
+		Warnings []string     `json:"warnings"`
+		AST      interface{}  `json:"ast"`
 	}
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return err
@@ -160,9 +231,10 @@ func (r *parseResponse) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	*r = parseResponse{
-		Status: resp.Status,
-		Errors: resp.Errors,
-		AST:    ast,
+		Status:   resp.Status,
+		Errors:   resp.Errors,
+		Warnings: resp.Warnings,
+		AST:      ast,
 	}
 	return nil
 }
@@ -293,9 +365,14 @@ func (d *Driver) Parse(rctx context.Context, src string) (nodes.Node, error) {
 		return nil, driver.ErrDriverFailure.Wrap(err, "unexpected state: %v", d.state)
 	}
 
-	err = d.writeRequest(ctx, &parseRequest{
+	req := &parseRequest{
 		Content: str, Encoding: d.ec,
-	})
+	}
+	if opts := driver.ParseOptionsFromContext(ctx); opts != nil {
+		// options are advisory, native drivers should ignore unknown ones
+		req.Options = opts.Options
+	}
+	err = d.writeRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -311,12 +388,14 @@ func (d *Driver) Parse(rctx context.Context, src string) (nodes.Node, error) {
 	if err != nil {
 		return nil, driver.ErrDriverFailure.Wrap(err)
 	}
+	// warnings are reported regardless of the status
+	driver.AddWarnings(ctx, r.Warnings...)
 	if r.Status == statusOK {
 		return r.AST, nil
 	}
 	errs := make([]error, 0, len(r.Errors))
-	for _, s := range r.Errors {
-		errs = append(errs, errors.New(s))
+	for _, e := range r.Errors {
+		errs = append(errs, e.toError())
 	}
 	err = derrors.Join(errs)
 	switch r.Status {
@@ -404,6 +483,22 @@ const (
 	Base64 = Encoding("base64")
 )
 
+// ParseEncoding parses an encoding name, as it may be specified by the user in
+// a command line flag or a config file. Names are case-insensitive.
+func ParseEncoding(s string) (Encoding, error) {
+	e := Encoding(strings.ToLower(strings.TrimSpace(s)))
+	switch e {
+	case UTF8, Base64:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown encoding: %q", s)
+}
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	return string(e)
+}
+
 func (e *Encoding) UnmarshalJSON(data []byte) error {
 	var str string
 	if err := json.Unmarshal(data, &str); err != nil {
@@ -2,6 +2,7 @@ package native
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"testing"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/bblfsh/sdk/v3/driver"
 	derrors "github.com/bblfsh/sdk/v3/driver/errors"
+	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 )
 
@@ -95,6 +98,73 @@ func TestNativeParse(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestNativeParsePartial(t *testing.T) {
+	require := require.New(t)
+
+	d := NewDriverAt("internal/simple/mock", "")
+	err := d.Start()
+	require.NoError(err)
+	defer d.Close()
+
+	r, err := d.Parse(context.Background(), "partial")
+	require.Error(err)
+	require.False(derrors.ErrDriverFailure.Is(err))
+	require.Equal(mockResponse("partial"), r)
+}
+
+func TestNativeParseWarnings(t *testing.T) {
+	require := require.New(t)
+
+	d := NewDriverAt("internal/simple/mock", "")
+	err := d.Start()
+	require.NoError(err)
+	defer d.Close()
+
+	ctx, w := driver.WithWarnings(context.Background())
+	r, err := d.Parse(ctx, "foo")
+	require.NoError(err)
+	require.Equal(mockResponse("foo"), r)
+	require.Empty(w.List())
+
+	r, err = d.Parse(ctx, "warn")
+	require.NoError(err)
+	require.Equal(mockResponse("warn"), r)
+	require.Equal([]string{"deprecated syntax"}, w.List())
+}
+
+func TestParseErrorJSON(t *testing.T) {
+	require := require.New(t)
+
+	var r parseResponse
+	err := json.Unmarshal([]byte(`{"status":"error","errors":[
+		"plain error",
+		{"text":"unexpected token","pos":{"offset":4,"line":1,"col":5}},
+		{"text":"missing semicolon","severity":"warning"}
+	],"ast":null}`), &r)
+	require.NoError(err)
+	exp := []parseError{
+		{Text: "plain error"},
+		{Text: "unexpected token", Pos: &uast.Position{Offset: 4, Line: 1, Col: 5}},
+		{Text: "missing semicolon", Severity: derrors.SeverityWarning},
+	}
+	require.Equal(exp, r.Errors)
+
+	data, err := json.Marshal(exp)
+	require.NoError(err)
+	require.JSONEq(`["plain error",
+		{"text":"unexpected token","pos":{"offset":4,"line":1,"col":5}},
+		{"text":"missing semicolon","severity":"warning"}]`, string(data))
+
+	require.Equal(&derrors.PositionedError{
+		Text:     "unexpected token",
+		Position: &uast.Position{Offset: 4, Line: 1, Col: 5},
+	}, exp[1].toError())
+	require.Equal("plain error", exp[0].toError().Error())
+
+	err = json.Unmarshal([]byte(`{"text":"x","severity":"fatal"}`), &parseError{})
+	require.Error(err)
+}
+
 func testNativeParseCrashWith(t *testing.T, keyword string, crash bool) {
 	require := require.New(t)
 
@@ -242,3 +312,22 @@ func TestNativeDriverParse_Timeout(t *testing.T) {
 	require.NoError(err)
 	require.Equal(mockResponse("second"), r)
 }
+
+func TestParseEncoding(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		exp  Encoding
+	}{
+		{name: "utf8", exp: UTF8},
+		{name: "UTF8", exp: UTF8},
+		{name: " Base64 ", exp: Base64},
+	} {
+		enc, err := ParseEncoding(c.name)
+		require.NoError(t, err, c.name)
+		require.Equal(t, c.exp, enc)
+		require.Equal(t, string(c.exp), enc.String())
+	}
+
+	_, err := ParseEncoding("utf16")
+	require.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/transformer"
+	"github.com/bblfsh/sdk/v3/uast/transformer/positioner"
+)
+
+// OffsetMap maps a byte offset in the preprocessed content to the offset in the original content.
+type OffsetMap func(offset int) int
+
+// ContentPreprocessor rewrites the source file content before it is parsed.
+//
+// Positions in the UAST are reported relative to the preprocessed content. Preprocessors that change
+// the byte offsets of the content (for example, by removing or inserting characters) must return an
+// offset map, so positions can be remapped to the original content after parsing. Preprocessors that
+// preserve offsets should return a nil map.
+type ContentPreprocessor interface {
+	Preprocess(ctx context.Context, src string) (string, OffsetMap, error)
+}
+
+// PreprocessorFunc is a function that implements ContentPreprocessor.
+type PreprocessorFunc func(ctx context.Context, src string) (string, OffsetMap, error)
+
+// Preprocess implements ContentPreprocessor.
+func (f PreprocessorFunc) Preprocess(ctx context.Context, src string) (string, OffsetMap, error) {
+	return f(ctx, src)
+}
+
+// NormalizeLineEndings returns a preprocessor that converts CRLF line endings to LF.
+func NormalizeLineEndings() ContentPreprocessor {
+	return PreprocessorFunc(func(ctx context.Context, src string) (string, OffsetMap, error) {
+		if !strings.Contains(src, "\r\n") {
+			return src, nil, nil
+		}
+		var (
+			buf strings.Builder
+			// offsets of '\n' in the output that had '\r' before them
+			lf []int
+		)
+		buf.Grow(len(src))
+		for i := 0; i < len(src); i++ {
+			if src[i] == '\r' && i+1 < len(src) && src[i+1] == '\n' {
+				lf = append(lf, buf.Len())
+				continue
+			}
+			buf.WriteByte(src[i])
+		}
+		return buf.String(), func(off int) int {
+			// the offset of '\n' is mapped to the '\r' before it, thus exclusive end positions
+			// of lines do not include the line break
+			return off + sort.SearchInts(lf, off)
+		}, nil
+	})
+}
+
+// Preprocess runs all preprocessors on the content in order. It returns the preprocessed content and
+// an offset map that maps offsets in it to the original content. The map is nil if no preprocessor
+// changed the offsets.
+func Preprocess(ctx context.Context, src string, pp []ContentPreprocessor) (string, OffsetMap, error) {
+	var maps []OffsetMap
+	for _, p := range pp {
+		var (
+			m   OffsetMap
+			err error
+		)
+		src, m, err = p.Preprocess(ctx, src)
+		if err != nil {
+			return "", nil, err
+		}
+		if m != nil {
+			maps = append(maps, m)
+		}
+	}
+	if len(maps) == 0 {
+		return src, nil, nil
+	}
+	return src, func(off int) int {
+		// the last preprocessor maps offsets to the content returned by the previous one
+		for i := len(maps) - 1; i >= 0; i-- {
+			off = maps[i](off)
+		}
+		return off
+	}, nil
+}
+
+// RemapPositions converts positions in the UAST parsed from the preprocessed content to positions in
+// the original content. Offsets are converted with the offset map, and lines and columns are recomputed
+// from the original content. Positions without a valid offset are left unchanged.
+func RemapPositions(ast nodes.Node, orig string, m OffsetMap) (nodes.Node, error) {
+	if m == nil || ast == nil {
+		return ast, nil
+	}
+	pm := NewPositionMapper(orig, m)
+	return transformer.TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		p := uast.AsPosition(n)
+		if p == nil || !p.HasOffset() {
+			return n, false, nil
+		}
+		if err := pm.Remap(p); err != nil {
+			return nil, false, err
+		}
+		return p.ToObject(), true, nil
+	}).Do(ast)
+}
+
+// PositionMapper converts individual positions from the preprocessed content to the original content,
+// the same way as RemapPositions. It is useful for positions stored outside of the UAST, e.g. in parsing errors.
+type PositionMapper struct {
+	idx *positioner.Index
+	m   OffsetMap
+}
+
+// NewPositionMapper creates a mapper for the original content and the offset map returned by Preprocess.
+// The offset map may be nil, in which case positions are left unchanged.
+func NewPositionMapper(orig string, m OffsetMap) *PositionMapper {
+	pm := &PositionMapper{m: m}
+	if m != nil {
+		pm.idx = positioner.NewIndex([]byte(orig), nil)
+	}
+	return pm
+}
+
+// Remap updates the position in place. Positions without a valid offset are left unchanged.
+func (pm *PositionMapper) Remap(p *uast.Position) error {
+	if pm.m == nil || p == nil || !p.HasOffset() {
+		return nil
+	}
+	off := pm.m(int(p.Offset))
+	line, col, err := pm.idx.LineCol(off)
+	if err != nil {
+		return err
+	}
+	p.Offset, p.Line, p.Col = uint32(off), uint32(line), uint32(col)
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 	"google.golang.org/grpc"
 	protocol1 "gopkg.in/bblfsh/sdk.v1/protocol"
+	serrors "gopkg.in/src-d/go-errors.v1"
 )
 
 // NewGRPCServer creates a gRPC server instance that dispatches requests to a provided driver.
@@ -24,6 +25,11 @@ func NewGRPCServer(drv driver.DriverModule, opts ...grpc.ServerOption) *grpc.Ser
 
 // NewGRPCServerCustom is the same as NewGRPCServer, but it won't include any options except the ones that were passed.
 func NewGRPCServerCustom(drv driver.DriverModule, opts ...grpc.ServerOption) *grpc.Server {
+	return newGRPCServerWith(drv, nil, opts...)
+}
+
+// newGRPCServerWith is the same as NewGRPCServerCustom, but allows to configure the v2 protocol server.
+func newGRPCServerWith(drv driver.DriverModule, popts []protocol2.RegisterOption, opts ...grpc.ServerOption) *grpc.Server {
 	srv := grpc.NewServer(opts...)
 
 	protocol1.DefaultService = service{drv}
@@ -31,7 +37,7 @@ func NewGRPCServerCustom(drv driver.DriverModule, opts ...grpc.ServerOption) *gr
 		srv,
 		protocol1.NewProtocolServiceServer(),
 	)
-	protocol2.RegisterDriver(srv, drv)
+	protocol2.RegisterDriver(srv, drv, popts...)
 
 	return srv
 }
@@ -115,8 +121,12 @@ func (s service) parse(mode driver.Mode, req *protocol1.ParseRequest) (nodes.Nod
 	})
 	dt := time.Since(start)
 	var r protocol1.Response
-	if err != nil {
+	if driver.ErrSyntax.Is(err) {
+		// syntax errors may still return a partial UAST
+		r = protocol1.Response{Status: protocol1.Error, Errors: syntaxErrors(err)}
+	} else if err != nil {
 		r = errResp(err)
+		ast = nil
 	} else {
 		r = protocol1.Response{Status: protocol1.Ok}
 	}
@@ -124,10 +134,25 @@ func (s service) parse(mode driver.Mode, req *protocol1.ParseRequest) (nodes.Nod
 	return ast, r
 }
 
+// syntaxErrors converts a syntax error to a list of error messages.
+func syntaxErrors(err error) []string {
+	if e, ok := err.(*serrors.Error); ok && e.Cause() != nil {
+		err = e.Cause()
+	}
+	if e, ok := err.(*driver.ErrMulti); ok {
+		str := make([]string, 0, len(e.Errors))
+		for _, e := range e.Errors {
+			str = append(str, e.Error())
+		}
+		return str
+	}
+	return []string{err.Error()}
+}
+
 // Parse implements protocol1.Service.
 func (s service) Parse(req *protocol1.ParseRequest) *protocol1.ParseResponse {
 	ast, resp := s.parse(driver.ModeAnnotated, req)
-	if resp.Status != protocol1.Ok {
+	if resp.Status == protocol1.Fatal || ast == nil {
 		return &protocol1.ParseResponse{Response: resp}
 	}
 	nd, err := uast1.ToNode(ast)
@@ -147,7 +172,7 @@ func (s service) Parse(req *protocol1.ParseRequest) *protocol1.ParseResponse {
 // NativeParse implements protocol1.Service.
 func (s service) NativeParse(req *protocol1.NativeParseRequest) *protocol1.NativeParseResponse {
 	ast, resp := s.parse(driver.ModeNative, (*protocol1.ParseRequest)(req))
-	if resp.Status != protocol1.Ok {
+	if resp.Status == protocol1.Fatal || ast == nil {
 		return &protocol1.NativeParseResponse{Response: resp}
 	}
 	data, err := json.Marshal(ast)
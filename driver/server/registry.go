@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/driver/manifest"
+	"github.com/bblfsh/sdk/v3/protocol"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ driver.DriverModule = (*ParserRegistry)(nil)
+
+// ParserRegistry is a set of language drivers served behind a single server.
+//
+// It implements driver.DriverModule and dispatches each Parse request to the driver registered for the requested
+// language. If the language is not set, it is detected from the file extension: extensions passed to Register
+// take precedence, and other extensions are detected with protocol.LanguageByFilename, the same way as clients
+// do when building requests with protocol.RequestFromFile.
+type ParserRegistry struct {
+	mu      sync.RWMutex
+	drivers []driver.DriverModule
+	byLang  map[string]driver.DriverModule
+	byExt   map[string]string
+}
+
+// NewParserRegistry creates an empty driver registry.
+func NewParserRegistry() *ParserRegistry {
+	return &ParserRegistry{
+		byLang: make(map[string]driver.DriverModule),
+		byExt:  make(map[string]string),
+	}
+}
+
+// Register adds a driver to the registry. The driver will be used for all languages and aliases listed in its
+// manifests. An optional list of file extensions (e.g. ".ts") is used to detect the language of requests that
+// have no language set. It is only required for extensions not known to protocol.LanguageByFilename, or to
+// override the language detected for them.
+//
+// It returns an error if one of the languages is already registered.
+func (r *ParserRegistry) Register(d driver.DriverModule, exts ...string) error {
+	list, err := d.Languages(context.Background())
+	if err != nil {
+		return err
+	} else if len(list) == 0 {
+		return fmt.Errorf("driver has no manifests")
+	}
+	var langs []string
+	for _, m := range list {
+		langs = append(langs, m.Language)
+		langs = append(langs, m.Aliases...)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, lang := range langs {
+		if _, ok := r.byLang[lang]; ok {
+			return fmt.Errorf("language %q is already registered", lang)
+		}
+	}
+	for _, lang := range langs {
+		r.byLang[lang] = d
+	}
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		r.byExt[ext] = list[0].Language
+	}
+	r.drivers = append(r.drivers, d)
+	return nil
+}
+
+// lookup returns a driver for a given language. If the language is empty, it is detected from the file name.
+func (r *ParserRegistry) lookup(lang, filename string) (string, driver.DriverModule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if lang == "" {
+		lang = r.byExt[strings.ToLower(filepath.Ext(filename))]
+		if lang == "" {
+			lang = protocol.LanguageByFilename(filename)
+		}
+		if lang == "" {
+			return "", nil, driver.ErrLanguageDetection.New()
+		}
+	}
+	d, ok := r.byLang[lang]
+	if !ok {
+		return lang, nil, &driver.ErrMissingDriver{Language: lang}
+	}
+	return lang, d, nil
+}
+
+// Start starts all registered drivers.
+func (r *ParserRegistry) Start() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, d := range r.drivers {
+		if err := d.Start(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops all registered drivers.
+func (r *ParserRegistry) Close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var last error
+	for _, d := range r.drivers {
+		if err := d.Close(); err != nil {
+			last = err
+		}
+	}
+	return last
+}
+
+// Parse implements driver.Driver. It dispatches the request to a driver for the requested language.
+//
+// The language in options is only set if it was empty, aliases are resolved without changing the options.
+// It returns driver.ErrLanguageDetection if the language is not set and cannot be detected from the file name,
+// and driver.ErrMissingDriver if there is no driver for the language.
+func (r *ParserRegistry) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	if opts == nil {
+		opts = &driver.ParseOptions{}
+	}
+	lang, d, err := r.lookup(opts.Language, opts.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Language == "" {
+		// report the detected language, the same way as drivers do
+		opts.Language = lang
+	}
+	dopts := *opts
+	dopts.Language = lang
+	return d.Parse(ctx, src, &dopts)
+}
+
+// Version implements driver.Driver. The registry has no version on its own, thus it returns an empty version.
+func (r *ParserRegistry) Version(ctx context.Context) (driver.Version, error) {
+	return driver.Version{}, nil
+}
+
+// Languages implements driver.Driver. It returns manifests of all registered drivers.
+func (r *ParserRegistry) Languages(ctx context.Context) ([]manifest.Manifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []manifest.Manifest
+	for _, d := range r.drivers {
+		list, err := d.Languages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list...)
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/driver/manifest"
+	"github.com/bblfsh/sdk/v3/protocol"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ driver.DriverModule = (*langDriverMock)(nil)
+
+type langDriverMock struct {
+	m       manifest.Manifest
+	started bool
+}
+
+func (d *langDriverMock) Start() error {
+	d.started = true
+	return nil
+}
+
+func (d *langDriverMock) Close() error {
+	d.started = false
+	return nil
+}
+
+func (d *langDriverMock) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	return nodes.String(d.m.Language), nil
+}
+
+func (d *langDriverMock) Version(ctx context.Context) (driver.Version, error) {
+	return driver.Version{Version: d.m.Version}, nil
+}
+
+func (d *langDriverMock) Languages(ctx context.Context) ([]manifest.Manifest, error) {
+	return []manifest.Manifest{d.m}, nil
+}
+
+func TestParserRegistry(t *testing.T) {
+	js := &langDriverMock{m: manifest.Manifest{Language: "javascript", Aliases: []string{"js"}}}
+	ts := &langDriverMock{m: manifest.Manifest{Language: "typescript"}}
+	py := &langDriverMock{m: manifest.Manifest{Language: "python"}}
+
+	r := NewParserRegistry()
+	require.NoError(t, r.Register(js, ".js"))
+	require.NoError(t, r.Register(ts, "ts"))
+	require.NoError(t, r.Register(py, ".tsx"))
+	require.Error(t, r.Register(&langDriverMock{m: manifest.Manifest{Language: "js"}}))
+
+	require.NoError(t, r.Start())
+	require.True(t, js.started && ts.started && py.started)
+
+	list, err := r.Languages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	ctx := context.Background()
+	for _, c := range []struct {
+		opts *driver.ParseOptions
+		exp  string
+	}{
+		{opts: &driver.ParseOptions{Language: "javascript"}, exp: "javascript"},
+		{opts: &driver.ParseOptions{Language: "js"}, exp: "javascript"},
+		{opts: &driver.ParseOptions{Filename: "a/b.TS"}, exp: "typescript"},
+		{opts: &driver.ParseOptions{Language: "typescript", Filename: "b.js"}, exp: "typescript"},
+		// extensions not registered explicitly are detected the same way as in protocol.RequestFromFile
+		{opts: &driver.ParseOptions{Filename: "c.mjs"}, exp: "javascript"},
+		{opts: &driver.ParseOptions{Filename: "c.py"}, exp: "python"},
+		// registered extensions take precedence
+		{opts: &driver.ParseOptions{Filename: "c.tsx"}, exp: "python"},
+	} {
+		n, err := r.Parse(ctx, "", c.opts)
+		require.NoError(t, err)
+		require.Equal(t, nodes.String(c.exp), n)
+	}
+
+	opts := &driver.ParseOptions{Filename: "b.ts"}
+	_, err = r.Parse(ctx, "", opts)
+	require.NoError(t, err)
+	require.Equal(t, "typescript", opts.Language)
+
+	// caller options are not changed when the alias is resolved
+	opts = &driver.ParseOptions{Language: "js"}
+	_, err = r.Parse(ctx, "", opts)
+	require.NoError(t, err)
+	require.Equal(t, "js", opts.Language)
+
+	_, err = r.Parse(ctx, "", &driver.ParseOptions{Filename: "b.unknown"})
+	require.True(t, driver.ErrLanguageDetection.Is(err))
+
+	_, err = r.Parse(ctx, "", &driver.ParseOptions{Language: "go"})
+	require.True(t, driver.IsMissingDriver(err))
+
+	_, err = r.Parse(ctx, "", &driver.ParseOptions{Filename: "b.go"})
+	require.True(t, driver.IsMissingDriver(err))
+
+	require.NoError(t, r.Close())
+	require.False(t, js.started || ts.started || py.started)
+}
+
+func TestParserRegistryGRPC(t *testing.T) {
+	js := &langDriverMock{m: manifest.Manifest{Language: "javascript"}}
+	r := NewParserRegistry()
+	require.NoError(t, r.Register(js, ".js"))
+	require.NoError(t, r.Start())
+	defer r.Close()
+
+	srv := NewGRPCServer(r)
+	defer srv.Stop()
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go srv.Serve(lis)
+
+	opts := append([]grpc.DialOption{grpc.WithInsecure()}, protocol.DialOptions()...)
+	cc, err := grpc.Dial(lis.Addr().String(), opts...)
+	require.NoError(t, err)
+	defer cc.Close()
+	d := protocol.AsDriver(cc)
+
+	ctx := context.Background()
+	n, err := d.Parse(ctx, "", &driver.ParseOptions{Filename: "a.js"})
+	require.NoError(t, err)
+	require.Equal(t, nodes.String("javascript"), n)
+
+	// errors are propagated to the client with their kinds
+	_, err = d.Parse(ctx, "", &driver.ParseOptions{Language: "go"})
+	require.True(t, driver.IsMissingDriver(err), "%v", err)
+	require.Equal(t, "go", err.(*driver.ErrMissingDriver).Language)
+
+	_, err = d.Parse(ctx, "", &driver.ParseOptions{Filename: "b.unknown"})
+	require.True(t, driver.ErrLanguageDetection.Is(err), "%v", err)
+}
@@ -7,12 +7,15 @@ import (
 	"io"
 	"net"
 	"os"
+	"time"
 
 	jaegercfg "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 
 	cmdutil "github.com/bblfsh/sdk/v3/cmd"
 	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/protocol"
 	"gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-log.v1"
 )
@@ -32,6 +35,7 @@ var (
 	address        *string
 	verbose        *string
 	maxMessageSize *int
+	contentRoot    *string
 	logs           struct {
 		level  *string
 		format *string
@@ -46,15 +50,77 @@ type Server struct {
 	Logger log.Logger
 
 	d driver.DriverModule
+	// polyglot is set if the server hosts multiple language drivers
+	polyglot bool
+	// reflection is set if the gRPC reflection service should be registered
+	reflection bool
+	// register is a set of options for the v2 protocol server
+	register []protocol.RegisterOption
 
 	// closers is a list of things to be closed
 	// TODO: proper driver shutdown logic; it's unused right now
 	closers []io.Closer
 }
 
+// Option is an optional configuration for the driver server.
+type Option func(s *Server)
+
+// WithReflection enables the gRPC reflection service on the server. It allows tools like grpcurl
+// to list the services and methods exposed by the driver without having the protobuf definitions.
+//
+// Note that reflection exposes the complete API surface of the server to any client that can
+// connect to it. It should only be enabled if the server is not reachable by untrusted clients.
+func WithReflection() Option {
+	return func(s *Server) {
+		s.reflection = true
+	}
+}
+
+// WithContentFetcher allows clients to reference the file content with URIs of a given scheme
+// instead of sending it in parse requests. See protocol.ContentFetcher.
+//
+// Support for file:// URIs can be enabled with the content-root flag.
+func WithContentFetcher(scheme string, f protocol.ContentFetcher) Option {
+	return func(s *Server) {
+		s.register = append(s.register, protocol.WithContentFetcher(scheme, f))
+	}
+}
+
+// WithPreprocessors sets a chain of preprocessors that are applied to the content of each parse request
+// before it is passed to the driver. See driver.ContentPreprocessor.
+func WithPreprocessors(pp ...driver.ContentPreprocessor) Option {
+	return func(s *Server) {
+		s.register = append(s.register, protocol.WithPreprocessors(pp...))
+	}
+}
+
+// WithParseTimeout limits the duration of each parse request on the server, regardless of the client deadline.
+// See protocol.WithParseTimeout.
+func WithParseTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.register = append(s.register, protocol.WithParseTimeout(d))
+	}
+}
+
 // NewServer returns a new server for a given Driver.
-func NewServer(d driver.DriverModule) *Server {
-	return &Server{d: d}
+func NewServer(d driver.DriverModule, opts ...Option) *Server {
+	s := &Server{d: d}
+	s.apply(opts)
+	return s
+}
+
+// NewPolyglotServer returns a new server that serves all drivers from the registry on a single port.
+// Requests are dispatched to drivers by the language, see ParserRegistry.
+func NewPolyglotServer(r *ParserRegistry, opts ...Option) *Server {
+	s := &Server{d: r, polyglot: true}
+	s.apply(opts)
+	return s
+}
+
+func (s *Server) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
 }
 
 // Start executes the binary driver and start to listen in the network and
@@ -87,11 +153,16 @@ func (s *Server) initialize() error {
 	list, err := s.d.Languages(context.Background())
 	if err != nil {
 		return err
-	} else if len(list) != 1 {
+	} else if s.polyglot && len(list) == 0 {
+		return fmt.Errorf("expected at least one manifest")
+	} else if !s.polyglot && len(list) != 1 {
 		return fmt.Errorf("expected exactly one manifest, got %d", len(list))
 	}
-	m := list[0]
-	if err := s.initializeTracing(m.Language + "-driver"); err != nil {
+	service := list[0].Language + "-driver"
+	if s.polyglot {
+		service = "polyglot-driver"
+	}
+	if err := s.initializeTracing(service); err != nil {
 		return err
 	}
 
@@ -101,19 +172,34 @@ func (s *Server) initialize() error {
 		os.Exit(1)
 	}
 
-	build := "unknown"
-	if !m.Build.IsZero() {
-		build = m.Build.Format("2006-01-02T15:04:05Z")
+	for _, m := range list {
+		build := "unknown"
+		if !m.Build.IsZero() {
+			build = m.Build.Format("2006-01-02T15:04:05Z")
+		}
+		s.Logger.Infof("%s-driver version: %s (build: %s)",
+			m.Language,
+			m.Version,
+			build,
+		)
 	}
-	s.Logger.Infof("%s-driver version: %s (build: %s)",
-		m.Language,
-		m.Version,
-		build,
-	)
-	s.grpc = NewGRPCServer(s.d, grpcOpts...)
+	if *contentRoot != "" {
+		s.register = append(s.register, protocol.WithContentRoot(*contentRoot))
+	}
+	s.grpc = s.newGRPCServer(grpcOpts...)
 	return nil
 }
 
+// newGRPCServer creates a gRPC server for the driver and registers optional services on it.
+func (s *Server) newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, protocol.ServerOptions()...)
+	srv := newGRPCServerWith(s.d, s.register, opts...)
+	if s.reflection {
+		reflection.Register(srv)
+	}
+	return srv
+}
+
 func (s *Server) initializeFlags() {
 	const (
 		defaultNetwork = "tcp"
@@ -126,6 +212,7 @@ func (s *Server) initializeFlags() {
 	network = cmd.String("network", defaultNetwork, "network type: tcp, tcp4, tcp6, unix or unixpacket.")
 	address = cmd.String("address", defaultAddress, "address to listen.")
 	maxMessageSize = cmdutil.FlagMaxGRPCMsgSizeMB(cmd)
+	contentRoot = cmd.String("content-root", "", "directory that clients can read files from with file:// content URIs; disabled if empty.")
 
 	logs.level = cmd.String("log-level", defaultVerbose, "log level: panic, fatal, error, warning, info, debug.")
 	logs.format = cmd.String("log-format", defaultFormat, "format of the logs: text or json.")
@@ -68,6 +68,38 @@ func TestDriverParserParse(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestDriverParserParse_Partial(t *testing.T) {
+	require := require.New(t)
+
+	d, err := newDriver("")
+	require.NoError(err)
+	require.NotNil(d)
+
+	err = d.d.Start()
+	require.NoError(err)
+	defer d.d.Close()
+
+	r := d.Parse(&protocol1.ParseRequest{
+		Language: "fixture",
+		Content:  "partial",
+	})
+
+	require.NotNil(r)
+	require.Equal(protocol1.Error, r.Status)
+	require.Equal([]string{"unexpected EOF"}, r.Errors)
+	require.NotNil(r.UAST)
+
+	nr := d.NativeParse(&protocol1.NativeParseRequest{
+		Language: "fixture",
+		Content:  "partial",
+	})
+
+	require.NotNil(nr)
+	require.Equal(protocol1.Error, nr.Status)
+	require.Equal([]string{"unexpected EOF"}, nr.Errors)
+	require.Equal("{\"root\":{\"key\":\"partial\"}}", nr.AST)
+}
+
 func TestDriverParserParse_MissingLanguage(t *testing.T) {
 	require := require.New(t)
 
@@ -154,3 +186,20 @@ func TestDriverParserVersion(t *testing.T) {
 	require.Equal(v.Version, "42")
 	require.Equal(v.Build.String(), "2015-10-21 04:29:00 +0000 UTC")
 }
+
+func TestServerReflection(t *testing.T) {
+	const reflectionService = "grpc.reflection.v1alpha.ServerReflection"
+
+	d, err := newDriver("")
+	require.NoError(t, err)
+
+	srv := NewServer(d.d).newGRPCServer()
+	_, ok := srv.GetServiceInfo()[reflectionService]
+	require.False(t, ok, "reflection should be disabled by default")
+
+	srv = NewServer(d.d, WithReflection()).newGRPCServer()
+	info := srv.GetServiceInfo()
+	_, ok = info[reflectionService]
+	require.True(t, ok, "reflection should be enabled")
+	require.Contains(t, info, "gopkg.in.bblfsh.sdk.v2.protocol.Driver")
+}
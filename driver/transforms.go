@@ -6,6 +6,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
 	"github.com/bblfsh/sdk/v3/uast/transformer"
 )
 
@@ -79,6 +80,14 @@ func (t Transforms) Do(rctx context.Context, mode Mode, code string, nd nodes.No
 	return t.do(ctx, mode, code, nd)
 }
 
+// ListRoles returns a sorted set of roles that the Annotations stage may assign. It doesn't run any transformations.
+// Dynamic is set to true if some annotations compute roles at runtime, thus the list might be incomplete.
+//
+// See transformer.ListRoles for details.
+func (t Transforms) ListRoles() (roles []role.Role, dynamic bool) {
+	return transformer.ListRoles(t.Annotations...)
+}
+
 func (t Transforms) do(ctx context.Context, mode Mode, code string, nd nodes.Node) (nodes.Node, error) {
 	var err error
 	runAll := func(name string, list []transformer.Transformer) error {
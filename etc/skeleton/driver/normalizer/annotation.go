@@ -6,17 +6,12 @@ import (
 )
 
 // Native is the of list `transformer.Transformer` to apply to a native AST.
+// It applies all the Annotations and removes duplicate roles assigned by multiple
+// transformation rules. Driver-specific annotations can be layered on top of a
+// shared list by passing them as the second argument to BuildPipeline.
 // To learn more about the Transformers and the available ones take a look to:
 // https://godoc.org/github.com/bblfsh/sdk/v3/uast/transformer
-var Native = Transformers([][]Transformer{
-	// The main block of transformation rules.
-	{Mappings(Annotations...)},
-	{
-		// RolesDedup is used to remove duplicate roles assigned by multiple
-		// transformation rules.
-		RolesDedup(),
-	},
-}...)
+var Native = BuildPipeline(Annotations, nil)
 
 // Annotations is a list of individual transformations to annotate a native AST with roles.
 var Annotations = []Mapping{
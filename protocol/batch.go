@@ -0,0 +1,82 @@
+package protocol
+
+import (
+	"context"
+	"sync"
+)
+
+// ParseResult is a result of a single Parse call issued by ParseAll.
+type ParseResult struct {
+	Response *ParseResponse
+	Err      error
+}
+
+// ParseAll sends all parse requests to the driver concurrently and returns the results in the same order as requests.
+//
+// At most concurrency requests are in flight at the same time; values less than 1 mean no concurrency.
+// No new requests are issued after the context is cancelled, and the results of those requests will contain
+// the context error.
+//
+// If stopOnError is set, ParseAll cancels all remaining requests on the first failure and returns it.
+// Otherwise, per-request errors are only reported in the results and the returned error is set only
+// if the context was cancelled.
+func ParseAll(ctx context.Context, c DriverClient, reqs []*ParseRequest, concurrency int, stopOnError bool) ([]ParseResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg sync.WaitGroup
+		// limits the number of concurrent requests
+		tokens = make(chan struct{}, concurrency)
+
+		mu    sync.Mutex
+		first error
+	)
+	out := make([]ParseResult, len(reqs))
+	setErr := func(err error) {
+		mu.Lock()
+		if first == nil {
+			first = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+	for i, req := range reqs {
+		select {
+		case <-cctx.Done():
+			out[i].Err = cctx.Err()
+			continue
+		case tokens <- struct{}{}:
+		}
+		// select picks a random case if both are ready, thus the context may be cancelled already
+		if err := cctx.Err(); err != nil {
+			<-tokens
+			out[i].Err = err
+			continue
+		}
+		wg.Add(1)
+		go func(r *ParseResult, req *ParseRequest) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			resp, err := c.Parse(cctx, req)
+			if err != nil {
+				r.Err = fromGRPCError(err)
+				if stopOnError {
+					setErr(r.Err)
+				}
+				return
+			}
+			r.Response = resp
+		}(&out[i], req)
+	}
+	wg.Wait()
+	if first != nil {
+		return out, first
+	}
+	return out, ctx.Err()
+}
@@ -0,0 +1,149 @@
+package protocol
+
+import (
+	"context"
+	"sort"
+
+	serrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// LanguageResult is a result of parsing a file as a specific language, as returned by ParseBest.
+type LanguageResult struct {
+	// Language is a language used to parse the file.
+	Language string
+	// Response is the raw response from the driver. It is nil if the request failed.
+	Response *ParseResponse
+	// UAST is the decoded UAST. It might be set even if Err is not nil, in case of a partial parse.
+	UAST nodes.Node
+	// Err is a parsing error, if any.
+	Err error
+	// Score is a quality score of the result assigned by ScoreFunc.
+	Score float64
+}
+
+// Failed checks if the result has no UAST or if the error is not a syntax error.
+// Failed results are never selected as the best result.
+func (r *LanguageResult) Failed() bool {
+	return r.UAST == nil || (r.Err != nil && !driver.ErrSyntax.Is(r.Err))
+}
+
+// ScoreFunc computes a quality score of a parse result. Results with a higher score are preferred.
+type ScoreFunc func(r *LanguageResult) float64
+
+// errorPenalty is a score penalty for each syntax error used by DefaultScore.
+const errorPenalty = 1 << 20
+
+// DefaultScore prefers results with fewer syntax errors and then results with more recognized nodes.
+// A node is considered recognized if it has a type and its roles are known (see uast.RolesOf).
+func DefaultScore(r *LanguageResult) float64 {
+	var recognized int
+	nodes.WalkPreOrder(r.UAST, func(n nodes.Node) bool {
+		obj, ok := n.(nodes.Object)
+		if !ok {
+			return true
+		}
+		if uast.TypeOf(obj) == "" {
+			return true
+		}
+		roles := uast.RolesOf(obj)
+		if len(roles) != 0 && !(len(roles) == 1 && roles[0] == role.Unannotated) {
+			recognized++
+		}
+		return true
+	})
+	return float64(recognized) - float64(countErrors(r.Err))*errorPenalty
+}
+
+// countErrors returns the number of syntax errors in the error.
+func countErrors(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*serrors.Error); ok && e.Cause() != nil {
+		err = e.Cause()
+	}
+	if e, ok := err.(*driver.ErrMulti); ok {
+		return len(e.Errors)
+	}
+	return 1
+}
+
+// ParseBest parses the same file with each of the candidate languages concurrently and returns the best result,
+// according to the score function. If score is nil, DefaultScore is used. The key of the clients map is the
+// language name; the language in the request is ignored.
+//
+// All other results are returned as alternatives, ordered by their score. Failed results (see LanguageResult.Failed)
+// are placed at the end and are never selected as the best result. If all candidates failed, the best result is nil,
+// all results are returned as alternatives and the error of the first candidate (in the language name order)
+// is returned.
+//
+// ParseBest returns the context error as soon as the context is cancelled. Requests that are still in flight
+// are cancelled as well.
+func ParseBest(ctx context.Context, clients map[string]DriverClient, req *ParseRequest, score ScoreFunc) (best *LanguageResult, alts []*LanguageResult, _ error) {
+	if score == nil {
+		score = DefaultScore
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan *LanguageResult, len(clients))
+	for lang, c := range clients {
+		go func(lang string, c DriverClient) {
+			r := &LanguageResult{Language: lang}
+			defer func() {
+				results <- r
+			}()
+			lreq := *req
+			lreq.Language = lang
+			resp, err := c.Parse(cctx, &lreq)
+			if err != nil {
+				r.Err = fromGRPCError(err)
+				return
+			}
+			r.Response = resp
+			r.UAST, r.Err = resp.Nodes()
+			if !r.Failed() {
+				r.Score = score(r)
+			}
+		}(lang, c)
+	}
+	list := make([]*LanguageResult, 0, len(clients))
+	for range clients {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case r := <-results:
+			list = append(list, r)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		// cancelled while collecting the last results
+		return nil, nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil, nil
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if fa, fb := a.Failed(), b.Failed(); fa != fb {
+			return !fa
+		} else if !fa && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Language < b.Language
+	})
+	if list[0].Failed() {
+		// all failed; the list is sorted by the language name
+		err := list[0].Err
+		if err == nil {
+			err = driver.ErrDriverFailure.New()
+		}
+		return nil, list, err
+	}
+	return list[0], list[1:], nil
+}
@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// directiveLines is the number of lines at the start of the file that may contain directives.
+const directiveLines = 2
+
+// reCoding matches a coding directive as defined by PEP 263. It also matches Emacs and Vim forms,
+// e.g. "-*- coding: utf-8 -*-" and "vim: set fileencoding=utf-8 :".
+var reCoding = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*([-_.a-zA-Z0-9]+)`)
+
+// interpreters maps interpreter names found in shebang lines to languages. Version suffixes are removed
+// before the lookup, thus "python3" and "python2.7" are both mapped to "python".
+var interpreters = map[string]string{
+	"python": "python",
+	"ruby":   "ruby",
+	"node":   "javascript",
+	"nodejs": "javascript",
+	"sh":     "bash",
+	"bash":   "bash",
+	"zsh":    "bash",
+	"perl":   "perl",
+	"php":    "php",
+}
+
+// charsetAliases maps alternative charset names to the canonical ones.
+var charsetAliases = map[string]string{
+	"utf8":    "utf-8",
+	"latin-1": "iso-8859-1",
+	"latin1":  "iso-8859-1",
+}
+
+// SniffDirectives reads the first lines of the file and returns the language from a shebang line
+// (e.g. "#!/usr/bin/env python3") and the charset from a coding directive (e.g. "# -*- coding: utf-8 -*-").
+//
+// The language is only returned for known interpreters, and the charset is converted to lower case with common
+// aliases replaced by canonical names. The last value reports if any of the hints were found.
+func SniffDirectives(content []byte) (lang, charset string, ok bool) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	for i := 0; i < directiveLines && len(content) != 0; i++ {
+		var line []byte
+		if j := bytes.IndexByte(content, '\n'); j >= 0 {
+			line, content = content[:j], content[j+1:]
+		} else {
+			line, content = content, nil
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if i == 0 && bytes.HasPrefix(line, []byte("#!")) {
+			lang = sniffShebang(string(line[2:]))
+			continue
+		}
+		if sub := reCoding.FindSubmatch(line); sub != nil {
+			charset = normalizeCharset(string(sub[1]))
+			break
+		}
+	}
+	return lang, charset, lang != "" || charset != ""
+}
+
+// sniffShebang returns the language of the interpreter in a shebang line, without the "#!" prefix.
+func sniffShebang(line string) string {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return ""
+	}
+	name := path.Base(args[0])
+	if name == "env" {
+		// skip env flags and variable assignments, e.g. "env -S VAR=1 python3 -u"
+		name = ""
+		for _, a := range args[1:] {
+			if strings.HasPrefix(a, "-") || strings.Contains(a, "=") {
+				continue
+			}
+			name = path.Base(a)
+			break
+		}
+	}
+	name = strings.TrimRight(name, "0123456789.")
+	return interpreters[name]
+}
+
+// normalizeCharset converts the charset name to the canonical form.
+func normalizeCharset(name string) string {
+	name = strings.Replace(strings.ToLower(name), "_", "-", -1)
+	if alias, ok := charsetAliases[name]; ok {
+		return alias
+	}
+	return name
+}
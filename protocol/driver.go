@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
@@ -17,8 +18,10 @@ import (
 
 	"github.com/bblfsh/sdk/v3/driver"
 	"github.com/bblfsh/sdk/v3/driver/manifest"
+	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 	"github.com/bblfsh/sdk/v3/uast/nodes/nodesproto"
+	"github.com/bblfsh/sdk/v3/uast/uastjson"
 )
 
 //go:generate protoc --proto_path=$GOPATH/src:. --gogo_out=plugins=grpc:. ./driver.proto
@@ -71,12 +74,38 @@ func DialOptions() []grpc.DialOption {
 }
 
 // RegisterDriver registers a v2 driver server on a given gRPC server.
-func RegisterDriver(srv *grpc.Server, d driver.Driver) {
+func RegisterDriver(srv *grpc.Server, d driver.Driver, opts ...RegisterOption) {
 	s := &driverServer{d: d}
+	for _, opt := range opts {
+		opt(s)
+	}
 	RegisterDriverServer(srv, s)
 	RegisterDriverHostServer(srv, s)
 }
 
+// RegisterOption is an optional configuration for RegisterDriver.
+type RegisterOption func(s *driverServer)
+
+// WithPreprocessors adds preprocessors that are applied in order to the decoded content of each parse request.
+// Positions in the UAST are mapped back to the original content, see driver.ContentPreprocessor.
+func WithPreprocessors(pp ...driver.ContentPreprocessor) RegisterOption {
+	return func(s *driverServer) {
+		s.preprocessors = append(s.preprocessors, pp...)
+	}
+}
+
+// WithParseTimeout limits the duration of each parse request, regardless of the client deadline. Requests that
+// exceed the timeout fail with the DeadlineExceeded status code.
+//
+// The driver receives a context with the timeout and is expected to stop parsing when it expires. If the driver
+// ignores the context, the server still responds to the client when the timeout expires, but the parsing continues
+// in the background until the driver returns.
+func WithParseTimeout(d time.Duration) RegisterOption {
+	return func(s *driverServer) {
+		s.timeout = d
+	}
+}
+
 // AsDriver creates a v2 driver client for a given gRPC client.
 func AsDriver(cc *grpc.ClientConn) driver.Driver {
 	return DriverFromClient(NewDriverClient(cc), NewDriverHostClient(cc))
@@ -89,17 +118,52 @@ func DriverFromClient(c DriverClient, h DriverHostClient) driver.Driver {
 	}
 }
 
+// AsDriverWithLimits is like AsDriver, but the returned client will reject UASTs that exceed given limits.
+// It should be used by servers that proxy requests to drivers they don't trust.
+func AsDriverWithLimits(cc *grpc.ClientConn, l nodesproto.Limits) driver.Driver {
+	return &client{
+		c:      NewDriverClient(cc),
+		h:      NewDriverHostClient(cc),
+		limits: l,
+	}
+}
+
 func toParseErrors(err error) []*ParseError {
 	if e, ok := err.(*driver.ErrMulti); ok {
 		errs := make([]*ParseError, 0, len(e.Errors))
 		for _, e := range e.Errors {
-			errs = append(errs, &ParseError{Text: e.Error()})
+			errs = append(errs, toParseError(e))
 		}
 		return errs
 	}
-	return []*ParseError{
-		{Text: err.Error()},
+	return []*ParseError{toParseError(err)}
+}
+
+// toParseError converts an error to the protocol message. Position and severity are preserved for
+// driver.PositionedError.
+func toParseError(err error) *ParseError {
+	e, ok := err.(*driver.PositionedError)
+	if !ok {
+		return &ParseError{Text: err.Error()}
+	}
+	pe := &ParseError{Text: e.Text, Severity: Severity(e.Severity)}
+	if p := e.Position; p != nil {
+		pe.Position = &ErrorPosition{Offset: p.Offset, Line: p.Line, Col: p.Col}
 	}
+	return pe
+}
+
+// toError converts the protocol message to an error. Errors with a position or a non-default severity
+// are returned as driver.PositionedError.
+func (e *ParseError) toError() error {
+	if e.Position == nil && e.Severity == Severity_SeverityError {
+		return errors.New(e.Text)
+	}
+	pe := &driver.PositionedError{Text: e.Text, Severity: driver.Severity(e.Severity)}
+	if p := e.Position; p != nil {
+		pe.Position = &uast.Position{Offset: p.Offset, Line: p.Line, Col: p.Col}
+	}
+	return pe
 }
 
 // newGRPCError creates a new gRPC error with a specified code, message and optional details.
@@ -114,11 +178,22 @@ func newGRPCError(code codes.Code, cause error, details ...proto.Message) error
 
 type driverServer struct {
 	d driver.Driver
+	// fetchers for content URIs, by scheme
+	fetchers map[string]ContentFetcher
+	// preprocessors for the decoded content
+	preprocessors []driver.ContentPreprocessor
+	// timeout for a single parse request; zero means no timeout
+	timeout time.Duration
 }
 
 // toGRPCError converts an error to gRPC equivalent.
 // Some errors may be silenced and added to resp instead (e.g. syntax errors).
 func toGRPCError(resp *ParseResponse, err error) error {
+	if e, ok := err.(*driver.ErrMissingDriver); ok {
+		return newGRPCError(codes.InvalidArgument, e, &ErrorDetails{
+			Reason: &ErrorDetails_UnsupportedLanguage{UnsupportedLanguage: e.Language},
+		})
+	}
 	e, ok := err.(*serrors.Error)
 	if !ok {
 		return err
@@ -153,11 +228,6 @@ func toGRPCError(resp *ParseResponse, err error) error {
 		resp.Errors = toParseErrors(cause)
 		return nil
 	}
-	if e, ok := err.(*driver.ErrMissingDriver); ok {
-		return newGRPCError(codes.InvalidArgument, cause, &ErrorDetails{
-			Reason: &ErrorDetails_UnsupportedLanguage{UnsupportedLanguage: e.Language},
-		})
-	}
 	return err // unknown error
 }
 
@@ -166,29 +236,141 @@ func (s *driverServer) Parse(rctx context.Context, req *ParseRequest) (*ParseRes
 	sp, ctx := opentracing.StartSpanFromContext(rctx, "bblfsh.server.Parse")
 	defer sp.Finish()
 
+	return s.parse(ctx, req)
+}
+
+// parse runs the parse request and encodes the resulting UAST.
+func (s *driverServer) parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
+	var resp ParseResponse
+	n, err := s.parseTree(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	dsp, _ := opentracing.StartSpanFromContext(ctx, "uast.Encode")
+	defer dsp.Finish()
+
+	buf := bytes.NewBuffer(nil)
+	switch req.Format {
+	case Format_FormatProto:
+		err = nodesproto.WriteTo(buf, n)
+		if err != nil {
+			return nil, err // unknown error = server failure
+		}
+		resp.Uast = buf.Bytes()
+	case Format_FormatJSON:
+		err = uastjson.NewEncoder(buf).Encode(n)
+		if err != nil {
+			return nil, err // unknown error = server failure
+		}
+		resp.UastJSON = buf.Bytes()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported UAST format: %v", req.Format)
+	}
+	return &resp, nil
+}
+
+// parseTree runs the parse request through all the steps preceding the UAST encoding: it reads and decodes
+// the content, applies preprocessors, calls the driver and maps positions back to the original content.
+// The response is filled with the language, warnings and parsing errors.
+func (s *driverServer) parseTree(ctx context.Context, req *ParseRequest, resp *ParseResponse) (nodes.Node, error) {
 	opts := &driver.ParseOptions{
 		Mode:     driver.Mode(req.Mode),
 		Language: req.Language,
 		Filename: req.Filename,
+		Options:  req.Options,
+	}
+	driver.ReportProgress(ctx, driver.PhaseDecoding, 0)
+	content, err := s.content(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	src, err := DecodeContent(content, req.Encoding)
+	if err != nil {
+		return nil, toGRPCError(resp, err)
+	}
+	orig := src
+	src, remap, err := driver.Preprocess(ctx, src, s.preprocessors)
+	if err != nil {
+		return nil, err
+	}
+	ctx, w := driver.WithWarnings(ctx)
+	n, err := s.parseDriver(ctx, src, opts)
+	if err == errParseTimeout {
+		return nil, status.Errorf(codes.DeadlineExceeded, "parse timeout exceeded (%v)", s.timeout)
 	}
-	var resp ParseResponse
-	n, err := s.d.Parse(ctx, req.Content, opts)
 	resp.Language = opts.Language // can be set during the call
-	err = toGRPCError(&resp, err)
+	resp.Warnings = w.List()
+	err = toGRPCError(resp, err)
 	if err != nil {
 		return nil, err
 	}
+	if err = remapErrors(resp.Errors, orig, remap); err != nil {
+		return nil, err
+	}
+	return driver.RemapPositions(n, orig, remap)
+}
 
-	dsp, _ := opentracing.StartSpanFromContext(ctx, "uast.Encode")
-	defer dsp.Finish()
+// remapErrors converts positions of parsing errors to the original content, see driver.RemapPositions.
+func remapErrors(errs []*ParseError, orig string, m driver.OffsetMap) error {
+	if m == nil {
+		return nil
+	}
+	pm := driver.NewPositionMapper(orig, m)
+	for _, e := range errs {
+		ep := e.Position
+		if ep == nil {
+			continue
+		}
+		p := uast.Position{Offset: ep.Offset, Line: ep.Line, Col: ep.Col}
+		if err := pm.Remap(&p); err != nil {
+			return err
+		}
+		ep.Offset, ep.Line, ep.Col = p.Offset, p.Line, p.Col
+	}
+	return nil
+}
 
-	buf := bytes.NewBuffer(nil)
-	err = nodesproto.WriteTo(buf, n)
-	if err != nil {
-		return nil, err // unknown error = server failure
+// errParseTimeout is returned by parseDriver if the server-side parse timeout has expired.
+var errParseTimeout = errors.New("parse timeout exceeded")
+
+// parseDriver calls the driver, enforcing the server-side parse timeout, if any.
+//
+// Drivers that ignore the context cannot be stopped. In this case the function returns as soon as the timeout
+// expires, while the parsing continues in the background.
+func (s *driverServer) parseDriver(rctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	if s.timeout <= 0 {
+		return s.d.Parse(rctx, src, opts)
+	}
+	ctx, cancel := context.WithTimeout(rctx, s.timeout)
+	defer cancel()
+
+	// the driver may modify the options; use a copy to avoid races with the background parsing
+	dopts := *opts
+	type result struct {
+		ast nodes.Node
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ast, err := s.d.Parse(ctx, src, &dopts)
+		done <- result{ast: ast, err: err}
+	}()
+	select {
+	case r := <-done:
+		*opts = dopts
+		if r.err != nil && ctx.Err() != nil && rctx.Err() == nil {
+			// the driver stopped because of the server-side timeout
+			return nil, errParseTimeout
+		}
+		return r.ast, r.err
+	case <-ctx.Done():
+		if err := rctx.Err(); err != nil {
+			// cancelled by the client
+			return nil, err
+		}
+		return nil, errParseTimeout
 	}
-	resp.Uast = buf.Bytes()
-	return &resp, nil
 }
 
 func (s *driverServer) ServerVersion(rctx context.Context, _ *VersionRequest) (*VersionResponse, error) {
@@ -199,7 +381,12 @@ func (s *driverServer) ServerVersion(rctx context.Context, _ *VersionRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	vers := Version{Version: resp.Version, Build: resp.Build}
+	vers := Version{
+		Version:         resp.Version,
+		Build:           resp.Build,
+		DriverVersion:   resp.Version,
+		LanguageVersion: resp.LanguageVersion,
+	}
 	return &VersionResponse{Version: &vers}, nil
 }
 
@@ -220,8 +407,9 @@ func (s *driverServer) SupportedLanguages(rctx context.Context, _ *SupportedLang
 }
 
 type client struct {
-	c DriverClient
-	h DriverHostClient
+	c      DriverClient
+	h      DriverHostClient
+	limits nodesproto.Limits
 }
 
 // fromGRPCError extract error details from gRPC error codes and error details and converts it to a native bblfsh error.
@@ -288,6 +476,7 @@ func (c *client) Parse(rctx context.Context, src string, opts *driver.ParseOptio
 		req.Mode = Mode(opts.Mode)
 		req.Language = opts.Language
 		req.Filename = opts.Filename
+		req.Options = opts.Options
 	}
 	resp, err := c.c.Parse(ctx, req)
 	err = fromGRPCError(err)
@@ -297,23 +486,44 @@ func (c *client) Parse(rctx context.Context, src string, opts *driver.ParseOptio
 	if opts != nil && opts.Language == "" {
 		opts.Language = resp.Language
 	}
+	driver.AddWarnings(ctx, resp.Warnings...)
 
 	dsp, _ := opentracing.StartSpanFromContext(ctx, "uast.Decode")
 	defer dsp.Finish()
 
 	// it may be still a parsing error
-	return resp.Nodes()
+	return resp.NodesWithLimits(c.limits)
 }
 
+// Nodes decodes the UAST from the response. If the response contains parsing errors, they are returned
+// as driver.ErrSyntax, together with a partial UAST.
 func (m *ParseResponse) Nodes() (nodes.Node, error) {
-	ast, err := nodesproto.ReadTree(bytes.NewReader(m.Uast))
+	return m.NodesWithLimits(nodesproto.Limits{})
+}
+
+// NodesWithLimits is like Nodes, but returns a *nodesproto.LimitError if the UAST exceeds given limits.
+//
+// Limits are enforced for both binary and JSON UAST formats. The JSON UAST is checked after it is decoded.
+func (m *ParseResponse) NodesWithLimits(l nodesproto.Limits) (nodes.Node, error) {
+	var (
+		ast nodes.Node
+		err error
+	)
+	if len(m.Uast) == 0 && len(m.UastJSON) != 0 {
+		ast, err = uastjson.Unmarshal(m.UastJSON)
+		if err == nil {
+			err = nodesproto.CheckLimits(ast, l)
+		}
+	} else {
+		ast, err = nodesproto.ReadTreeWithLimits(bytes.NewReader(m.Uast), l)
+	}
 	if err != nil {
 		return nil, err
 	}
 	if len(m.Errors) != 0 {
 		var errs []error
 		for _, e := range m.Errors {
-			errs = append(errs, errors.New(e.Text))
+			errs = append(errs, e.toError())
 		}
 		// syntax error or partial parse - return both UAST and an error
 		err = driver.ErrSyntax.Wrap(driver.JoinErrors(errs))
@@ -330,7 +540,7 @@ func (c *client) Version(rctx context.Context) (driver.Version, error) {
 	if err != nil {
 		return driver.Version{}, err
 	}
-	return driver.Version{Version: resp.Version.Version, Build: resp.Version.Build}, nil
+	return resp.Version.toNative(), nil
 }
 
 // Languages implements DriverHostClient.
@@ -349,6 +559,23 @@ func (c *client) Languages(rctx context.Context) ([]manifest.Manifest, error) {
 	return out, nil
 }
 
+// toNative converts the version message to the driver version used by the SDK.
+func (m *Version) toNative() driver.Version {
+	if m == nil {
+		return driver.Version{}
+	}
+	vers := m.Version
+	if vers == "" {
+		// server may only set the new field
+		vers = m.DriverVersion
+	}
+	return driver.Version{
+		Version:         vers,
+		Build:           m.Build,
+		LanguageVersion: m.LanguageVersion,
+	}
+}
+
 // NewManifest converts driver manifest to the corresponding protocol message.
 func NewManifest(m *manifest.Manifest) *Manifest {
 	dm := &Manifest{
@@ -357,10 +584,12 @@ func NewManifest(m *manifest.Manifest) *Manifest {
 		Aliases:  m.Aliases,
 		Features: make([]string, 0, len(m.Features)),
 	}
-	if m.Version != "" || !m.Build.IsZero() {
+	if m.Version != "" || !m.Build.IsZero() || m.Runtime.NativeVersion != "" {
 		dm.Version = &Version{
-			Version: m.Version,
-			Build:   m.Build,
+			Version:         m.Version,
+			Build:           m.Build,
+			DriverVersion:   m.Version,
+			LanguageVersion: m.Runtime.NativeVersion,
 		}
 	}
 	switch m.Status {
@@ -395,8 +624,10 @@ func (m *Manifest) toNative(dm *manifest.Manifest) {
 	dm.Aliases = m.Aliases
 	dm.Features = make([]manifest.Feature, 0, len(m.Features))
 	if m.Version != nil {
-		dm.Version = m.Version.Version
-		dm.Build = m.Version.Build
+		vers := m.Version.toNative()
+		dm.Version = vers.Version
+		dm.Build = vers.Build
+		dm.Runtime.NativeVersion = vers.LanguageVersion
 	}
 	switch m.Status {
 	case DevelopmentStatus_Inactive:
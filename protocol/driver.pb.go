@@ -5,6 +5,7 @@ package protocol
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -33,6 +34,77 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// Encoding is a character encoding of the source file content in ParseRequest.
+type Encoding int32
+
+const (
+	// UTF8 is the default encoding. Content must be a valid UTF-8 string.
+	Encoding_EncodingUTF8 Encoding = 0
+	// UTF16 is an UTF-16 content with the byte order detected from the BOM.
+	// Little-endian order is assumed if the content has no BOM.
+	Encoding_EncodingUTF16 Encoding = 1
+	// UTF16LE is an UTF-16 content in little-endian byte order. An optional BOM is skipped.
+	Encoding_EncodingUTF16LE Encoding = 2
+	// UTF16BE is an UTF-16 content in big-endian byte order. An optional BOM is skipped.
+	Encoding_EncodingUTF16BE Encoding = 3
+	// Base64 is a binary content encoded with the standard base64 encoding. It can be used for files that
+	// are not valid UTF-8. The decoded content is passed to the driver as is.
+	Encoding_EncodingBase64 Encoding = 4
+)
+
+var Encoding_name = map[int32]string{
+	0: "ENCODING_UTF8",
+	1: "ENCODING_UTF16",
+	2: "ENCODING_UTF16LE",
+	3: "ENCODING_UTF16BE",
+	4: "ENCODING_BASE64",
+}
+
+var Encoding_value = map[string]int32{
+	"ENCODING_UTF8":    0,
+	"ENCODING_UTF16":   1,
+	"ENCODING_UTF16LE": 2,
+	"ENCODING_UTF16BE": 3,
+	"ENCODING_BASE64":  4,
+}
+
+func (x Encoding) String() string {
+	return proto.EnumName(Encoding_name, int32(x))
+}
+
+func (Encoding) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{0}
+}
+
+// Format is an encoding of the UAST in ParseResponse.
+type Format int32
+
+const (
+	// Proto encodes the UAST in a binary protobuf-based format and stores it in ParseResponse.uast.
+	Format_FormatProto Format = 0
+	// JSON encodes the UAST as a single JSON document and stores it in ParseResponse.uast_json.
+	// It is useful for clients that cannot decode the binary format.
+	Format_FormatJSON Format = 1
+)
+
+var Format_name = map[int32]string{
+	0: "FORMAT_PROTO",
+	1: "FORMAT_JSON",
+}
+
+var Format_value = map[string]int32{
+	"FORMAT_PROTO": 0,
+	"FORMAT_JSON":  1,
+}
+
+func (x Format) String() string {
+	return proto.EnumName(Format_name, int32(x))
+}
+
+func (Format) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{1}
+}
+
 type Mode int32
 
 const (
@@ -69,7 +141,75 @@ func (x Mode) String() string {
 }
 
 func (Mode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_521003751d596b5e, []int{0}
+	return fileDescriptor_521003751d596b5e, []int{2}
+}
+
+// Severity of the parsing error.
+type Severity int32
+
+const (
+	// Error indicates that the parser failed to process a part of the source file.
+	Severity_SeverityError Severity = 0
+	// Warning indicates a recoverable issue that does not affect the resulting tree.
+	Severity_SeverityWarning Severity = 1
+)
+
+var Severity_name = map[int32]string{
+	0: "SEVERITY_ERROR",
+	1: "SEVERITY_WARNING",
+}
+
+var Severity_value = map[string]int32{
+	"SEVERITY_ERROR":   0,
+	"SEVERITY_WARNING": 1,
+}
+
+func (x Severity) String() string {
+	return proto.EnumName(Severity_name, int32(x))
+}
+
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{3}
+}
+
+// NodeEventType is a type of the event emitted when walking the UAST.
+type NodeEventType int32
+
+const (
+	// Value event contains a single value node. If no value is set, the node is nil.
+	NodeEventType_Value NodeEventType = 0
+	// ObjectStart event marks the start of an object node. It is followed by events for each object field.
+	NodeEventType_ObjectStart NodeEventType = 1
+	// ObjectEnd event marks the end of an object node.
+	NodeEventType_ObjectEnd NodeEventType = 2
+	// ArrayStart event marks the start of an array node. It is followed by events for each array element.
+	NodeEventType_ArrayStart NodeEventType = 3
+	// ArrayEnd event marks the end of an array node.
+	NodeEventType_ArrayEnd NodeEventType = 4
+)
+
+var NodeEventType_name = map[int32]string{
+	0: "EVENT_VALUE",
+	1: "EVENT_OBJECT_START",
+	2: "EVENT_OBJECT_END",
+	3: "EVENT_ARRAY_START",
+	4: "EVENT_ARRAY_END",
+}
+
+var NodeEventType_value = map[string]int32{
+	"EVENT_VALUE":        0,
+	"EVENT_OBJECT_START": 1,
+	"EVENT_OBJECT_END":   2,
+	"EVENT_ARRAY_START":  3,
+	"EVENT_ARRAY_END":    4,
+}
+
+func (x NodeEventType) String() string {
+	return proto.EnumName(NodeEventType_name, int32(x))
+}
+
+func (NodeEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{4}
 }
 
 type DevelopmentStatus int32
@@ -109,19 +249,34 @@ func (x DevelopmentStatus) String() string {
 }
 
 func (DevelopmentStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_521003751d596b5e, []int{1}
+	return fileDescriptor_521003751d596b5e, []int{5}
 }
 
 // ParseRequest is a request to parse a file and get its UAST.
 type ParseRequest struct {
-	// Content stores the content of a source file. Required.
+	// Content stores the content of a source file. Required, unless RawContent or ContentURI is set.
+	// Protobuf strings must be valid UTF-8, thus content in UTF-16 encodings must be sent in RawContent.
 	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
 	// Language can be set optionally to disable automatic language detection.
 	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	// Filename can be set optionally to assist automatic language detection.
 	Filename string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
 	// Mode sets a transformation pipeline used for UAST.
-	Mode                 Mode     `protobuf:"varint,4,opt,name=mode,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.Mode" json:"mode,omitempty"`
+	Mode Mode `protobuf:"varint,4,opt,name=mode,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.Mode" json:"mode,omitempty"`
+	// Options is a set of driver-specific hints (parser dialect, language version, feature flags).
+	// Options are advisory and applied on a best-effort basis; drivers ignore unknown options.
+	Options map[string]string `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Format selects an encoding of the UAST in the response.
+	Format Format `protobuf:"varint,6,opt,name=format,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.Format" json:"format,omitempty"`
+	// Encoding is an encoding of the content. Content is always converted to UTF-8 before parsing,
+	// thus positions in the UAST are always reported relative to the UTF-8 representation of the content.
+	Encoding Encoding `protobuf:"varint,7,opt,name=encoding,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.Encoding" json:"encoding,omitempty"`
+	// ContentURI is an URI of the source file that the server reads instead of receiving the Content.
+	// It can only be set if the Content and RawContent are empty. The server must be configured to accept the URI scheme.
+	ContentURI string `protobuf:"bytes,8,opt,name=content_uri,json=contentUri,proto3" json:"content_uri,omitempty"`
+	// RawContent stores the content of a source file as bytes. It must be used for content that is not a valid
+	// UTF-8 string, e.g. in UTF-16 encodings. Only one of Content, RawContent and ContentURI can be set.
+	RawContent           []byte   `protobuf:"bytes,9,opt,name=raw_content,json=rawContent,proto3" json:"raw_content,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -168,10 +323,15 @@ type ParseResponse struct {
 	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	// Errors is a list of parsing errors.
 	// Only set if parser was able to return a response. Otherwise gRPC error codes are used.
-	Errors               []*ParseError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Errors []*ParseError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Warnings is a list of non-fatal issues reported by the parser, e.g. deprecated syntax or recovered errors.
+	// Warnings do not indicate a parsing failure.
+	Warnings []string `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// UASTJSON is a JSON encoding of the resulting UAST. Only set if JSON format was requested.
+	UastJSON             []byte   `protobuf:"bytes,5,opt,name=uast_json,json=uastJson,proto3" json:"uast_json,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ParseResponse) Reset()         { *m = ParseResponse{} }
@@ -208,8 +368,12 @@ func (m *ParseResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_ParseResponse proto.InternalMessageInfo
 
 type ParseError struct {
-	// Text is an error message.
-	Text                 string   `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Text is an error message. It never includes the position, thus clients should use Position to locate the error.
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Position is a location of the error in the source file. Not set if the location is unknown.
+	Position *ErrorPosition `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	// Severity of the error.
+	Severity             Severity `protobuf:"varint,3,opt,name=severity,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.Severity" json:"severity,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -248,26 +412,31 @@ func (m *ParseError) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ParseError proto.InternalMessageInfo
 
-type Version struct {
-	Version              string    `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	Build                time.Time `protobuf:"bytes,2,opt,name=build,proto3,stdtime" json:"build"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+// ErrorPosition is a location of the parsing error in the source file.
+type ErrorPosition struct {
+	// Offset is a zero-based byte offset in the UTF-8 representation of the content.
+	Offset uint32 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Line is a one-based line number.
+	Line uint32 `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	// Col is a one-based column number, in bytes.
+	Col                  uint32   `protobuf:"varint,3,opt,name=col,proto3" json:"col,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Version) Reset()         { *m = Version{} }
-func (m *Version) String() string { return proto.CompactTextString(m) }
-func (*Version) ProtoMessage()    {}
-func (*Version) Descriptor() ([]byte, []int) {
+func (m *ErrorPosition) Reset()         { *m = ErrorPosition{} }
+func (m *ErrorPosition) String() string { return proto.CompactTextString(m) }
+func (*ErrorPosition) ProtoMessage()    {}
+func (*ErrorPosition) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{3}
 }
-func (m *Version) XXX_Unmarshal(b []byte) error {
+func (m *ErrorPosition) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ErrorPosition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ErrorPosition.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -277,48 +446,50 @@ func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Version) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Version.Merge(m, src)
+func (m *ErrorPosition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorPosition.Merge(m, src)
 }
-func (m *Version) XXX_Size() int {
+func (m *ErrorPosition) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *Version) XXX_DiscardUnknown() {
-	xxx_messageInfo_Version.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Version proto.InternalMessageInfo
-
-type Manifest struct {
-	// Name is a human-readable language or driver name.
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// Language is a Babelfish language identifier.
-	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
-	// Aliases is a list of alternative language identifiers from Enry/Linguist.
-	Aliases []string `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
-	// Version of the language driver.
-	Version *Version `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
-	// Status of the driver development.
-	Status DevelopmentStatus `protobuf:"varint,5,opt,name=status,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.DevelopmentStatus" json:"status,omitempty"`
-	// Features this driver supports.
-	Features             []string `protobuf:"bytes,6,rep,name=features,proto3" json:"features,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Manifest) Reset()         { *m = Manifest{} }
-func (m *Manifest) String() string { return proto.CompactTextString(m) }
-func (*Manifest) ProtoMessage()    {}
-func (*Manifest) Descriptor() ([]byte, []int) {
+func (m *ErrorPosition) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorPosition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorPosition proto.InternalMessageInfo
+
+// NodeEvent is a single event emitted when walking the UAST in pre-order.
+type NodeEvent struct {
+	Type NodeEventType `protobuf:"varint,1,opt,name=type,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.NodeEventType" json:"type,omitempty"`
+	// Key is a name of the object field. Only set for Value, ObjectStart and ArrayStart events
+	// of nodes that are stored in an object field.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Value is only set for Value events.
+	//
+	// Types that are valid to be assigned to Value:
+	//	*NodeEvent_StringValue
+	//	*NodeEvent_IntValue
+	//	*NodeEvent_UintValue
+	//	*NodeEvent_FloatValue
+	//	*NodeEvent_BoolValue
+	Value                isNodeEvent_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NodeEvent) Reset()         { *m = NodeEvent{} }
+func (m *NodeEvent) String() string { return proto.CompactTextString(m) }
+func (*NodeEvent) ProtoMessage()    {}
+func (*NodeEvent) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{4}
 }
-func (m *Manifest) XXX_Unmarshal(b []byte) error {
+func (m *NodeEvent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Manifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *NodeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Manifest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_NodeEvent.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -328,36 +499,226 @@ func (m *Manifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Manifest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Manifest.Merge(m, src)
+func (m *NodeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeEvent.Merge(m, src)
 }
-func (m *Manifest) XXX_Size() int {
+func (m *NodeEvent) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *Manifest) XXX_DiscardUnknown() {
-	xxx_messageInfo_Manifest.DiscardUnknown(m)
+func (m *NodeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeEvent.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Manifest proto.InternalMessageInfo
+var xxx_messageInfo_NodeEvent proto.InternalMessageInfo
 
-type VersionRequest struct {
+type isNodeEvent_Value interface {
+	isNodeEvent_Value()
+	MarshalTo([]byte) (int, error)
+	ProtoSize() int
+}
+
+type NodeEvent_StringValue struct {
+	StringValue string `protobuf:"bytes,3,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+type NodeEvent_IntValue struct {
+	IntValue int64 `protobuf:"varint,4,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+type NodeEvent_UintValue struct {
+	UintValue uint64 `protobuf:"varint,5,opt,name=uint_value,json=uintValue,proto3,oneof"`
+}
+type NodeEvent_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,6,opt,name=float_value,json=floatValue,proto3,oneof"`
+}
+type NodeEvent_BoolValue struct {
+	BoolValue bool `protobuf:"varint,7,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+func (*NodeEvent_StringValue) isNodeEvent_Value() {}
+func (*NodeEvent_IntValue) isNodeEvent_Value()    {}
+func (*NodeEvent_UintValue) isNodeEvent_Value()   {}
+func (*NodeEvent_FloatValue) isNodeEvent_Value()  {}
+func (*NodeEvent_BoolValue) isNodeEvent_Value()   {}
+
+func (m *NodeEvent) GetValue() isNodeEvent_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *NodeEvent) GetStringValue() string {
+	if x, ok := m.GetValue().(*NodeEvent_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *NodeEvent) GetIntValue() int64 {
+	if x, ok := m.GetValue().(*NodeEvent_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (m *NodeEvent) GetUintValue() uint64 {
+	if x, ok := m.GetValue().(*NodeEvent_UintValue); ok {
+		return x.UintValue
+	}
+	return 0
+}
+
+func (m *NodeEvent) GetFloatValue() float64 {
+	if x, ok := m.GetValue().(*NodeEvent_FloatValue); ok {
+		return x.FloatValue
+	}
+	return 0
+}
+
+func (m *NodeEvent) GetBoolValue() bool {
+	if x, ok := m.GetValue().(*NodeEvent_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*NodeEvent) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _NodeEvent_OneofMarshaler, _NodeEvent_OneofUnmarshaler, _NodeEvent_OneofSizer, []interface{}{
+		(*NodeEvent_StringValue)(nil),
+		(*NodeEvent_IntValue)(nil),
+		(*NodeEvent_UintValue)(nil),
+		(*NodeEvent_FloatValue)(nil),
+		(*NodeEvent_BoolValue)(nil),
+	}
+}
+
+func _NodeEvent_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*NodeEvent)
+	// value
+	switch x := m.Value.(type) {
+	case *NodeEvent_StringValue:
+		_ = b.EncodeVarint(3<<3 | proto.WireBytes)
+		_ = b.EncodeStringBytes(x.StringValue)
+	case *NodeEvent_IntValue:
+		_ = b.EncodeVarint(4<<3 | proto.WireVarint)
+		_ = b.EncodeVarint(uint64(x.IntValue))
+	case *NodeEvent_UintValue:
+		_ = b.EncodeVarint(5<<3 | proto.WireVarint)
+		_ = b.EncodeVarint(uint64(x.UintValue))
+	case *NodeEvent_FloatValue:
+		_ = b.EncodeVarint(6<<3 | proto.WireFixed64)
+		_ = b.EncodeFixed64(math.Float64bits(x.FloatValue))
+	case *NodeEvent_BoolValue:
+		t := uint64(0)
+		if x.BoolValue {
+			t = 1
+		}
+		_ = b.EncodeVarint(7<<3 | proto.WireVarint)
+		_ = b.EncodeVarint(t)
+	case nil:
+	default:
+		return fmt.Errorf("NodeEvent.Value has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _NodeEvent_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*NodeEvent)
+	switch tag {
+	case 3: // value.string_value
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Value = &NodeEvent_StringValue{x}
+		return true, err
+	case 4: // value.int_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Value = &NodeEvent_IntValue{int64(x)}
+		return true, err
+	case 5: // value.uint_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Value = &NodeEvent_UintValue{x}
+		return true, err
+	case 6: // value.float_value
+		if wire != proto.WireFixed64 {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeFixed64()
+		m.Value = &NodeEvent_FloatValue{math.Float64frombits(x)}
+		return true, err
+	case 7: // value.bool_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Value = &NodeEvent_BoolValue{x != 0}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _NodeEvent_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*NodeEvent)
+	// value
+	switch x := m.Value.(type) {
+	case *NodeEvent_StringValue:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.StringValue)))
+		n += len(x.StringValue)
+	case *NodeEvent_IntValue:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(x.IntValue))
+	case *NodeEvent_UintValue:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(x.UintValue))
+	case *NodeEvent_FloatValue:
+		n += 1 // tag and wire
+		n += 8
+	case *NodeEvent_BoolValue:
+		n += 1 // tag and wire
+		n += 1
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// ParseStreamResponse is a part of the reply to ParseRequest sent by ParseStream.
+type ParseStreamResponse struct {
+	// Events is the next batch of node events of the resulting UAST.
+	Events []*NodeEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Language that was automatically detected. Only set in the last message.
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// Errors is a list of parsing errors. Only set in the last message.
+	Errors []*ParseError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Warnings is a list of non-fatal issues reported by the parser. Only set in the last message.
+	Warnings             []string `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *VersionRequest) Reset()         { *m = VersionRequest{} }
-func (m *VersionRequest) String() string { return proto.CompactTextString(m) }
-func (*VersionRequest) ProtoMessage()    {}
-func (*VersionRequest) Descriptor() ([]byte, []int) {
+func (m *ParseStreamResponse) Reset()         { *m = ParseStreamResponse{} }
+func (m *ParseStreamResponse) String() string { return proto.CompactTextString(m) }
+func (*ParseStreamResponse) ProtoMessage()    {}
+func (*ParseStreamResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{5}
 }
-func (m *VersionRequest) XXX_Unmarshal(b []byte) error {
+func (m *ParseStreamResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *VersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ParseStreamResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_VersionRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ParseStreamResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -367,37 +728,41 @@ func (m *VersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, erro
 		return b[:n], nil
 	}
 }
-func (m *VersionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VersionRequest.Merge(m, src)
+func (m *ParseStreamResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseStreamResponse.Merge(m, src)
 }
-func (m *VersionRequest) XXX_Size() int {
+func (m *ParseStreamResponse) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *VersionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_VersionRequest.DiscardUnknown(m)
+func (m *ParseStreamResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseStreamResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_VersionRequest proto.InternalMessageInfo
+var xxx_messageInfo_ParseStreamResponse proto.InternalMessageInfo
 
-type VersionResponse struct {
-	Version              *Version `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+// Progress is a progress event of a parse request.
+type Progress struct {
+	// Percent is an estimated completion percentage of the whole request, in the range [0, 100].
+	Percent float32 `protobuf:"fixed32,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	// Phase is a name of the current parsing phase, e.g. "decoding", "native-parse" or "transforming".
+	Phase                string   `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *VersionResponse) Reset()         { *m = VersionResponse{} }
-func (m *VersionResponse) String() string { return proto.CompactTextString(m) }
-func (*VersionResponse) ProtoMessage()    {}
-func (*VersionResponse) Descriptor() ([]byte, []int) {
+func (m *Progress) Reset()         { *m = Progress{} }
+func (m *Progress) String() string { return proto.CompactTextString(m) }
+func (*Progress) ProtoMessage()    {}
+func (*Progress) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{6}
 }
-func (m *VersionResponse) XXX_Unmarshal(b []byte) error {
+func (m *Progress) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *VersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Progress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_VersionResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Progress.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -407,36 +772,41 @@ func (m *VersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, err
 		return b[:n], nil
 	}
 }
-func (m *VersionResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VersionResponse.Merge(m, src)
+func (m *Progress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Progress.Merge(m, src)
 }
-func (m *VersionResponse) XXX_Size() int {
+func (m *Progress) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *VersionResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_VersionResponse.DiscardUnknown(m)
+func (m *Progress) XXX_DiscardUnknown() {
+	xxx_messageInfo_Progress.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_VersionResponse proto.InternalMessageInfo
+var xxx_messageInfo_Progress proto.InternalMessageInfo
 
-type SupportedLanguagesRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+// ParseProgressResponse is a part of the reply to ParseRequest sent by ParseWithProgress.
+type ParseProgressResponse struct {
+	// Types that are valid to be assigned to Event:
+	//	*ParseProgressResponse_Progress
+	//	*ParseProgressResponse_Response
+	Event                isParseProgressResponse_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
 }
 
-func (m *SupportedLanguagesRequest) Reset()         { *m = SupportedLanguagesRequest{} }
-func (m *SupportedLanguagesRequest) String() string { return proto.CompactTextString(m) }
-func (*SupportedLanguagesRequest) ProtoMessage()    {}
-func (*SupportedLanguagesRequest) Descriptor() ([]byte, []int) {
+func (m *ParseProgressResponse) Reset()         { *m = ParseProgressResponse{} }
+func (m *ParseProgressResponse) String() string { return proto.CompactTextString(m) }
+func (*ParseProgressResponse) ProtoMessage()    {}
+func (*ParseProgressResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{7}
 }
-func (m *SupportedLanguagesRequest) XXX_Unmarshal(b []byte) error {
+func (m *ParseProgressResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SupportedLanguagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ParseProgressResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SupportedLanguagesRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ParseProgressResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -446,38 +816,154 @@ func (m *SupportedLanguagesRequest) XXX_Marshal(b []byte, deterministic bool) ([
 		return b[:n], nil
 	}
 }
-func (m *SupportedLanguagesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SupportedLanguagesRequest.Merge(m, src)
+func (m *ParseProgressResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseProgressResponse.Merge(m, src)
 }
-func (m *SupportedLanguagesRequest) XXX_Size() int {
+func (m *ParseProgressResponse) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *SupportedLanguagesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SupportedLanguagesRequest.DiscardUnknown(m)
+func (m *ParseProgressResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseProgressResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SupportedLanguagesRequest proto.InternalMessageInfo
+var xxx_messageInfo_ParseProgressResponse proto.InternalMessageInfo
 
-type SupportedLanguagesResponse struct {
-	// Languages is a list of driver manifests for each language supported by the server.
-	Languages            []*Manifest `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+type isParseProgressResponse_Event interface {
+	isParseProgressResponse_Event()
+	MarshalTo([]byte) (int, error)
+	ProtoSize() int
 }
 
-func (m *SupportedLanguagesResponse) Reset()         { *m = SupportedLanguagesResponse{} }
-func (m *SupportedLanguagesResponse) String() string { return proto.CompactTextString(m) }
-func (*SupportedLanguagesResponse) ProtoMessage()    {}
-func (*SupportedLanguagesResponse) Descriptor() ([]byte, []int) {
+type ParseProgressResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+type ParseProgressResponse_Response struct {
+	Response *ParseResponse `protobuf:"bytes,2,opt,name=response,proto3,oneof"`
+}
+
+func (*ParseProgressResponse_Progress) isParseProgressResponse_Event() {}
+func (*ParseProgressResponse_Response) isParseProgressResponse_Event() {}
+
+func (m *ParseProgressResponse) GetEvent() isParseProgressResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *ParseProgressResponse) GetProgress() *Progress {
+	if x, ok := m.GetEvent().(*ParseProgressResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (m *ParseProgressResponse) GetResponse() *ParseResponse {
+	if x, ok := m.GetEvent().(*ParseProgressResponse_Response); ok {
+		return x.Response
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*ParseProgressResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _ParseProgressResponse_OneofMarshaler, _ParseProgressResponse_OneofUnmarshaler, _ParseProgressResponse_OneofSizer, []interface{}{
+		(*ParseProgressResponse_Progress)(nil),
+		(*ParseProgressResponse_Response)(nil),
+	}
+}
+
+func _ParseProgressResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*ParseProgressResponse)
+	// event
+	switch x := m.Event.(type) {
+	case *ParseProgressResponse_Progress:
+		_ = b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Progress); err != nil {
+			return err
+		}
+	case *ParseProgressResponse_Response:
+		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Response); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("ParseProgressResponse.Event has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _ParseProgressResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*ParseProgressResponse)
+	switch tag {
+	case 1: // event.progress
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Progress)
+		err := b.DecodeMessage(msg)
+		m.Event = &ParseProgressResponse_Progress{msg}
+		return true, err
+	case 2: // event.response
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ParseResponse)
+		err := b.DecodeMessage(msg)
+		m.Event = &ParseProgressResponse_Response{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _ParseProgressResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*ParseProgressResponse)
+	// event
+	switch x := m.Event.(type) {
+	case *ParseProgressResponse_Progress:
+		s := proto.Size(x.Progress)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *ParseProgressResponse_Response:
+		s := proto.Size(x.Response)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type Version struct {
+	// Version of the driver. It is the same as driver_version and is kept for older clients.
+	Version string    `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Build   time.Time `protobuf:"bytes,2,opt,name=build,proto3,stdtime" json:"build"`
+	// DriverVersion is a version of the driver.
+	DriverVersion string `protobuf:"bytes,3,opt,name=driver_version,json=driverVersion,proto3" json:"driver_version,omitempty"`
+	// LanguageVersion is a version of the native parser or language runtime used by the driver, if known.
+	LanguageVersion      string   `protobuf:"bytes,4,opt,name=language_version,json=languageVersion,proto3" json:"language_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{8}
 }
-func (m *SupportedLanguagesResponse) XXX_Unmarshal(b []byte) error {
+func (m *Version) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SupportedLanguagesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SupportedLanguagesResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Version.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -487,45 +973,48 @@ func (m *SupportedLanguagesResponse) XXX_Marshal(b []byte, deterministic bool) (
 		return b[:n], nil
 	}
 }
-func (m *SupportedLanguagesResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SupportedLanguagesResponse.Merge(m, src)
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
 }
-func (m *SupportedLanguagesResponse) XXX_Size() int {
+func (m *Version) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *SupportedLanguagesResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SupportedLanguagesResponse.DiscardUnknown(m)
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SupportedLanguagesResponse proto.InternalMessageInfo
+var xxx_messageInfo_Version proto.InternalMessageInfo
 
-// ErrorDetails adds bblfsh-specific information to gRPC errors (google.rpc.Status).
-type ErrorDetails struct {
-	// Types that are valid to be assigned to Reason:
-	//	*ErrorDetails_InvalidFileEncoding
-	//	*ErrorDetails_UnsupportedLanguage
-	//	*ErrorDetails_CannotDetectLanguage
-	//	*ErrorDetails_UnsupportedTransformMode
-	//	*ErrorDetails_TransformFailure
-	//	*ErrorDetails_DriverFailure
-	Reason               isErrorDetails_Reason `protobuf_oneof:"reason"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+type Manifest struct {
+	// Name is a human-readable language or driver name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Language is a Babelfish language identifier.
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// Aliases is a list of alternative language identifiers from Enry/Linguist.
+	Aliases []string `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// Version of the language driver.
+	Version *Version `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Status of the driver development.
+	Status DevelopmentStatus `protobuf:"varint,5,opt,name=status,proto3,enum=gopkg.in.bblfsh.sdk.v2.protocol.DevelopmentStatus" json:"status,omitempty"`
+	// Features this driver supports.
+	Features             []string `protobuf:"bytes,6,rep,name=features,proto3" json:"features,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ErrorDetails) Reset()         { *m = ErrorDetails{} }
-func (m *ErrorDetails) String() string { return proto.CompactTextString(m) }
-func (*ErrorDetails) ProtoMessage()    {}
-func (*ErrorDetails) Descriptor() ([]byte, []int) {
+func (m *Manifest) Reset()         { *m = Manifest{} }
+func (m *Manifest) String() string { return proto.CompactTextString(m) }
+func (*Manifest) ProtoMessage()    {}
+func (*Manifest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_521003751d596b5e, []int{9}
 }
-func (m *ErrorDetails) XXX_Unmarshal(b []byte) error {
+func (m *Manifest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ErrorDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Manifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ErrorDetails.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Manifest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -535,102 +1024,309 @@ func (m *ErrorDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *ErrorDetails) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ErrorDetails.Merge(m, src)
+func (m *Manifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Manifest.Merge(m, src)
 }
-func (m *ErrorDetails) XXX_Size() int {
+func (m *Manifest) XXX_Size() int {
 	return m.ProtoSize()
 }
-func (m *ErrorDetails) XXX_DiscardUnknown() {
-	xxx_messageInfo_ErrorDetails.DiscardUnknown(m)
+func (m *Manifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_Manifest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ErrorDetails proto.InternalMessageInfo
+var xxx_messageInfo_Manifest proto.InternalMessageInfo
 
-type isErrorDetails_Reason interface {
-	isErrorDetails_Reason()
-	MarshalTo([]byte) (int, error)
-	ProtoSize() int
+type VersionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type ErrorDetails_InvalidFileEncoding struct {
-	InvalidFileEncoding bool `protobuf:"varint,1,opt,name=invalid_file_encoding,json=invalidFileEncoding,proto3,oneof"`
+func (m *VersionRequest) Reset()         { *m = VersionRequest{} }
+func (m *VersionRequest) String() string { return proto.CompactTextString(m) }
+func (*VersionRequest) ProtoMessage()    {}
+func (*VersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{10}
 }
-type ErrorDetails_UnsupportedLanguage struct {
-	UnsupportedLanguage string `protobuf:"bytes,2,opt,name=unsupported_language,json=unsupportedLanguage,proto3,oneof"`
+func (m *VersionRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-type ErrorDetails_CannotDetectLanguage struct {
-	CannotDetectLanguage bool `protobuf:"varint,3,opt,name=cannot_detect_language,json=cannotDetectLanguage,proto3,oneof"`
+func (m *VersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VersionRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-type ErrorDetails_UnsupportedTransformMode struct {
-	UnsupportedTransformMode bool `protobuf:"varint,4,opt,name=unsupported_transform_mode,json=unsupportedTransformMode,proto3,oneof"`
+func (m *VersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionRequest.Merge(m, src)
 }
-type ErrorDetails_TransformFailure struct {
-	TransformFailure bool `protobuf:"varint,5,opt,name=transform_failure,json=transformFailure,proto3,oneof"`
+func (m *VersionRequest) XXX_Size() int {
+	return m.ProtoSize()
 }
-type ErrorDetails_DriverFailure struct {
-	DriverFailure bool `protobuf:"varint,6,opt,name=driver_failure,json=driverFailure,proto3,oneof"`
+func (m *VersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionRequest.DiscardUnknown(m)
 }
 
-func (*ErrorDetails_InvalidFileEncoding) isErrorDetails_Reason()      {}
-func (*ErrorDetails_UnsupportedLanguage) isErrorDetails_Reason()      {}
-func (*ErrorDetails_CannotDetectLanguage) isErrorDetails_Reason()     {}
-func (*ErrorDetails_UnsupportedTransformMode) isErrorDetails_Reason() {}
-func (*ErrorDetails_TransformFailure) isErrorDetails_Reason()         {}
-func (*ErrorDetails_DriverFailure) isErrorDetails_Reason()            {}
+var xxx_messageInfo_VersionRequest proto.InternalMessageInfo
 
-func (m *ErrorDetails) GetReason() isErrorDetails_Reason {
-	if m != nil {
-		return m.Reason
-	}
-	return nil
+type VersionResponse struct {
+	Version              *Version `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ErrorDetails) GetInvalidFileEncoding() bool {
-	if x, ok := m.GetReason().(*ErrorDetails_InvalidFileEncoding); ok {
-		return x.InvalidFileEncoding
-	}
-	return false
+func (m *VersionResponse) Reset()         { *m = VersionResponse{} }
+func (m *VersionResponse) String() string { return proto.CompactTextString(m) }
+func (*VersionResponse) ProtoMessage()    {}
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{11}
 }
-
-func (m *ErrorDetails) GetUnsupportedLanguage() string {
-	if x, ok := m.GetReason().(*ErrorDetails_UnsupportedLanguage); ok {
-		return x.UnsupportedLanguage
-	}
-	return ""
+func (m *VersionResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *ErrorDetails) GetCannotDetectLanguage() bool {
-	if x, ok := m.GetReason().(*ErrorDetails_CannotDetectLanguage); ok {
-		return x.CannotDetectLanguage
+func (m *VersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VersionResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return false
 }
-
-func (m *ErrorDetails) GetUnsupportedTransformMode() bool {
-	if x, ok := m.GetReason().(*ErrorDetails_UnsupportedTransformMode); ok {
-		return x.UnsupportedTransformMode
-	}
-	return false
+func (m *VersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionResponse.Merge(m, src)
 }
-
-func (m *ErrorDetails) GetTransformFailure() bool {
-	if x, ok := m.GetReason().(*ErrorDetails_TransformFailure); ok {
-		return x.TransformFailure
-	}
-	return false
+func (m *VersionResponse) XXX_Size() int {
+	return m.ProtoSize()
 }
-
-func (m *ErrorDetails) GetDriverFailure() bool {
-	if x, ok := m.GetReason().(*ErrorDetails_DriverFailure); ok {
-		return x.DriverFailure
-	}
-	return false
+func (m *VersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionResponse.DiscardUnknown(m)
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*ErrorDetails) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _ErrorDetails_OneofMarshaler, _ErrorDetails_OneofUnmarshaler, _ErrorDetails_OneofSizer, []interface{}{
+var xxx_messageInfo_VersionResponse proto.InternalMessageInfo
+
+type SupportedLanguagesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupportedLanguagesRequest) Reset()         { *m = SupportedLanguagesRequest{} }
+func (m *SupportedLanguagesRequest) String() string { return proto.CompactTextString(m) }
+func (*SupportedLanguagesRequest) ProtoMessage()    {}
+func (*SupportedLanguagesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{12}
+}
+func (m *SupportedLanguagesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SupportedLanguagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SupportedLanguagesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SupportedLanguagesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupportedLanguagesRequest.Merge(m, src)
+}
+func (m *SupportedLanguagesRequest) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *SupportedLanguagesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupportedLanguagesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupportedLanguagesRequest proto.InternalMessageInfo
+
+type SupportedLanguagesResponse struct {
+	// Languages is a list of driver manifests for each language supported by the server.
+	Languages            []*Manifest `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SupportedLanguagesResponse) Reset()         { *m = SupportedLanguagesResponse{} }
+func (m *SupportedLanguagesResponse) String() string { return proto.CompactTextString(m) }
+func (*SupportedLanguagesResponse) ProtoMessage()    {}
+func (*SupportedLanguagesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{13}
+}
+func (m *SupportedLanguagesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SupportedLanguagesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SupportedLanguagesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SupportedLanguagesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupportedLanguagesResponse.Merge(m, src)
+}
+func (m *SupportedLanguagesResponse) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *SupportedLanguagesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupportedLanguagesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupportedLanguagesResponse proto.InternalMessageInfo
+
+// ErrorDetails adds bblfsh-specific information to gRPC errors (google.rpc.Status).
+type ErrorDetails struct {
+	// Types that are valid to be assigned to Reason:
+	//	*ErrorDetails_InvalidFileEncoding
+	//	*ErrorDetails_UnsupportedLanguage
+	//	*ErrorDetails_CannotDetectLanguage
+	//	*ErrorDetails_UnsupportedTransformMode
+	//	*ErrorDetails_TransformFailure
+	//	*ErrorDetails_DriverFailure
+	Reason               isErrorDetails_Reason `protobuf_oneof:"reason"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ErrorDetails) Reset()         { *m = ErrorDetails{} }
+func (m *ErrorDetails) String() string { return proto.CompactTextString(m) }
+func (*ErrorDetails) ProtoMessage()    {}
+func (*ErrorDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_521003751d596b5e, []int{14}
+}
+func (m *ErrorDetails) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ErrorDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ErrorDetails.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ErrorDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorDetails.Merge(m, src)
+}
+func (m *ErrorDetails) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *ErrorDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorDetails proto.InternalMessageInfo
+
+type isErrorDetails_Reason interface {
+	isErrorDetails_Reason()
+	MarshalTo([]byte) (int, error)
+	ProtoSize() int
+}
+
+type ErrorDetails_InvalidFileEncoding struct {
+	InvalidFileEncoding bool `protobuf:"varint,1,opt,name=invalid_file_encoding,json=invalidFileEncoding,proto3,oneof"`
+}
+type ErrorDetails_UnsupportedLanguage struct {
+	UnsupportedLanguage string `protobuf:"bytes,2,opt,name=unsupported_language,json=unsupportedLanguage,proto3,oneof"`
+}
+type ErrorDetails_CannotDetectLanguage struct {
+	CannotDetectLanguage bool `protobuf:"varint,3,opt,name=cannot_detect_language,json=cannotDetectLanguage,proto3,oneof"`
+}
+type ErrorDetails_UnsupportedTransformMode struct {
+	UnsupportedTransformMode bool `protobuf:"varint,4,opt,name=unsupported_transform_mode,json=unsupportedTransformMode,proto3,oneof"`
+}
+type ErrorDetails_TransformFailure struct {
+	TransformFailure bool `protobuf:"varint,5,opt,name=transform_failure,json=transformFailure,proto3,oneof"`
+}
+type ErrorDetails_DriverFailure struct {
+	DriverFailure bool `protobuf:"varint,6,opt,name=driver_failure,json=driverFailure,proto3,oneof"`
+}
+
+func (*ErrorDetails_InvalidFileEncoding) isErrorDetails_Reason()      {}
+func (*ErrorDetails_UnsupportedLanguage) isErrorDetails_Reason()      {}
+func (*ErrorDetails_CannotDetectLanguage) isErrorDetails_Reason()     {}
+func (*ErrorDetails_UnsupportedTransformMode) isErrorDetails_Reason() {}
+func (*ErrorDetails_TransformFailure) isErrorDetails_Reason()         {}
+func (*ErrorDetails_DriverFailure) isErrorDetails_Reason()            {}
+
+func (m *ErrorDetails) GetReason() isErrorDetails_Reason {
+	if m != nil {
+		return m.Reason
+	}
+	return nil
+}
+
+func (m *ErrorDetails) GetInvalidFileEncoding() bool {
+	if x, ok := m.GetReason().(*ErrorDetails_InvalidFileEncoding); ok {
+		return x.InvalidFileEncoding
+	}
+	return false
+}
+
+func (m *ErrorDetails) GetUnsupportedLanguage() string {
+	if x, ok := m.GetReason().(*ErrorDetails_UnsupportedLanguage); ok {
+		return x.UnsupportedLanguage
+	}
+	return ""
+}
+
+func (m *ErrorDetails) GetCannotDetectLanguage() bool {
+	if x, ok := m.GetReason().(*ErrorDetails_CannotDetectLanguage); ok {
+		return x.CannotDetectLanguage
+	}
+	return false
+}
+
+func (m *ErrorDetails) GetUnsupportedTransformMode() bool {
+	if x, ok := m.GetReason().(*ErrorDetails_UnsupportedTransformMode); ok {
+		return x.UnsupportedTransformMode
+	}
+	return false
+}
+
+func (m *ErrorDetails) GetTransformFailure() bool {
+	if x, ok := m.GetReason().(*ErrorDetails_TransformFailure); ok {
+		return x.TransformFailure
+	}
+	return false
+}
+
+func (m *ErrorDetails) GetDriverFailure() bool {
+	if x, ok := m.GetReason().(*ErrorDetails_DriverFailure); ok {
+		return x.DriverFailure
+	}
+	return false
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*ErrorDetails) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _ErrorDetails_OneofMarshaler, _ErrorDetails_OneofUnmarshaler, _ErrorDetails_OneofSizer, []interface{}{
 		(*ErrorDetails_InvalidFileEncoding)(nil),
 		(*ErrorDetails_UnsupportedLanguage)(nil),
 		(*ErrorDetails_CannotDetectLanguage)(nil),
@@ -773,16 +1469,36 @@ func (*ErrorDetails) XXX_MessageName() string {
 	return "gopkg.in.bblfsh.sdk.v2.protocol.ErrorDetails"
 }
 func init() {
+	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Encoding", Encoding_name, Encoding_value)
+	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Encoding", Encoding_name, Encoding_value)
+	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Format", Format_name, Format_value)
+	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Format", Format_name, Format_value)
 	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Mode", Mode_name, Mode_value)
 	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Mode", Mode_name, Mode_value)
+	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Severity", Severity_name, Severity_value)
+	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.Severity", Severity_name, Severity_value)
+	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.NodeEventType", NodeEventType_name, NodeEventType_value)
+	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.NodeEventType", NodeEventType_name, NodeEventType_value)
 	proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.DevelopmentStatus", DevelopmentStatus_name, DevelopmentStatus_value)
 	golang_proto.RegisterEnum("gopkg.in.bblfsh.sdk.v2.protocol.DevelopmentStatus", DevelopmentStatus_name, DevelopmentStatus_value)
 	proto.RegisterType((*ParseRequest)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseRequest")
 	golang_proto.RegisterType((*ParseRequest)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseRequest")
+	proto.RegisterMapType((map[string]string)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseRequest.OptionsEntry")
+	golang_proto.RegisterMapType((map[string]string)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseRequest.OptionsEntry")
 	proto.RegisterType((*ParseResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseResponse")
 	golang_proto.RegisterType((*ParseResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseResponse")
 	proto.RegisterType((*ParseError)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseError")
 	golang_proto.RegisterType((*ParseError)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseError")
+	proto.RegisterType((*ErrorPosition)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ErrorPosition")
+	golang_proto.RegisterType((*ErrorPosition)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ErrorPosition")
+	proto.RegisterType((*NodeEvent)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.NodeEvent")
+	golang_proto.RegisterType((*NodeEvent)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.NodeEvent")
+	proto.RegisterType((*ParseStreamResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseStreamResponse")
+	golang_proto.RegisterType((*ParseStreamResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseStreamResponse")
+	proto.RegisterType((*Progress)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.Progress")
+	golang_proto.RegisterType((*Progress)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.Progress")
+	proto.RegisterType((*ParseProgressResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseProgressResponse")
+	golang_proto.RegisterType((*ParseProgressResponse)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.ParseProgressResponse")
 	proto.RegisterType((*Version)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.Version")
 	golang_proto.RegisterType((*Version)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.Version")
 	proto.RegisterType((*Manifest)(nil), "gopkg.in.bblfsh.sdk.v2.protocol.Manifest")
@@ -803,71 +1519,124 @@ func init() { proto.RegisterFile("driver.proto", fileDescriptor_521003751d596b5e
 func init() { golang_proto.RegisterFile("driver.proto", fileDescriptor_521003751d596b5e) }
 
 var fileDescriptor_521003751d596b5e = []byte{
-	// 1019 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcf, 0x6f, 0xe3, 0xc4,
-	0x17, 0x8f, 0xd3, 0x34, 0x4d, 0x5e, 0xd3, 0xae, 0x77, 0xbe, 0xfb, 0xad, 0x8c, 0x41, 0xa9, 0xb1,
-	0x84, 0x28, 0x8b, 0xea, 0xa2, 0x2c, 0x42, 0xa2, 0x48, 0x48, 0x4e, 0xe3, 0x6e, 0x8b, 0x92, 0x34,
-	0x72, 0xd2, 0x1e, 0xb8, 0x44, 0x93, 0x78, 0x92, 0x5a, 0xeb, 0x78, 0x82, 0x67, 0x1c, 0x71, 0xe4,
-	0xc0, 0x01, 0x45, 0x42, 0xda, 0x3b, 0x8a, 0x40, 0xfc, 0x25, 0x1c, 0x7b, 0xe4, 0xca, 0x81, 0x5f,
-	0xdd, 0x7f, 0x04, 0x79, 0xc6, 0x4e, 0xba, 0x5a, 0xd8, 0x54, 0x7b, 0x9b, 0x37, 0x9f, 0xf7, 0x99,
-	0xf7, 0x99, 0xf7, 0x0b, 0x2a, 0x5e, 0xe4, 0xcf, 0x48, 0x64, 0x4d, 0x23, 0xca, 0x29, 0xda, 0x1f,
-	0xd3, 0xe9, 0xb3, 0xb1, 0xe5, 0x87, 0xd6, 0x60, 0x10, 0x8c, 0xd8, 0xb5, 0xc5, 0xbc, 0x67, 0xd6,
-	0xac, 0x26, 0xd1, 0x21, 0x0d, 0xf4, 0xc3, 0xb1, 0xcf, 0xaf, 0xe3, 0x81, 0x35, 0xa4, 0x93, 0xa3,
-	0x31, 0x1d, 0xd3, 0x23, 0x81, 0x0c, 0xe2, 0x91, 0xb0, 0x84, 0x21, 0x4e, 0x92, 0xa1, 0xef, 0x8f,
-	0x29, 0x1d, 0x07, 0x64, 0xe5, 0xc5, 0xfd, 0x09, 0x61, 0x1c, 0x4f, 0xa6, 0xd2, 0xc1, 0xfc, 0x41,
-	0x81, 0x4a, 0x07, 0x47, 0x8c, 0xb8, 0xe4, 0xab, 0x98, 0x30, 0x8e, 0x34, 0xd8, 0x1a, 0xd2, 0x90,
-	0x93, 0x90, 0x6b, 0x8a, 0xa1, 0x1c, 0x94, 0xdd, 0xcc, 0x44, 0x3a, 0x94, 0x02, 0x1c, 0x8e, 0x63,
-	0x3c, 0x26, 0x5a, 0x5e, 0x40, 0x4b, 0x3b, 0xc1, 0x46, 0x7e, 0x40, 0x42, 0x3c, 0x21, 0xda, 0x86,
-	0xc4, 0x32, 0x1b, 0x7d, 0x0a, 0x85, 0x09, 0xf5, 0x88, 0x56, 0x30, 0x94, 0x83, 0xdd, 0xda, 0x7b,
-	0xd6, 0x9a, 0x2f, 0x5a, 0x2d, 0xea, 0x11, 0x57, 0x50, 0xcc, 0x6f, 0x15, 0xd8, 0x49, 0xd5, 0xb1,
-	0x29, 0x0d, 0x19, 0x41, 0x08, 0x0a, 0x31, 0x66, 0x52, 0x5b, 0xc5, 0x15, 0xe7, 0xd7, 0x0a, 0x3b,
-	0x81, 0x22, 0x89, 0x22, 0x1a, 0x31, 0x6d, 0xc3, 0xd8, 0x38, 0xd8, 0xae, 0x7d, 0xb8, 0x36, 0xbc,
-	0x88, 0xe7, 0x24, 0x1c, 0x37, 0xa5, 0x9a, 0x06, 0xc0, 0xea, 0x36, 0x91, 0xc0, 0xc9, 0xd7, 0x59,
-	0x7a, 0xc4, 0xd9, 0xec, 0xc3, 0xd6, 0x15, 0x89, 0x98, 0x4f, 0xc3, 0x24, 0x81, 0x33, 0x79, 0xcc,
-	0x12, 0x98, 0x9a, 0xe8, 0x18, 0x36, 0x07, 0xb1, 0x1f, 0x78, 0x42, 0xe4, 0x76, 0x4d, 0xb7, 0x64,
-	0x71, 0xac, 0xac, 0x38, 0x56, 0x2f, 0x2b, 0x4e, 0xbd, 0x74, 0xf3, 0xc7, 0x7e, 0xee, 0xf9, 0x9f,
-	0xfb, 0x8a, 0x2b, 0x29, 0xe6, 0x37, 0x79, 0x28, 0xb5, 0x70, 0xe8, 0x8f, 0x92, 0x1a, 0x21, 0x28,
-	0x88, 0x4c, 0xa7, 0x0a, 0x44, 0x96, 0x5f, 0x97, 0x04, 0x0d, 0xb6, 0x70, 0xe0, 0x63, 0x46, 0x64,
-	0x16, 0xca, 0x6e, 0x66, 0xa2, 0xfa, 0x4a, 0x6c, 0x41, 0x88, 0x3a, 0x58, 0x9b, 0x9f, 0xf4, 0x9f,
-	0xab, 0x6f, 0x7d, 0x01, 0x45, 0xc6, 0x31, 0x8f, 0x99, 0xb6, 0x29, 0x2a, 0x5c, 0x5b, 0xfb, 0x44,
-	0x83, 0xcc, 0x48, 0x40, 0xa7, 0x13, 0x12, 0xf2, 0xae, 0x60, 0xba, 0xe9, 0x0b, 0xa2, 0x8f, 0x08,
-	0xe6, 0x71, 0x44, 0x98, 0x56, 0x14, 0x52, 0x97, 0xb6, 0xa9, 0xc2, 0x6e, 0x16, 0x5b, 0xf6, 0xaa,
-	0x79, 0x09, 0x0f, 0x96, 0x37, 0x69, 0x7f, 0xd4, 0x5f, 0xce, 0xfe, 0x9b, 0x7c, 0xc8, 0x7c, 0x1b,
-	0xde, 0xea, 0xc6, 0xd3, 0x29, 0x8d, 0x38, 0xf1, 0x9a, 0x69, 0x0e, 0x59, 0x16, 0x93, 0x80, 0xfe,
-	0x6f, 0x60, 0x1a, 0xfe, 0x29, 0x94, 0xb3, 0xac, 0x33, 0x4d, 0x11, 0x1d, 0xf7, 0xc1, 0xfa, 0x86,
-	0x4f, 0xeb, 0xea, 0xae, 0xb8, 0xe6, 0x6f, 0x79, 0xa8, 0x88, 0x76, 0x6b, 0x10, 0x8e, 0xfd, 0x80,
-	0xa1, 0x8f, 0xe1, 0xff, 0x7e, 0x38, 0xc3, 0x81, 0xef, 0xf5, 0x93, 0xc9, 0xea, 0x93, 0x70, 0x48,
-	0x3d, 0x3f, 0x1c, 0x8b, 0x6f, 0x96, 0xce, 0x72, 0xee, 0xff, 0x52, 0xf8, 0xd4, 0x0f, 0x88, 0x93,
-	0x82, 0xe8, 0x09, 0x3c, 0x8a, 0x43, 0x96, 0xe9, 0xed, 0xbf, 0xdc, 0x21, 0x09, 0xe9, 0x0e, 0x9a,
-	0xfd, 0x06, 0x7d, 0x02, 0x7b, 0x43, 0x1c, 0x86, 0x94, 0xf7, 0x3d, 0xc2, 0xc9, 0x90, 0xaf, 0x68,
-	0x1b, 0x69, 0xac, 0x47, 0x12, 0x6f, 0x08, 0x78, 0xc9, 0xfb, 0x1c, 0xf4, 0xbb, 0xc1, 0x78, 0x84,
-	0x43, 0x36, 0xa2, 0xd1, 0xa4, 0xbf, 0x1c, 0xff, 0x84, 0xab, 0xdd, 0xf1, 0xe9, 0x65, 0x2e, 0xc9,
-	0xcc, 0xa3, 0x43, 0x78, 0xb8, 0xe2, 0x8c, 0xb0, 0x1f, 0xc4, 0x11, 0x11, 0x3d, 0x95, 0xd0, 0xd4,
-	0x25, 0x74, 0x2a, 0x11, 0xf4, 0x3e, 0xec, 0xca, 0xdd, 0xb9, 0xf4, 0x2d, 0xa6, 0xbe, 0x3b, 0xf2,
-	0x3e, 0x75, 0x3c, 0x2e, 0x7c, 0xf7, 0xf3, 0xbe, 0x52, 0x2f, 0x41, 0x31, 0x22, 0x98, 0xd1, 0xf0,
-	0xf1, 0x8f, 0x0a, 0x14, 0x44, 0xc0, 0x77, 0xa1, 0xd2, 0x70, 0x4e, 0xed, 0xcb, 0x66, 0xaf, 0xdf,
-	0xba, 0x68, 0x38, 0x6a, 0x4e, 0x7f, 0x30, 0x5f, 0x18, 0xdb, 0x0d, 0x32, 0xc2, 0x71, 0xc0, 0x85,
-	0xcb, 0x1e, 0x14, 0xdb, 0x76, 0xef, 0xfc, 0xca, 0x51, 0x15, 0x1d, 0xe6, 0x0b, 0xa3, 0xd8, 0xc6,
-	0xdc, 0x9f, 0x11, 0x64, 0x42, 0xa5, 0xe3, 0x3a, 0x1d, 0xf7, 0xe2, 0xc4, 0xe9, 0x76, 0x9d, 0x86,
-	0x9a, 0xd7, 0xd5, 0xf9, 0xc2, 0xa8, 0x74, 0x22, 0x32, 0x8d, 0xe8, 0x90, 0x30, 0x46, 0x3c, 0xf4,
-	0x0e, 0x94, 0xed, 0x76, 0xfb, 0xa2, 0x67, 0xf7, 0x9c, 0x86, 0x5a, 0xd0, 0x77, 0xe6, 0x0b, 0xa3,
-	0x6c, 0x27, 0x79, 0xc3, 0x9c, 0x78, 0x49, 0xab, 0x77, 0x9d, 0x96, 0xdd, 0xee, 0x9d, 0x9f, 0xa8,
-	0x25, 0xbd, 0x32, 0x5f, 0x18, 0xa5, 0x2e, 0x99, 0xe0, 0x90, 0xfb, 0xc3, 0xc7, 0xbf, 0x2b, 0xf0,
-	0xf0, 0x95, 0x21, 0x41, 0xd5, 0x44, 0xee, 0x55, 0xff, 0xbc, 0x6d, 0x9f, 0x08, 0x45, 0x39, 0xc9,
-	0x3a, 0x0f, 0xf1, 0x50, 0x68, 0x4a, 0xf1, 0x4e, 0xd3, 0x6e, 0xb7, 0xcf, 0xdb, 0x4f, 0x55, 0x45,
-	0xe2, 0x9d, 0x00, 0x87, 0x61, 0xd2, 0x0c, 0x19, 0xee, 0x3a, 0x76, 0xb3, 0x73, 0x66, 0xab, 0xf9,
-	0x14, 0x8f, 0x88, 0x1d, 0x4c, 0xaf, 0x31, 0xd2, 0xa0, 0x9c, 0xe0, 0x12, 0xdc, 0xd0, 0xcb, 0xf3,
-	0x85, 0xb1, 0x29, 0x91, 0x3d, 0x28, 0x25, 0x48, 0xdd, 0xe9, 0xd9, 0x6a, 0x41, 0x2f, 0xcd, 0x17,
-	0x46, 0xa1, 0x4e, 0x38, 0x46, 0x3a, 0x40, 0x72, 0xdf, 0xed, 0xd9, 0xf5, 0xa6, 0xa3, 0x6e, 0xca,
-	0x0c, 0x75, 0x39, 0x1e, 0x04, 0x24, 0xc3, 0x5a, 0x76, 0xef, 0xd2, 0x75, 0xd4, 0xa2, 0xc4, 0x5a,
-	0x62, 0x96, 0x6b, 0x53, 0x28, 0x36, 0x44, 0x89, 0xd0, 0x08, 0x36, 0xc5, 0x6a, 0x45, 0x87, 0xf7,
-	0x5b, 0xcc, 0xe9, 0x18, 0xea, 0xd6, 0x7d, 0xdd, 0xe5, 0x60, 0xd6, 0x9e, 0xe7, 0x01, 0x64, 0xc8,
-	0x33, 0xca, 0x38, 0x8a, 0x60, 0xa7, 0x4b, 0xa2, 0x19, 0x89, 0xb2, 0xad, 0x7d, 0x74, 0xef, 0x35,
-	0x91, 0x0a, 0xf8, 0xe8, 0xfe, 0x84, 0x74, 0x37, 0x7c, 0xaf, 0x00, 0x7a, 0x75, 0x75, 0xa0, 0xe3,
-	0xb5, 0x0f, 0xfd, 0xe7, 0x32, 0xd2, 0x3f, 0x7b, 0x23, 0xae, 0xd4, 0x53, 0x37, 0x6f, 0xfe, 0xae,
-	0xe6, 0x6e, 0x6e, 0xab, 0xca, 0xaf, 0xb7, 0x55, 0xe5, 0xaf, 0xdb, 0x6a, 0xee, 0xa7, 0x17, 0x55,
-	0xe5, 0x97, 0x17, 0x55, 0xe5, 0xcb, 0x52, 0x46, 0x1f, 0x14, 0xc5, 0xe9, 0xc9, 0x3f, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0xf2, 0x4f, 0xc7, 0x15, 0xa6, 0x08, 0x00, 0x00,
+	// 1863 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcf, 0x6f, 0x23, 0x49,
+	0xf5, 0x77, 0xdb, 0x8e, 0xd3, 0x7e, 0xb6, 0x93, 0x9e, 0x9a, 0xd9, 0x91, 0xbf, 0xfd, 0x85, 0xc4,
+	0xe3, 0xd1, 0x6a, 0x32, 0x41, 0xe3, 0x99, 0xcd, 0x8e, 0xa2, 0x25, 0x48, 0xa0, 0x76, 0xdc, 0x99,
+	0x24, 0x72, 0x6c, 0xab, 0xdc, 0xc9, 0x68, 0x11, 0x92, 0xd5, 0xb1, 0xcb, 0x4e, 0xef, 0x74, 0xba,
+	0x4d, 0x77, 0x39, 0x43, 0x24, 0x0e, 0xdc, 0x40, 0x96, 0x90, 0x56, 0xe2, 0x6c, 0x81, 0xf8, 0x0f,
+	0x10, 0x07, 0xae, 0x48, 0x5c, 0x46, 0xe2, 0xc2, 0x05, 0x24, 0x0e, 0x0c, 0x90, 0xe5, 0x0f, 0x41,
+	0xf5, 0xab, 0xe3, 0x30, 0xcb, 0xc6, 0xb3, 0x17, 0x6e, 0x55, 0xf5, 0x3e, 0x9f, 0xf7, 0xba, 0xde,
+	0x8f, 0x7a, 0xaf, 0xa1, 0x38, 0x88, 0xbc, 0x0b, 0x12, 0xd5, 0xc6, 0x51, 0x48, 0x43, 0xb4, 0x3e,
+	0x0a, 0xc7, 0xaf, 0x46, 0x35, 0x2f, 0xa8, 0x9d, 0x9e, 0xfa, 0xc3, 0xf8, 0xac, 0x16, 0x0f, 0x5e,
+	0xd5, 0x2e, 0xb6, 0x84, 0xb4, 0x1f, 0xfa, 0xe6, 0x93, 0x91, 0x47, 0xcf, 0x26, 0xa7, 0xb5, 0x7e,
+	0x78, 0xfe, 0x74, 0x14, 0x8e, 0xc2, 0xa7, 0x5c, 0x72, 0x3a, 0x19, 0xf2, 0x1d, 0xdf, 0xf0, 0x95,
+	0x60, 0x98, 0xeb, 0xa3, 0x30, 0x1c, 0xf9, 0xe4, 0x1a, 0x45, 0xbd, 0x73, 0x12, 0x53, 0xf7, 0x7c,
+	0x2c, 0x00, 0xd5, 0x9f, 0x66, 0xa1, 0xd8, 0x71, 0xa3, 0x98, 0x60, 0xf2, 0xc3, 0x09, 0x89, 0x29,
+	0x2a, 0xc3, 0x72, 0x3f, 0x0c, 0x28, 0x09, 0x68, 0x59, 0xab, 0x68, 0x1b, 0x79, 0xac, 0xb6, 0xc8,
+	0x04, 0xdd, 0x77, 0x83, 0xd1, 0xc4, 0x1d, 0x91, 0x72, 0x9a, 0x8b, 0x92, 0x3d, 0x93, 0x0d, 0x3d,
+	0x9f, 0x04, 0xee, 0x39, 0x29, 0x67, 0x84, 0x4c, 0xed, 0xd1, 0xb7, 0x21, 0x7b, 0x1e, 0x0e, 0x48,
+	0x39, 0x5b, 0xd1, 0x36, 0x56, 0xb6, 0x3e, 0xac, 0xdd, 0x72, 0xc5, 0xda, 0x51, 0x38, 0x20, 0x98,
+	0x53, 0x90, 0x03, 0xcb, 0xe1, 0x98, 0x7a, 0x61, 0x10, 0x97, 0x97, 0x2a, 0x99, 0x8d, 0xc2, 0xd6,
+	0xce, 0xad, 0xec, 0xf9, 0xcb, 0xd4, 0xda, 0x82, 0x6c, 0x07, 0x34, 0xba, 0xc4, 0x4a, 0x15, 0xfa,
+	0x1e, 0xe4, 0x86, 0x61, 0x74, 0xee, 0xd2, 0x72, 0x8e, 0x7f, 0xd2, 0xa3, 0x5b, 0x95, 0xee, 0x71,
+	0x38, 0x96, 0x34, 0x64, 0x83, 0x4e, 0x82, 0x7e, 0x38, 0xf0, 0x82, 0x51, 0x79, 0x99, 0xab, 0x78,
+	0x7c, 0xab, 0x0a, 0x5b, 0x12, 0x70, 0x42, 0x45, 0x4f, 0xa1, 0x20, 0x7d, 0xdb, 0x9b, 0x44, 0x5e,
+	0x59, 0x67, 0x7e, 0xab, 0xaf, 0x5c, 0xbd, 0x5d, 0x87, 0x5d, 0x71, 0x7c, 0x8c, 0x0f, 0x30, 0x48,
+	0xc8, 0x71, 0xe4, 0xa1, 0x75, 0x28, 0x44, 0xee, 0xeb, 0x9e, 0x8a, 0x4f, 0xbe, 0xa2, 0x6d, 0x14,
+	0x31, 0x44, 0xee, 0x6b, 0x89, 0x37, 0x77, 0xa0, 0x38, 0x7f, 0x65, 0x64, 0x40, 0xe6, 0x15, 0xb9,
+	0x94, 0x81, 0x64, 0x4b, 0x74, 0x0f, 0x96, 0x2e, 0x5c, 0x7f, 0xa2, 0x22, 0x28, 0x36, 0x3b, 0xe9,
+	0x4f, 0xb4, 0xea, 0x1f, 0x35, 0x28, 0x49, 0xe7, 0xc5, 0xe3, 0x30, 0x88, 0x09, 0x42, 0x90, 0x9d,
+	0xb8, 0xb1, 0xc8, 0x83, 0x22, 0xe6, 0xeb, 0xaf, 0x4c, 0x82, 0x5d, 0xc8, 0x91, 0x28, 0x0a, 0xa3,
+	0xb8, 0x9c, 0xe1, 0xc1, 0xfa, 0xd6, 0x62, 0xc1, 0xb2, 0x19, 0x07, 0x4b, 0x2a, 0x33, 0xf0, 0xda,
+	0x8d, 0x02, 0x2f, 0x18, 0xc5, 0xe5, 0x6c, 0x25, 0xc3, 0x0c, 0xa8, 0x3d, 0x7a, 0x0c, 0x79, 0xf6,
+	0x11, 0xbd, 0xcf, 0xe2, 0x30, 0x28, 0x2f, 0xb1, 0xaf, 0xaa, 0x17, 0xaf, 0xde, 0xae, 0xeb, 0xc7,
+	0x6e, 0x4c, 0x0f, 0xbb, 0xed, 0x16, 0xd6, 0x99, 0xf8, 0x30, 0x0e, 0x83, 0xea, 0x6f, 0x35, 0x80,
+	0x6b, 0xed, 0xec, 0x2a, 0x94, 0xfc, 0x48, 0xa5, 0x34, 0x5f, 0xa3, 0x43, 0xd0, 0xc7, 0x61, 0xec,
+	0x31, 0x77, 0xf1, 0xab, 0x14, 0xb6, 0x6a, 0xb7, 0x47, 0x91, 0x69, 0xeb, 0x48, 0x16, 0x4e, 0xf8,
+	0x2c, 0x23, 0x62, 0x72, 0x41, 0x22, 0x8f, 0x5e, 0x96, 0x33, 0x0b, 0x66, 0x44, 0x57, 0x12, 0x70,
+	0x42, 0xad, 0x1e, 0x41, 0xe9, 0x86, 0x05, 0x74, 0x1f, 0x72, 0xe1, 0x70, 0x18, 0x13, 0xf1, 0xe5,
+	0x25, 0x2c, 0x77, 0xec, 0x3e, 0xbe, 0x17, 0x88, 0x10, 0x94, 0x30, 0x5f, 0xb3, 0x60, 0xf7, 0x43,
+	0x9f, 0x9b, 0x2f, 0x61, 0xb6, 0xac, 0xfe, 0x22, 0x0d, 0xf9, 0x56, 0x38, 0x20, 0xf6, 0x05, 0xab,
+	0xdf, 0x3a, 0x64, 0xe9, 0xe5, 0x98, 0x70, 0x4d, 0x2b, 0x0b, 0xdc, 0x35, 0x61, 0x3a, 0x97, 0x63,
+	0x82, 0x39, 0x57, 0x25, 0x54, 0xfa, 0x3a, 0xa1, 0x1e, 0x42, 0x31, 0xa6, 0x91, 0x17, 0x8c, 0x7a,
+	0x22, 0xaf, 0x78, 0xf5, 0xef, 0xa7, 0x70, 0x41, 0x9c, 0x9e, 0xb0, 0x43, 0xf4, 0x4d, 0xc8, 0x7b,
+	0x01, 0x95, 0x08, 0xf6, 0x0e, 0x64, 0xf6, 0x53, 0x58, 0xf7, 0x02, 0x2a, 0xc4, 0xeb, 0x00, 0x93,
+	0x6b, 0x39, 0x0b, 0x6c, 0x76, 0x3f, 0x85, 0xf3, 0x93, 0x04, 0xf0, 0x00, 0x0a, 0x43, 0x3f, 0x74,
+	0x15, 0x82, 0x95, 0xad, 0xb6, 0x9f, 0xc2, 0xc0, 0x0f, 0x13, 0x1d, 0xa7, 0x61, 0xe8, 0x4b, 0x04,
+	0xab, 0x4a, 0x9d, 0xe9, 0x60, 0x67, 0x1c, 0x50, 0x5f, 0x96, 0x99, 0x5f, 0xfd, 0x8b, 0x06, 0x77,
+	0x79, 0x6a, 0x74, 0x69, 0x44, 0xdc, 0xf3, 0x24, 0xdd, 0xeb, 0x90, 0x23, 0xec, 0xba, 0x71, 0x59,
+	0xe3, 0xe9, 0xbb, 0xb9, 0xb8, 0x87, 0xb0, 0x64, 0xfe, 0x4f, 0xcb, 0xa3, 0xba, 0x03, 0x7a, 0x27,
+	0x0a, 0x47, 0x11, 0x89, 0x63, 0xf6, 0x8c, 0x8f, 0x49, 0xd4, 0x57, 0xcf, 0x78, 0x1a, 0xab, 0x2d,
+	0x7b, 0x01, 0xc6, 0x67, 0x6e, 0x9c, 0xbc, 0x00, 0x7c, 0x53, 0xfd, 0x9d, 0x06, 0x1f, 0x70, 0x73,
+	0x4a, 0x43, 0xe2, 0x96, 0x17, 0xa0, 0x8f, 0xe5, 0x19, 0x57, 0x55, 0x58, 0x20, 0xb5, 0x95, 0x12,
+	0x16, 0x65, 0x45, 0x46, 0x4d, 0xd0, 0x23, 0xa9, 0x74, 0xe1, 0x7a, 0xbb, 0xf1, 0x20, 0x31, 0x6d,
+	0x4a, 0x03, 0x0b, 0x27, 0xf7, 0x79, 0xf5, 0x37, 0x1a, 0x2c, 0x9f, 0x90, 0x28, 0x66, 0xe5, 0x52,
+	0x86, 0xe5, 0x0b, 0xb1, 0x54, 0xcd, 0x4b, 0x6e, 0xd1, 0x0e, 0x2c, 0x9d, 0x4e, 0x3c, 0x7f, 0x20,
+	0x2d, 0x9b, 0x35, 0xd1, 0x18, 0x6b, 0xaa, 0x31, 0xd6, 0x1c, 0xd5, 0x18, 0xeb, 0xfa, 0x9b, 0xb7,
+	0xeb, 0xa9, 0xcf, 0xff, 0xbe, 0xae, 0x61, 0x41, 0x41, 0x1f, 0xc2, 0x8a, 0x68, 0xd2, 0x3d, 0xa5,
+	0x5c, 0xb4, 0xb8, 0x92, 0x38, 0x55, 0xc6, 0x1f, 0x83, 0xa1, 0x62, 0x9d, 0x00, 0xb3, 0x1c, 0xb8,
+	0xaa, 0xce, 0x25, 0xb4, 0xfa, 0x93, 0x34, 0xe8, 0x47, 0x6e, 0xe0, 0x0d, 0x59, 0xc7, 0x45, 0x90,
+	0xe5, 0x7d, 0x53, 0xbe, 0x4d, 0x6c, 0xfd, 0x95, 0x79, 0x54, 0x86, 0x65, 0xd7, 0xf7, 0xdc, 0x98,
+	0x88, 0x44, 0xca, 0x63, 0xb5, 0x45, 0x75, 0x58, 0x9e, 0x37, 0x5c, 0xd8, 0xda, 0xb8, 0xd5, 0xc1,
+	0xf2, 0x8b, 0xae, 0x1d, 0x75, 0x08, 0xb9, 0x98, 0xba, 0x74, 0x12, 0xf3, 0x3a, 0x5c, 0xd9, 0xda,
+	0xba, 0x55, 0x45, 0x83, 0x5c, 0x10, 0x3f, 0x1c, 0x9f, 0x93, 0x80, 0x76, 0x39, 0x13, 0x4b, 0x0d,
+	0x7c, 0x2a, 0x20, 0x2e, 0x9d, 0x44, 0x24, 0x2e, 0xe7, 0x44, 0xb2, 0xaa, 0x7d, 0xd5, 0x80, 0x15,
+	0x65, 0x5b, 0x34, 0xeb, 0xea, 0x31, 0xac, 0x26, 0x27, 0x49, 0x49, 0xde, 0x88, 0xe7, 0xd7, 0xb9,
+	0x50, 0xf5, 0xff, 0xe1, 0xff, 0xba, 0x93, 0xf1, 0x38, 0x8c, 0x28, 0x19, 0x34, 0xa5, 0x0f, 0x63,
+	0x65, 0x93, 0x80, 0xf9, 0x65, 0xc2, 0x24, 0xf5, 0xf3, 0xca, 0xeb, 0xea, 0x51, 0xb8, 0x3d, 0xf7,
+	0x55, 0x5c, 0xf1, 0x35, 0xb7, 0xfa, 0xd7, 0x34, 0x14, 0x79, 0x1d, 0x37, 0x08, 0x75, 0x3d, 0x3f,
+	0x46, 0xcf, 0xe1, 0x03, 0x2f, 0xb8, 0x70, 0x7d, 0x6f, 0xd0, 0x63, 0x73, 0x52, 0x2f, 0x19, 0x27,
+	0x34, 0xf9, 0x70, 0xdd, 0x95, 0xe2, 0x3d, 0xcf, 0x27, 0x6a, 0x74, 0x40, 0x1f, 0xc3, 0xbd, 0x49,
+	0x10, 0xab, 0xef, 0xed, 0xdd, 0xcc, 0x10, 0x46, 0x9a, 0x93, 0xaa, 0xdb, 0xa0, 0x6d, 0xb8, 0xdf,
+	0x77, 0x83, 0x20, 0xa4, 0xbd, 0x01, 0xa1, 0xa4, 0x4f, 0xaf, 0x69, 0x19, 0x69, 0xeb, 0x9e, 0x90,
+	0x37, 0xb8, 0x38, 0xe1, 0x7d, 0x17, 0xcc, 0x79, 0x63, 0x34, 0x72, 0x83, 0x98, 0xcd, 0x3f, 0xbd,
+	0x64, 0x98, 0x63, 0xdc, 0xf2, 0x1c, 0xc6, 0x51, 0x10, 0x36, 0xc1, 0xa1, 0x27, 0x70, 0xe7, 0x9a,
+	0x33, 0x74, 0x3d, 0x7f, 0x12, 0x89, 0xb7, 0x9d, 0xd1, 0x8c, 0x44, 0xb4, 0x27, 0x24, 0xe8, 0x51,
+	0x52, 0x64, 0x0a, 0x9b, 0x93, 0x58, 0x59, 0x66, 0x12, 0xb8, 0x93, 0xfd, 0xd9, 0xaf, 0xd7, 0xb5,
+	0xba, 0x0e, 0xb9, 0x88, 0xb8, 0x71, 0x18, 0x6c, 0xfe, 0x59, 0x03, 0x3d, 0xf1, 0xd0, 0x43, 0x28,
+	0xd9, 0xad, 0xdd, 0x76, 0xe3, 0xa0, 0xf5, 0xa2, 0x77, 0xec, 0xec, 0x7d, 0x62, 0xa4, 0x4c, 0x63,
+	0x3a, 0xab, 0x14, 0x15, 0x80, 0x9d, 0xb1, 0x7a, 0x9e, 0x07, 0x7d, 0xb4, 0x6d, 0x68, 0xe6, 0x9d,
+	0xe9, 0xac, 0x52, 0x9a, 0x43, 0x7d, 0xb4, 0xcd, 0xea, 0xf9, 0x26, 0xac, 0x69, 0x1b, 0x69, 0xf3,
+	0xee, 0x74, 0x56, 0x59, 0xbd, 0x01, 0x6c, 0xda, 0xef, 0x42, 0xeb, 0xb6, 0x91, 0xf9, 0x12, 0x68,
+	0xdd, 0x46, 0x8f, 0x60, 0x35, 0x81, 0xd6, 0xad, 0xae, 0xbd, 0xfd, 0xdc, 0xc8, 0x9a, 0x68, 0x3a,
+	0xab, 0xac, 0x28, 0x64, 0xdd, 0x8d, 0xc9, 0xf6, 0xf3, 0xcd, 0x26, 0xe4, 0xc4, 0xd8, 0x89, 0x1e,
+	0x40, 0x71, 0xaf, 0x8d, 0x8f, 0x2c, 0xa7, 0xd7, 0xc1, 0x6d, 0xa7, 0x6d, 0xa4, 0xcc, 0xd5, 0xe9,
+	0xac, 0x52, 0x10, 0xd2, 0x8e, 0xf8, 0x6f, 0x80, 0x82, 0x84, 0xb0, 0x39, 0xc8, 0xd0, 0xcc, 0x95,
+	0xe9, 0xac, 0x02, 0x02, 0xc1, 0x4e, 0x36, 0x7f, 0xa9, 0x41, 0x96, 0x87, 0xe5, 0x01, 0x14, 0x1b,
+	0xf6, 0x9e, 0x75, 0xdc, 0x74, 0x7a, 0x47, 0xed, 0x86, 0xad, 0x94, 0x35, 0xc8, 0xd0, 0x9d, 0xf8,
+	0x94, 0x43, 0xee, 0x43, 0xae, 0x65, 0x39, 0x07, 0x27, 0xb6, 0xa1, 0x99, 0x30, 0x9d, 0x55, 0x72,
+	0x2d, 0x97, 0x7a, 0x17, 0x04, 0x55, 0xa1, 0xd8, 0xc1, 0x76, 0x07, 0xb7, 0x77, 0xed, 0x6e, 0xd7,
+	0x6e, 0x18, 0x69, 0xe1, 0xdb, 0x4e, 0x44, 0xc6, 0x51, 0xd8, 0x27, 0x71, 0x4c, 0x06, 0xe8, 0x1b,
+	0x90, 0xb7, 0x5a, 0xad, 0xb6, 0x63, 0x39, 0x76, 0xc3, 0xc8, 0x9a, 0xa5, 0xe9, 0xac, 0x92, 0xb7,
+	0x58, 0x76, 0xb9, 0x94, 0x0c, 0xd8, 0x83, 0xd0, 0xb5, 0x8f, 0xac, 0x96, 0x73, 0xb0, 0x6b, 0xe8,
+	0x66, 0x71, 0x3a, 0xab, 0xe8, 0x5d, 0x72, 0xee, 0x06, 0xd4, 0xeb, 0x6f, 0xfe, 0x00, 0x74, 0x35,
+	0x11, 0xb1, 0x08, 0x75, 0xed, 0x13, 0x1b, 0x1f, 0x38, 0x9f, 0xf6, 0x6c, 0x8c, 0xdb, 0xd8, 0x48,
+	0x89, 0x08, 0x29, 0x84, 0x98, 0xea, 0x1e, 0x83, 0x91, 0xc0, 0x5e, 0x5a, 0xb8, 0x75, 0xd0, 0x7a,
+	0x61, 0x68, 0xc2, 0xed, 0x0a, 0xf8, 0x52, 0x34, 0xc7, 0xcd, 0x3f, 0x68, 0x50, 0xba, 0x31, 0xd0,
+	0x20, 0x13, 0x0a, 0xf6, 0x89, 0xdd, 0x72, 0x7a, 0x27, 0x56, 0xf3, 0x98, 0xf9, 0x21, 0x3f, 0x9d,
+	0x55, 0x96, 0xc4, 0x30, 0xf1, 0x08, 0x90, 0x90, 0xb5, 0xeb, 0x87, 0xf6, 0xae, 0xd3, 0xeb, 0x3a,
+	0x16, 0x76, 0x0c, 0x4d, 0xb8, 0xaa, 0x7d, 0xfa, 0x19, 0xe9, 0xb3, 0xa7, 0x2e, 0xa2, 0xe8, 0x21,
+	0x18, 0x37, 0x80, 0x76, 0x8b, 0xb9, 0x85, 0xdf, 0x5a, 0xc0, 0xec, 0x80, 0xf5, 0x8f, 0x3b, 0x02,
+	0x64, 0x61, 0x6c, 0x7d, 0x2a, 0x95, 0x65, 0x44, 0x88, 0xac, 0x28, 0x72, 0x2f, 0x85, 0xae, 0x07,
+	0xb0, 0x3a, 0x0f, 0x63, 0xaa, 0xb2, 0xc2, 0x47, 0x1c, 0x64, 0x07, 0x83, 0xcd, 0xbf, 0x69, 0x70,
+	0xe7, 0x9d, 0xe7, 0x16, 0xad, 0xb1, 0x90, 0x9e, 0xf4, 0x0e, 0x5a, 0xd6, 0x2e, 0x8f, 0x5a, 0x4a,
+	0xb0, 0x0e, 0x02, 0xb7, 0xcf, 0xe3, 0x26, 0xe5, 0x9d, 0xa6, 0xd5, 0x92, 0x2e, 0xe2, 0xf2, 0x8e,
+	0xef, 0x06, 0xcc, 0x37, 0x89, 0x1c, 0xdb, 0x56, 0xb3, 0xb3, 0x6f, 0x19, 0x69, 0x29, 0x8f, 0x88,
+	0xe5, 0x8f, 0xcf, 0x5c, 0x54, 0x86, 0x3c, 0x93, 0x0b, 0x61, 0x46, 0xf8, 0x49, 0x48, 0xee, 0x83,
+	0xce, 0x24, 0x75, 0xdb, 0xb1, 0x8c, 0xac, 0xa9, 0x4f, 0x67, 0x95, 0x6c, 0x9d, 0x50, 0x17, 0x99,
+	0x00, 0xec, 0xbc, 0xeb, 0x58, 0xf5, 0xa6, 0x6d, 0x2c, 0x89, 0x2c, 0xea, 0x52, 0xf7, 0xd4, 0x27,
+	0x4a, 0x76, 0x64, 0x39, 0xc7, 0xd8, 0x36, 0x72, 0x42, 0x76, 0xc4, 0xbb, 0xc2, 0xd6, 0xbf, 0xd2,
+	0x90, 0x6b, 0xf0, 0x6a, 0x47, 0x43, 0x58, 0xe2, 0xcd, 0x1f, 0x3d, 0x79, 0xaf, 0x5f, 0x3e, 0xf3,
+	0x3d, 0x67, 0x0a, 0x44, 0xa1, 0x30, 0x37, 0x0c, 0xbe, 0xaf, 0xb5, 0xe7, 0x8b, 0xc1, 0x6f, 0x4e,
+	0x9a, 0xcf, 0x34, 0xf4, 0x63, 0xb8, 0xc3, 0x05, 0x2f, 0x3d, 0x7a, 0x96, 0xcc, 0x6c, 0xef, 0x69,
+	0x7b, 0x7b, 0x31, 0xf8, 0x7f, 0x0e, 0x74, 0xcf, 0xb4, 0xad, 0xcf, 0xd3, 0x00, 0xc2, 0xcd, 0xfb,
+	0x61, 0x4c, 0x51, 0x04, 0xa5, 0x2e, 0x89, 0xe6, 0x26, 0x99, 0xa7, 0x0b, 0x77, 0x59, 0xf9, 0x29,
+	0xcf, 0x16, 0x27, 0x48, 0xb7, 0xff, 0x5c, 0x03, 0xf4, 0x6e, 0xe7, 0x45, 0xb7, 0xff, 0xdf, 0xff,
+	0xd7, 0x5e, 0x6e, 0x7e, 0xe7, 0x6b, 0x71, 0xe5, 0x38, 0x59, 0x7d, 0xf3, 0xcf, 0xb5, 0xd4, 0x9b,
+	0xab, 0x35, 0xed, 0x4f, 0x57, 0x6b, 0xda, 0x3f, 0xae, 0xd6, 0x52, 0xbf, 0xfa, 0x62, 0x4d, 0xfb,
+	0xfd, 0x17, 0x6b, 0xda, 0xf7, 0x75, 0x45, 0x3f, 0xcd, 0xf1, 0xd5, 0xc7, 0xff, 0x1e, 0x00, 0x81,
+	0x7c, 0x8f, 0xe2, 0xb3, 0x11, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -884,6 +1653,10 @@ const _ = grpc.SupportPackageIsVersion4
 type DriverClient interface {
 	// Parse returns an UAST for a given source file.
 	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ParseStream is like Parse, but streams the UAST as a sequence of node events.
+	ParseStream(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseStreamClient, error)
+	// ParseWithProgress is like Parse, but sends a sequence of progress events before the response.
+	ParseWithProgress(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseWithProgressClient, error)
 }
 
 type driverClient struct {
@@ -903,10 +1676,78 @@ func (c *driverClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *driverClient) ParseStream(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[0], "/gopkg.in.bblfsh.sdk.v2.protocol.Driver/ParseStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverParseStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_ParseStreamClient interface {
+	Recv() (*ParseStreamResponse, error)
+	grpc.ClientStream
+}
+
+type driverParseStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverParseStreamClient) Recv() (*ParseStreamResponse, error) {
+	m := new(ParseStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *driverClient) ParseWithProgress(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseWithProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[1], "/gopkg.in.bblfsh.sdk.v2.protocol.Driver/ParseWithProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverParseWithProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_ParseWithProgressClient interface {
+	Recv() (*ParseProgressResponse, error)
+	grpc.ClientStream
+}
+
+type driverParseWithProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverParseWithProgressClient) Recv() (*ParseProgressResponse, error) {
+	m := new(ParseProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DriverServer is the server API for Driver service.
 type DriverServer interface {
 	// Parse returns an UAST for a given source file.
 	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ParseStream is like Parse, but streams the UAST as a sequence of node events.
+	ParseStream(*ParseRequest, Driver_ParseStreamServer) error
+	// ParseWithProgress is like Parse, but sends a sequence of progress events before the response.
+	ParseWithProgress(*ParseRequest, Driver_ParseWithProgressServer) error
 }
 
 // UnimplementedDriverServer can be embedded to have forward compatible implementations.
@@ -916,6 +1757,12 @@ type UnimplementedDriverServer struct {
 func (*UnimplementedDriverServer) Parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
 }
+func (*UnimplementedDriverServer) ParseStream(req *ParseRequest, srv Driver_ParseStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ParseStream not implemented")
+}
+func (*UnimplementedDriverServer) ParseWithProgress(req *ParseRequest, srv Driver_ParseWithProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method ParseWithProgress not implemented")
+}
 
 func RegisterDriverServer(s *grpc.Server, srv DriverServer) {
 	s.RegisterService(&_Driver_serviceDesc, srv)
@@ -939,16 +1786,69 @@ func _Driver_Parse_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Driver_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "gopkg.in.bblfsh.sdk.v2.protocol.Driver",
-	HandlerType: (*DriverServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Parse",
-			Handler:    _Driver_Parse_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
+func _Driver_ParseStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ParseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServer).ParseStream(m, &driverParseStreamServer{stream})
+}
+
+type Driver_ParseStreamServer interface {
+	Send(*ParseStreamResponse) error
+	grpc.ServerStream
+}
+
+type driverParseStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverParseStreamServer) Send(m *ParseStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Driver_ParseWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ParseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServer).ParseWithProgress(m, &driverParseWithProgressServer{stream})
+}
+
+type Driver_ParseWithProgressServer interface {
+	Send(*ParseProgressResponse) error
+	grpc.ServerStream
+}
+
+type driverParseWithProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverParseWithProgressServer) Send(m *ParseProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Driver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gopkg.in.bblfsh.sdk.v2.protocol.Driver",
+	HandlerType: (*DriverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _Driver_Parse_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ParseStream",
+			Handler:       _Driver_ParseStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ParseWithProgress",
+			Handler:       _Driver_ParseWithProgress_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "driver.proto",
 }
 
@@ -1088,6 +1988,49 @@ func (m *ParseRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.RawContent) > 0 {
+		i -= len(m.RawContent)
+		copy(dAtA[i:], m.RawContent)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.RawContent)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.ContentURI) > 0 {
+		i -= len(m.ContentURI)
+		copy(dAtA[i:], m.ContentURI)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.ContentURI)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Encoding != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Encoding))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Format != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Format))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Options) > 0 {
+		for k := range m.Options {
+			v := m.Options[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintDriver(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintDriver(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintDriver(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if m.Mode != 0 {
 		i = encodeVarintDriver(dAtA, i, uint64(m.Mode))
 		i--
@@ -1141,6 +2084,22 @@ func (m *ParseResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.UastJSON) > 0 {
+		i -= len(m.UastJSON)
+		copy(dAtA[i:], m.UastJSON)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.UastJSON)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintDriver(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Errors) > 0 {
 		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1196,6 +2155,23 @@ func (m *ParseError) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Severity != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Severity))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Position != nil {
+		{
+			size, err := m.Position.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintDriver(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Text) > 0 {
 		i -= len(m.Text)
 		copy(dAtA[i:], m.Text)
@@ -1206,7 +2182,7 @@ func (m *ParseError) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Version) Marshal() (dAtA []byte, err error) {
+func (m *ErrorPosition) Marshal() (dAtA []byte, err error) {
 	size := m.ProtoSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1216,12 +2192,12 @@ func (m *Version) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Version) MarshalTo(dAtA []byte) (int, error) {
+func (m *ErrorPosition) MarshalTo(dAtA []byte) (int, error) {
 	size := m.ProtoSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Version) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ErrorPosition) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1230,25 +2206,25 @@ func (m *Version) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Build, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Build):])
-	if err1 != nil {
-		return 0, err1
+	if m.Col != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Col))
+		i--
+		dAtA[i] = 0x18
 	}
-	i -= n1
-	i = encodeVarintDriver(dAtA, i, uint64(n1))
-	i--
-	dAtA[i] = 0x12
-	if len(m.Version) > 0 {
-		i -= len(m.Version)
-		copy(dAtA[i:], m.Version)
-		i = encodeVarintDriver(dAtA, i, uint64(len(m.Version)))
+	if m.Line != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Line))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x10
+	}
+	if m.Offset != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Manifest) Marshal() (dAtA []byte, err error) {
+func (m *NodeEvent) Marshal() (dAtA []byte, err error) {
 	size := m.ProtoSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1258,12 +2234,12 @@ func (m *Manifest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Manifest) MarshalTo(dAtA []byte) (int, error) {
+func (m *NodeEvent) MarshalTo(dAtA []byte) (int, error) {
 	size := m.ProtoSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Manifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NodeEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1272,86 +2248,94 @@ func (m *Manifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Features) > 0 {
-		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Features[iNdEx])
-			copy(dAtA[i:], m.Features[iNdEx])
-			i = encodeVarintDriver(dAtA, i, uint64(len(m.Features[iNdEx])))
-			i--
-			dAtA[i] = 0x32
-		}
-	}
-	if m.Status != 0 {
-		i = encodeVarintDriver(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Version != nil {
+	if m.Value != nil {
 		{
-			size, err := m.Version.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
+			size := m.Value.ProtoSize()
+			i -= size
+			if _, err := m.Value.MarshalTo(dAtA[i:]); err != nil {
 				return 0, err
 			}
-			i -= size
-			i = encodeVarintDriver(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Aliases) > 0 {
-		for iNdEx := len(m.Aliases) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Aliases[iNdEx])
-			copy(dAtA[i:], m.Aliases[iNdEx])
-			i = encodeVarintDriver(dAtA, i, uint64(len(m.Aliases[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
 		}
 	}
-	if len(m.Language) > 0 {
-		i -= len(m.Language)
-		copy(dAtA[i:], m.Language)
-		i = encodeVarintDriver(dAtA, i, uint64(len(m.Language)))
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Key)))
 		i--
 		dAtA[i] = 0x12
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintDriver(dAtA, i, uint64(len(m.Name)))
+	if m.Type != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Type))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *VersionRequest) Marshal() (dAtA []byte, err error) {
-	size := m.ProtoSize()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *NodeEvent_StringValue) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
 
-func (m *VersionRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.ProtoSize()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *NodeEvent_StringValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= len(m.StringValue)
+	copy(dAtA[i:], m.StringValue)
+	i = encodeVarintDriver(dAtA, i, uint64(len(m.StringValue)))
+	i--
+	dAtA[i] = 0x1a
+	return len(dAtA) - i, nil
+}
+func (m *NodeEvent_IntValue) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
 
-func (m *VersionRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NodeEvent_IntValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
-	}
+	i = encodeVarintDriver(dAtA, i, uint64(m.IntValue))
+	i--
+	dAtA[i] = 0x20
 	return len(dAtA) - i, nil
 }
+func (m *NodeEvent_UintValue) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
 
-func (m *VersionResponse) Marshal() (dAtA []byte, err error) {
+func (m *NodeEvent_UintValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i = encodeVarintDriver(dAtA, i, uint64(m.UintValue))
+	i--
+	dAtA[i] = 0x28
+	return len(dAtA) - i, nil
+}
+func (m *NodeEvent_FloatValue) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *NodeEvent_FloatValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= 8
+	encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.FloatValue))))
+	i--
+	dAtA[i] = 0x31
+	return len(dAtA) - i, nil
+}
+func (m *NodeEvent_BoolValue) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *NodeEvent_BoolValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.BoolValue {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x38
+	return len(dAtA) - i, nil
+}
+func (m *ParseStreamResponse) Marshal() (dAtA []byte, err error) {
 	size := m.ProtoSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1361,12 +2345,12 @@ func (m *VersionResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *VersionResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *ParseStreamResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.ProtoSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *VersionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ParseStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1375,22 +2359,54 @@ func (m *VersionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Version != nil {
-		{
-			size, err := m.Version.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintDriver(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Errors) > 0 {
+		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Errors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintDriver(dAtA, i, uint64(size))
 			}
-			i -= size
-			i = encodeVarintDriver(dAtA, i, uint64(size))
+			i--
+			dAtA[i] = 0x1a
 		}
+	}
+	if len(m.Language) > 0 {
+		i -= len(m.Language)
+		copy(dAtA[i:], m.Language)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Language)))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x12
+	}
+	if len(m.Events) > 0 {
+		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintDriver(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SupportedLanguagesRequest) Marshal() (dAtA []byte, err error) {
+func (m *Progress) Marshal() (dAtA []byte, err error) {
 	size := m.ProtoSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1400,12 +2416,12 @@ func (m *SupportedLanguagesRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SupportedLanguagesRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *Progress) MarshalTo(dAtA []byte) (int, error) {
 	size := m.ProtoSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SupportedLanguagesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Progress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1414,10 +2430,23 @@ func (m *SupportedLanguagesRequest) MarshalToSizedBuffer(dAtA []byte) (int, erro
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Phase) > 0 {
+		i -= len(m.Phase)
+		copy(dAtA[i:], m.Phase)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Phase)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Percent != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Percent))))
+		i--
+		dAtA[i] = 0xd
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SupportedLanguagesResponse) Marshal() (dAtA []byte, err error) {
+func (m *ParseProgressResponse) Marshal() (dAtA []byte, err error) {
 	size := m.ProtoSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1427,12 +2456,12 @@ func (m *SupportedLanguagesResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SupportedLanguagesResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *ParseProgressResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.ProtoSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SupportedLanguagesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ParseProgressResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1441,400 +2470,653 @@ func (m *SupportedLanguagesResponse) MarshalToSizedBuffer(dAtA []byte) (int, err
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Languages) > 0 {
-		for iNdEx := len(m.Languages) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Languages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintDriver(dAtA, i, uint64(size))
+	if m.Event != nil {
+		{
+			size := m.Event.ProtoSize()
+			i -= size
+			if _, err := m.Event.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
 			}
-			i--
-			dAtA[i] = 0xa
 		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ErrorDetails) Marshal() (dAtA []byte, err error) {
-	size := m.ProtoSize()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *ErrorDetails) MarshalTo(dAtA []byte) (int, error) {
-	size := m.ProtoSize()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ParseProgressResponse_Progress) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
 
-func (m *ErrorDetails) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ParseProgressResponse_Progress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	if m.Reason != nil {
+	if m.Progress != nil {
 		{
-			size := m.Reason.ProtoSize()
-			i -= size
-			if _, err := m.Reason.MarshalTo(dAtA[i:]); err != nil {
+			size, err := m.Progress.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
 				return 0, err
 			}
+			i -= size
+			i = encodeVarintDriver(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
-
-func (m *ErrorDetails_InvalidFileEncoding) MarshalTo(dAtA []byte) (int, error) {
+func (m *ParseProgressResponse_Response) MarshalTo(dAtA []byte) (int, error) {
 	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
 
-func (m *ErrorDetails_InvalidFileEncoding) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ParseProgressResponse_Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	i--
-	if m.InvalidFileEncoding {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+	if m.Response != nil {
+		{
+			size, err := m.Response.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintDriver(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
 	}
-	i--
-	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
-func (m *ErrorDetails_UnsupportedLanguage) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+func (m *Version) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
-func (m *ErrorDetails_UnsupportedLanguage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	i -= len(m.UnsupportedLanguage)
-	copy(dAtA[i:], m.UnsupportedLanguage)
-	i = encodeVarintDriver(dAtA, i, uint64(len(m.UnsupportedLanguage)))
-	i--
-	dAtA[i] = 0x12
-	return len(dAtA) - i, nil
-}
-func (m *ErrorDetails_CannotDetectLanguage) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+func (m *Version) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ErrorDetails_CannotDetectLanguage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Version) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	i--
-	if m.CannotDetectLanguage {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	i--
-	dAtA[i] = 0x18
-	return len(dAtA) - i, nil
-}
-func (m *ErrorDetails_UnsupportedTransformMode) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
-}
-
-func (m *ErrorDetails_UnsupportedTransformMode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	i--
-	if m.UnsupportedTransformMode {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+	if len(m.LanguageVersion) > 0 {
+		i -= len(m.LanguageVersion)
+		copy(dAtA[i:], m.LanguageVersion)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.LanguageVersion)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.DriverVersion) > 0 {
+		i -= len(m.DriverVersion)
+		copy(dAtA[i:], m.DriverVersion)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.DriverVersion)))
+		i--
+		dAtA[i] = 0x1a
 	}
+	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Build, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Build):])
+	if err4 != nil {
+		return 0, err4
+	}
+	i -= n4
+	i = encodeVarintDriver(dAtA, i, uint64(n4))
 	i--
-	dAtA[i] = 0x20
+	dAtA[i] = 0x12
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
-func (m *ErrorDetails_TransformFailure) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
-}
 
-func (m *ErrorDetails_TransformFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	i--
-	if m.TransformFailure {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+func (m *Manifest) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	i--
-	dAtA[i] = 0x28
-	return len(dAtA) - i, nil
+	return dAtA[:n], nil
 }
-func (m *ErrorDetails_DriverFailure) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+
+func (m *Manifest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ErrorDetails_DriverFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Manifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	i--
-	if m.DriverFailure {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
-	}
-	i--
-	dAtA[i] = 0x30
-	return len(dAtA) - i, nil
-}
-func encodeVarintDriver(dAtA []byte, offset int, v uint64) int {
-	offset -= sovDriver(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *ParseRequest) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Content)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	l = len(m.Language)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintDriver(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
 	}
-	l = len(m.Filename)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
+	if m.Status != 0 {
+		i = encodeVarintDriver(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.Mode != 0 {
-		n += 1 + sovDriver(uint64(m.Mode))
+	if m.Version != nil {
+		{
+			size, err := m.Version.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintDriver(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if len(m.Aliases) > 0 {
+		for iNdEx := len(m.Aliases) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Aliases[iNdEx])
+			copy(dAtA[i:], m.Aliases[iNdEx])
+			i = encodeVarintDriver(dAtA, i, uint64(len(m.Aliases[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	return n
+	if len(m.Language) > 0 {
+		i -= len(m.Language)
+		copy(dAtA[i:], m.Language)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Language)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintDriver(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ParseResponse) ProtoSize() (n int) {
-	if m == nil {
-		return 0
+func (m *VersionRequest) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *VersionRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VersionRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Uast)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	l = len(m.Language)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	if len(m.Errors) > 0 {
-		for _, e := range m.Errors {
-			l = e.ProtoSize()
-			n += 1 + l + sovDriver(uint64(l))
-		}
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *ParseError) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Text)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+func (m *VersionResponse) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Version) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Version)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Build)
-	n += 1 + l + sovDriver(uint64(l))
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+func (m *VersionResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Manifest) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *VersionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	l = len(m.Language)
-	if l > 0 {
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	if len(m.Aliases) > 0 {
-		for _, s := range m.Aliases {
-			l = len(s)
-			n += 1 + l + sovDriver(uint64(l))
-		}
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	if m.Version != nil {
-		l = m.Version.ProtoSize()
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	if m.Status != 0 {
-		n += 1 + sovDriver(uint64(m.Status))
-	}
-	if len(m.Features) > 0 {
-		for _, s := range m.Features {
-			l = len(s)
-			n += 1 + l + sovDriver(uint64(l))
+		{
+			size, err := m.Version.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintDriver(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0xa
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *VersionRequest) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+func (m *SupportedLanguagesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *VersionResponse) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Version != nil {
-		l = m.Version.ProtoSize()
-		n += 1 + l + sovDriver(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+func (m *SupportedLanguagesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SupportedLanguagesRequest) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SupportedLanguagesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SupportedLanguagesResponse) ProtoSize() (n int) {
-	if m == nil {
-		return 0
+func (m *SupportedLanguagesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SupportedLanguagesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SupportedLanguagesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
 	if len(m.Languages) > 0 {
-		for _, e := range m.Languages {
-			l = e.ProtoSize()
-			n += 1 + l + sovDriver(uint64(l))
+		for iNdEx := len(m.Languages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Languages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintDriver(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *ErrorDetails) ProtoSize() (n int) {
-	if m == nil {
-		return 0
+func (m *ErrorDetails) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ErrorDetails) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ErrorDetails) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Reason != nil {
-		n += m.Reason.ProtoSize()
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	if m.Reason != nil {
+		{
+			size := m.Reason.ProtoSize()
+			i -= size
+			if _, err := m.Reason.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ErrorDetails_InvalidFileEncoding) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 2
-	return n
+func (m *ErrorDetails_InvalidFileEncoding) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
-func (m *ErrorDetails_UnsupportedLanguage) ProtoSize() (n int) {
-	if m == nil {
-		return 0
+
+func (m *ErrorDetails_InvalidFileEncoding) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.InvalidFileEncoding {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
 	}
-	var l int
-	_ = l
-	l = len(m.UnsupportedLanguage)
-	n += 1 + l + sovDriver(uint64(l))
-	return n
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
-func (m *ErrorDetails_CannotDetectLanguage) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 2
-	return n
+func (m *ErrorDetails_UnsupportedLanguage) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
 }
-func (m *ErrorDetails_UnsupportedTransformMode) ProtoSize() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 2
+
+func (m *ErrorDetails_UnsupportedLanguage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= len(m.UnsupportedLanguage)
+	copy(dAtA[i:], m.UnsupportedLanguage)
+	i = encodeVarintDriver(dAtA, i, uint64(len(m.UnsupportedLanguage)))
+	i--
+	dAtA[i] = 0x12
+	return len(dAtA) - i, nil
+}
+func (m *ErrorDetails_CannotDetectLanguage) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *ErrorDetails_CannotDetectLanguage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.CannotDetectLanguage {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x18
+	return len(dAtA) - i, nil
+}
+func (m *ErrorDetails_UnsupportedTransformMode) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *ErrorDetails_UnsupportedTransformMode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.UnsupportedTransformMode {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x20
+	return len(dAtA) - i, nil
+}
+func (m *ErrorDetails_TransformFailure) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *ErrorDetails_TransformFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.TransformFailure {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x28
+	return len(dAtA) - i, nil
+}
+func (m *ErrorDetails_DriverFailure) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.ProtoSize()])
+}
+
+func (m *ErrorDetails_DriverFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.DriverFailure {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x30
+	return len(dAtA) - i, nil
+}
+func encodeVarintDriver(dAtA []byte, offset int, v uint64) int {
+	offset -= sovDriver(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ParseRequest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Content)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.Filename)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovDriver(uint64(m.Mode))
+	}
+	if len(m.Options) > 0 {
+		for k, v := range m.Options {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovDriver(uint64(len(k))) + 1 + len(v) + sovDriver(uint64(len(v)))
+			n += mapEntrySize + 1 + sovDriver(uint64(mapEntrySize))
+		}
+	}
+	if m.Format != 0 {
+		n += 1 + sovDriver(uint64(m.Format))
+	}
+	if m.Encoding != 0 {
+		n += 1 + sovDriver(uint64(m.Encoding))
+	}
+	l = len(m.ContentURI)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.RawContent)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
 	return n
 }
-func (m *ErrorDetails_TransformFailure) ProtoSize() (n int) {
+
+func (m *ParseResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Uast)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if len(m.Errors) > 0 {
+		for _, e := range m.Errors {
+			l = e.ProtoSize()
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	l = len(m.UastJSON)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ParseError) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.Position != nil {
+		l = m.Position.ProtoSize()
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.Severity != 0 {
+		n += 1 + sovDriver(uint64(m.Severity))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ErrorPosition) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Offset != 0 {
+		n += 1 + sovDriver(uint64(m.Offset))
+	}
+	if m.Line != 0 {
+		n += 1 + sovDriver(uint64(m.Line))
+	}
+	if m.Col != 0 {
+		n += 1 + sovDriver(uint64(m.Col))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *NodeEvent) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovDriver(uint64(m.Type))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.Value != nil {
+		n += m.Value.ProtoSize()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *NodeEvent_StringValue) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StringValue)
+	n += 1 + l + sovDriver(uint64(l))
+	return n
+}
+func (m *NodeEvent_IntValue) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovDriver(uint64(m.IntValue))
+	return n
+}
+func (m *NodeEvent_UintValue) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovDriver(uint64(m.UintValue))
+	return n
+}
+func (m *NodeEvent_FloatValue) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 9
+	return n
+}
+func (m *NodeEvent_BoolValue) ProtoSize() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -1843,23 +3125,1380 @@ func (m *ErrorDetails_TransformFailure) ProtoSize() (n int) {
 	n += 2
 	return n
 }
-func (m *ErrorDetails_DriverFailure) ProtoSize() (n int) {
+func (m *ParseStreamResponse) ProtoSize() (n int) {
 	if m == nil {
 		return 0
 	}
-	var l int
-	_ = l
-	n += 2
-	return n
-}
+	var l int
+	_ = l
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.ProtoSize()
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if len(m.Errors) > 0 {
+		for _, e := range m.Errors {
+			l = e.ProtoSize()
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Progress) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Percent != 0 {
+		n += 5
+	}
+	l = len(m.Phase)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ParseProgressResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Event != nil {
+		n += m.Event.ProtoSize()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ParseProgressResponse_Progress) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Progress != nil {
+		l = m.Progress.ProtoSize()
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	return n
+}
+func (m *ParseProgressResponse_Response) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.ProtoSize()
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	return n
+}
+func (m *Version) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Build)
+	n += 1 + l + sovDriver(uint64(l))
+	l = len(m.DriverVersion)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.LanguageVersion)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Manifest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if len(m.Aliases) > 0 {
+		for _, s := range m.Aliases {
+			l = len(s)
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if m.Version != nil {
+		l = m.Version.ProtoSize()
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovDriver(uint64(m.Status))
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VersionRequest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VersionResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Version != nil {
+		l = m.Version.ProtoSize()
+		n += 1 + l + sovDriver(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SupportedLanguagesRequest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SupportedLanguagesResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Languages) > 0 {
+		for _, e := range m.Languages {
+			l = e.ProtoSize()
+			n += 1 + l + sovDriver(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ErrorDetails) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Reason != nil {
+		n += m.Reason.ProtoSize()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ErrorDetails_InvalidFileEncoding) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+func (m *ErrorDetails_UnsupportedLanguage) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UnsupportedLanguage)
+	n += 1 + l + sovDriver(uint64(l))
+	return n
+}
+func (m *ErrorDetails_CannotDetectLanguage) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+func (m *ErrorDetails_UnsupportedTransformMode) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+func (m *ErrorDetails_TransformFailure) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+func (m *ErrorDetails_DriverFailure) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+
+func sovDriver(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozDriver(x uint64) (n int) {
+	return sovDriver(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ParseRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDriver
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParseRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParseRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Content = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Language", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Language = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filename", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filename = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= Mode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Options == nil {
+				m.Options = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowDriver
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowDriver
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthDriver
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthDriver
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowDriver
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthDriver
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthDriver
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipDriver(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthDriver
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Options[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
+			}
+			m.Format = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Format |= Format(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			m.Encoding = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Encoding |= Encoding(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawContent", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RawContent = append(m.RawContent[:0], dAtA[iNdEx:postIndex]...)
+			if m.RawContent == nil {
+				m.RawContent = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDriver(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParseResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDriver
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParseResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParseResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uast", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Uast = append(m.Uast[:0], dAtA[iNdEx:postIndex]...)
+			if m.Uast == nil {
+				m.Uast = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Language", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Language = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errors = append(m.Errors, &ParseError{})
+			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UastJSON", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UastJSON = append(m.UastJSON[:0], dAtA[iNdEx:postIndex]...)
+			if m.UastJSON == nil {
+				m.UastJSON = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDriver(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParseError) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDriver
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParseError: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParseError: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Position", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Position == nil {
+				m.Position = &ErrorPosition{}
+			}
+			if err := m.Position.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Severity", wireType)
+			}
+			m.Severity = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Severity |= Severity(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDriver(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ErrorPosition) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDriver
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ErrorPosition: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ErrorPosition: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Line", wireType)
+			}
+			m.Line = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Line |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Col", wireType)
+			}
+			m.Col = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Col |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDriver(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NodeEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDriver
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NodeEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NodeEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= NodeEventType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StringValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = &NodeEvent_StringValue{string(dAtA[iNdEx:postIndex])}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IntValue", wireType)
+			}
+			var v int64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Value = &NodeEvent_IntValue{v}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UintValue", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Value = &NodeEvent_UintValue{v}
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FloatValue", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = &NodeEvent_FloatValue{float64(math.Float64frombits(v))}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BoolValue", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.Value = &NodeEvent_BoolValue{b}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDriver(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
 
-func sovDriver(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozDriver(x uint64) (n int) {
-	return sovDriver(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *ParseRequest) Unmarshal(dAtA []byte) error {
+func (m *ParseStreamResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1882,17 +4521,17 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ParseRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ParseStreamResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ParseRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ParseStreamResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDriver
@@ -1902,23 +4541,25 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthDriver
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthDriver
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Content = string(dAtA[iNdEx:postIndex])
+			m.Events = append(m.Events, &NodeEvent{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
@@ -1954,9 +4595,9 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Filename", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDriver
@@ -1966,29 +4607,31 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthDriver
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthDriver
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Filename = string(dAtA[iNdEx:postIndex])
+			m.Errors = append(m.Errors, &ParseError{})
+			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
 			}
-			m.Mode = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDriver
@@ -1998,11 +4641,24 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Mode |= Mode(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDriver(dAtA[iNdEx:])
@@ -2028,7 +4684,7 @@ func (m *ParseRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ParseResponse) Unmarshal(dAtA []byte) error {
+func (m *Progress) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2051,49 +4707,26 @@ func (m *ParseResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ParseResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: Progress: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ParseResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Progress: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Uast", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDriver
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if byteLen < 0 {
-				return ErrInvalidLengthDriver
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthDriver
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Percent", wireType)
 			}
-			if postIndex > l {
+			var v uint32
+			if (iNdEx + 4) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Uast = append(m.Uast[:0], dAtA[iNdEx:postIndex]...)
-			if m.Uast == nil {
-				m.Uast = []byte{}
-			}
-			iNdEx = postIndex
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Percent = float32(math.Float32frombits(v))
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Language", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Phase", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2121,41 +4754,7 @@ func (m *ParseResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Language = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDriver
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthDriver
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthDriver
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Errors = append(m.Errors, &ParseError{})
-			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Phase = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2182,7 +4781,7 @@ func (m *ParseResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ParseError) Unmarshal(dAtA []byte) error {
+func (m *ParseProgressResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2205,17 +4804,17 @@ func (m *ParseError) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ParseError: wiretype end group for non-group")
+			return fmt.Errorf("proto: ParseProgressResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ParseError: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ParseProgressResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDriver
@@ -2225,23 +4824,61 @@ func (m *ParseError) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthDriver
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthDriver
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Text = string(dAtA[iNdEx:postIndex])
+			v := &Progress{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &ParseProgressResponse_Progress{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ParseResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &ParseProgressResponse_Response{v}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2362,6 +4999,70 @@ func (m *Version) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DriverVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DriverVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LanguageVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDriver
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDriver
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDriver
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LanguageVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDriver(dAtA[iNdEx:])
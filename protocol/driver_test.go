@@ -1,32 +1,63 @@
 package protocol
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf16"
 	"unsafe"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	serrors "gopkg.in/src-d/go-errors.v1"
 
 	"github.com/bblfsh/sdk/v3/driver"
 	"github.com/bblfsh/sdk/v3/driver/manifest"
+	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/nodes/nodesproto"
+	"github.com/bblfsh/sdk/v3/uast/role"
+	"github.com/bblfsh/sdk/v3/uast/transformer/positioner"
 )
 
 var _ driver.Driver = (*driverMock)(nil)
 
 type driverMock struct {
 	name string
+	opts *driver.ParseOptions
 	uast nodes.Node
 	vers driver.Version
 	list []manifest.Manifest
+	warn []string
 	err  error
+	src  string
+	prog []float64
 }
 
 func (d *driverMock) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	d.src = src
+	d.opts = opts
+	driver.AddWarnings(ctx, d.warn...)
+	for _, p := range d.prog {
+		driver.ReportProgress(ctx, driver.PhaseNativeParse, p)
+	}
 	return d.uast, d.err
 }
 
@@ -63,6 +94,46 @@ func equalError(t testing.TB, exp, got error) {
 	require.Equal(t, exp, got)
 }
 
+// serveDriver starts a gRPC server for a given driver and returns a client for it.
+func serveDriver(t testing.TB, d driver.Driver) (driver.Driver, func()) {
+	cc, closer := serveGRPC(t, d)
+	return AsDriver(cc), closer
+}
+
+// serveGRPC starts a gRPC server for a given driver and returns a connection to it.
+func serveGRPC(t testing.TB, d driver.Driver, ropts ...RegisterOption) (*grpc.ClientConn, func()) {
+	srv := grpc.NewServer(ServerOptions()...)
+	RegisterDriver(srv, d, ropts...)
+	errc := make(chan error, 1)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			errc <- err
+		}
+	}()
+
+	opts := append([]grpc.DialOption{grpc.WithInsecure()}, DialOptions()...)
+	cc, err := grpc.Dial(lis.Addr().String(), opts...)
+	if err != nil {
+		// maybe Serve failed to start the server?
+		select {
+		case serr := <-errc:
+			err = serr
+		default:
+		}
+	}
+	if err != nil {
+		lis.Close()
+	}
+	require.NoError(t, err)
+	return cc, func() {
+		cc.Close()
+		srv.Stop()
+		lis.Close()
+	}
+}
+
 func TestDriverError(t *testing.T) {
 	var cases = []driverMock{
 		{name: "success", uast: defaultUAST()},
@@ -77,32 +148,8 @@ func TestDriverError(t *testing.T) {
 		c := c
 		t.Run(c.name, func(t *testing.T) {
 			var d driver.Driver = &c
-			srv := grpc.NewServer(ServerOptions()...)
-			RegisterDriver(srv, d)
-			errc := make(chan error, 1)
-			lis, err := net.Listen("tcp", "localhost:0")
-			require.NoError(t, err)
-			defer lis.Close()
-			go func() {
-				if err := srv.Serve(lis); err != nil {
-					errc <- err
-				}
-			}()
-
-			opts := append([]grpc.DialOption{grpc.WithInsecure()}, DialOptions()...)
-			cc, err := grpc.Dial(lis.Addr().String(), opts...)
-			if err != nil {
-				// maybe Serve failed to start the server?
-				select {
-				case serr := <-errc:
-					err = serr
-				default:
-				}
-			}
-			require.NoError(t, err)
-			defer cc.Close()
-
-			cd := AsDriver(cc)
+			cd, closer := serveDriver(t, d)
+			defer closer()
 
 			nd, err := cd.Parse(context.Background(), "test", nil)
 			exp, eerr := d.Parse(context.Background(), "test", nil)
@@ -111,3 +158,1365 @@ func TestDriverError(t *testing.T) {
 		})
 	}
 }
+
+func TestDriverParseOptions(t *testing.T) {
+	d := &driverMock{uast: defaultUAST()}
+	cd, closer := serveDriver(t, d)
+	defer closer()
+
+	opts := &driver.ParseOptions{
+		Mode:     driver.ModeSemantic,
+		Language: "typescript",
+		Filename: "file.ts",
+		Options:  map[string]string{"dialect": "typescript"},
+	}
+	_, err := cd.Parse(context.Background(), "test", opts)
+	require.NoError(t, err)
+	require.Equal(t, opts, d.opts)
+}
+
+func TestDriverWarnings(t *testing.T) {
+	d := &driverMock{
+		uast: defaultUAST(),
+		warn: []string{"deprecated syntax", "recovered error"},
+	}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	resp, err := NewDriverClient(cc).Parse(context.Background(), &ParseRequest{Content: "test"})
+	require.NoError(t, err)
+	require.Empty(t, resp.Errors)
+	require.Equal(t, d.warn, resp.Warnings)
+
+	ctx, w := driver.WithWarnings(context.Background())
+	nd, err := AsDriver(cc).Parse(ctx, "test", nil)
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), nd)
+	require.Equal(t, d.warn, w.List())
+}
+
+func TestDriverPositionedErrors(t *testing.T) {
+	errs := []error{
+		&driver.PositionedError{Text: "unexpected token", Position: &uast.Position{Offset: 4, Line: 1, Col: 5}},
+		&driver.PositionedError{
+			Text:     "missing semicolon",
+			Severity: driver.SeverityWarning,
+			Position: &uast.Position{Offset: 12, Line: 2, Col: 3},
+		},
+	}
+	d := &driverMock{
+		uast: defaultUAST(),
+		err:  driver.ErrSyntax.Wrap(driver.JoinErrors(errs)),
+	}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	resp, err := NewDriverClient(cc).Parse(context.Background(), &ParseRequest{Content: "test"})
+	require.NoError(t, err)
+	require.Equal(t, []*ParseError{
+		{Text: "unexpected token", Position: &ErrorPosition{Offset: 4, Line: 1, Col: 5}},
+		{Text: "missing semicolon", Severity: Severity_SeverityWarning, Position: &ErrorPosition{Offset: 12, Line: 2, Col: 3}},
+	}, resp.Errors)
+
+	nd, err := AsDriver(cc).Parse(context.Background(), "test", nil)
+	require.True(t, driver.ErrSyntax.Is(err), "%v", err)
+	require.Equal(t, defaultUAST(), nd)
+	multi, ok := err.(*serrors.Error).Cause().(*driver.ErrMulti)
+	require.True(t, ok, "%T", err.(*serrors.Error).Cause())
+	require.Equal(t, errs, multi.Errors)
+	require.Equal(t, "1:5: unexpected token", multi.Errors[0].Error())
+
+	_, err = ParseStream(context.Background(), NewDriverClient(cc), &ParseRequest{Content: "test"}, func(ev *NodeEvent) error {
+		return nil
+	})
+	require.True(t, driver.ErrSyntax.Is(err), "%v", err)
+	multi, ok = err.(*serrors.Error).Cause().(*driver.ErrMulti)
+	require.True(t, ok, "%T", err.(*serrors.Error).Cause())
+	require.Equal(t, errs, multi.Errors)
+
+	// severity is encoded to JSON by name
+	data, err := json.Marshal(resp.Errors[1])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"severity":"SEVERITY_WARNING"`)
+	var pe ParseError
+	require.NoError(t, json.Unmarshal(data, &pe))
+	require.Equal(t, resp.Errors[1], &pe)
+	require.NoError(t, json.Unmarshal([]byte(`{"severity":1}`), &pe))
+	require.Equal(t, Severity_SeverityWarning, pe.Severity)
+}
+
+func TestDriverFormatJSON(t *testing.T) {
+	d := &driverMock{uast: defaultUAST()}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+	c := NewDriverClient(cc)
+
+	resp, err := c.Parse(context.Background(), &ParseRequest{Content: "test", Format: Format_FormatJSON})
+	require.NoError(t, err)
+	require.Empty(t, resp.Uast)
+	require.JSONEq(t, `{"k":"v"}`, string(resp.UastJSON))
+
+	nd, err := resp.Nodes()
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), nd)
+
+	resp, err = c.Parse(context.Background(), &ParseRequest{Content: "test"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Uast)
+	require.Empty(t, resp.UastJSON)
+
+	_, err = c.Parse(context.Background(), &ParseRequest{Content: "test", Format: Format(42)})
+	require.Error(t, err)
+}
+
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) string {
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xfeff}, units...)
+	}
+	buf := make([]byte, 2*len(units))
+	for i, u := range units {
+		order.PutUint16(buf[2*i:], u)
+	}
+	return string(buf)
+}
+
+func TestDecodeContent(t *testing.T) {
+	const src = "a = \"é😀\"; b"
+	cases := []struct {
+		name string
+		enc  Encoding
+		data string
+	}{
+		{name: "utf8", enc: Encoding_EncodingUTF8, data: src},
+		{name: "utf16", enc: Encoding_EncodingUTF16, data: encodeUTF16(src, binary.LittleEndian, false)},
+		{name: "utf16 le bom", enc: Encoding_EncodingUTF16, data: encodeUTF16(src, binary.LittleEndian, true)},
+		{name: "utf16 be bom", enc: Encoding_EncodingUTF16, data: encodeUTF16(src, binary.BigEndian, true)},
+		{name: "utf16le", enc: Encoding_EncodingUTF16LE, data: encodeUTF16(src, binary.LittleEndian, true)},
+		{name: "utf16be", enc: Encoding_EncodingUTF16BE, data: encodeUTF16(src, binary.BigEndian, false)},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			out, err := DecodeContent(c.data, c.enc)
+			require.NoError(t, err)
+			require.Equal(t, src, out)
+		})
+	}
+
+	_, err := DecodeContent("abc", Encoding_EncodingUTF16)
+	require.True(t, driver.ErrUnknownEncoding.Is(err))
+
+	_, err = DecodeContent("ab", Encoding(42))
+	require.True(t, driver.ErrUnknownEncoding.Is(err))
+}
+
+func TestParseEncoding(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		exp  Encoding
+	}{
+		{name: "utf8", exp: Encoding_EncodingUTF8},
+		{name: "UTF-8", exp: Encoding_EncodingUTF8},
+		{name: " utf-16le ", exp: Encoding_EncodingUTF16LE},
+		{name: "UTF16BE", exp: Encoding_EncodingUTF16BE},
+		{name: "ENCODING_BASE64", exp: Encoding_EncodingBase64},
+		{name: "base64", exp: Encoding_EncodingBase64},
+	} {
+		enc, err := ParseEncoding(c.name)
+		require.NoError(t, err, c.name)
+		require.Equal(t, c.exp, enc, c.name)
+
+		enc, err = ParseEncoding(c.exp.String())
+		require.NoError(t, err)
+		require.Equal(t, c.exp, enc)
+	}
+
+	for _, name := range []string{"", "utf32", "ENCODING_"} {
+		_, err := ParseEncoding(name)
+		require.Error(t, err, name)
+	}
+}
+
+func TestDriverEncodingUTF16(t *testing.T) {
+	const src = "a = \"é😀\"; b"
+	d := &driverMock{uast: defaultUAST()}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+	c := NewDriverClient(cc)
+
+	_, err := c.Parse(context.Background(), &ParseRequest{
+		RawContent: []byte(encodeUTF16(src, binary.BigEndian, true)),
+		Encoding:   Encoding_EncodingUTF16,
+	})
+	require.NoError(t, err)
+	require.Equal(t, src, d.src)
+
+	// UTF-16 is not a valid protobuf string
+	_, err = c.Parse(context.Background(), &ParseRequest{
+		Content:  encodeUTF16(src, binary.BigEndian, true),
+		Encoding: Encoding_EncodingUTF16,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "%v", err)
+
+	_, err = c.Parse(context.Background(), &ParseRequest{
+		Content:    "a",
+		RawContent: []byte("a"),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "%v", err)
+
+	// native parsers usually report offsets in UTF-16 code units; they must be remapped to UTF-8 bytes
+	const off16 = 11 // "b", after a surrogate pair
+	pos := uast.Position{Offset: off16}
+	out, err := positioner.FromUTF16Offset().OnCode(d.src).Do(pos.ToObject())
+	require.NoError(t, err)
+	got := uast.AsPosition(out.(nodes.Object))
+	require.Equal(t, uint32(strings.Index(src, "b")), got.Offset)
+	require.Equal(t, uint32(1), got.Line)
+
+	_, err = c.Parse(context.Background(), &ParseRequest{
+		RawContent: []byte("odd"),
+		Encoding:   Encoding_EncodingUTF16,
+	})
+	require.Error(t, err)
+}
+
+func TestDriverLimits(t *testing.T) {
+	d := &driverMock{uast: defaultUAST()}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	cd := AsDriverWithLimits(cc, nodesproto.Limits{MaxNodes: 3})
+	nd, err := cd.Parse(context.Background(), "test", nil)
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), nd)
+
+	cd = AsDriverWithLimits(cc, nodesproto.Limits{MaxDepth: 1})
+	_, err = cd.Parse(context.Background(), "test", nil)
+	require.Equal(t, &nodesproto.LimitError{Limit: "depth", Max: 1}, err)
+
+	// limits are enforced for the JSON format as well
+	resp, err := NewDriverClient(cc).Parse(context.Background(), &ParseRequest{Content: "test", Format: Format_FormatJSON})
+	require.NoError(t, err)
+	nd, err = resp.NodesWithLimits(nodesproto.Limits{MaxNodes: 3})
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), nd)
+	_, err = resp.NodesWithLimits(nodesproto.Limits{MaxNodes: 2})
+	require.Equal(t, &nodesproto.LimitError{Limit: "nodes", Max: 2}, err)
+}
+
+func TestDriverVersion(t *testing.T) {
+	d := &driverMock{vers: driver.Version{
+		Version:         "v1.2.3",
+		Build:           time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+		LanguageVersion: "3.7",
+	}}
+	cd, closer := serveDriver(t, d)
+	defer closer()
+
+	vers, err := cd.Version(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, d.vers, vers)
+
+	// old clients only read the version field
+	srv := &driverServer{d: d}
+	resp, err := srv.ServerVersion(context.Background(), &VersionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.3", resp.Version.Version)
+	require.Equal(t, "v1.2.3", resp.Version.DriverVersion)
+	require.Equal(t, "3.7", resp.Version.LanguageVersion)
+
+	// new servers may only set the driver version field
+	vers = (&Version{DriverVersion: "v1.2.3"}).toNative()
+	require.Equal(t, driver.Version{Version: "v1.2.3"}, vers)
+}
+
+type parseClientMock struct {
+	DriverClient
+	fail  string
+	calls int32
+}
+
+func (c *parseClientMock) Parse(ctx context.Context, req *ParseRequest, _ ...grpc.CallOption) (*ParseResponse, error) {
+	atomic.AddInt32(&c.calls, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if req.Filename == c.fail {
+		return nil, driver.ErrDriverFailure.New()
+	}
+	return &ParseResponse{Language: req.Filename}, nil
+}
+
+func TestParseAll(t *testing.T) {
+	var reqs []*ParseRequest
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		reqs = append(reqs, &ParseRequest{Filename: name})
+	}
+	c := &parseClientMock{fail: "c"}
+
+	res, err := ParseAll(context.Background(), c, reqs, 2, false)
+	require.NoError(t, err)
+	require.Len(t, res, len(reqs))
+	for i, r := range res {
+		if reqs[i].Filename == c.fail {
+			require.True(t, driver.ErrDriverFailure.Is(r.Err))
+			require.Nil(t, r.Response)
+			continue
+		}
+		require.NoError(t, r.Err)
+		require.Equal(t, reqs[i].Filename, r.Response.Language)
+	}
+
+	_, err = ParseAll(context.Background(), c, reqs, 2, true)
+	require.True(t, driver.ErrDriverFailure.Is(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.calls = 0
+	res, err = ParseAll(ctx, c, reqs, 2, false)
+	require.Equal(t, context.Canceled, err)
+	for _, r := range res {
+		require.Error(t, r.Err)
+	}
+	// no requests are issued after the cancellation, even if a concurrency token is available
+	require.Equal(t, int32(0), c.calls)
+}
+
+type langClientMock struct {
+	DriverClient
+	uast  nodes.Node
+	errs  []string
+	fail  bool
+	block bool
+}
+
+func (c *langClientMock) Parse(ctx context.Context, req *ParseRequest, _ ...grpc.CallOption) (*ParseResponse, error) {
+	if c.block {
+		<-ctx.Done()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.fail {
+		return nil, driver.ErrDriverFailure.New()
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := nodesproto.WriteTo(buf, c.uast); err != nil {
+		return nil, err
+	}
+	resp := &ParseResponse{Language: req.Language, Uast: buf.Bytes()}
+	for _, e := range c.errs {
+		resp.Errors = append(resp.Errors, &ParseError{Text: e})
+	}
+	return resp, nil
+}
+
+func TestParseBest(t *testing.T) {
+	ident := nodes.Object{
+		uast.KeyType:  nodes.String("Ident"),
+		uast.KeyRoles: uast.RoleList(role.Identifier),
+	}
+	clients := map[string]DriverClient{
+		"c":      &langClientMock{uast: nodes.Array{ident, ident}, errs: []string{"unexpected token"}},
+		"cpp":    &langClientMock{uast: nodes.Array{ident}},
+		"objc":   &langClientMock{uast: nodes.Array{nodes.Object{uast.KeyType: nodes.String("Ident")}}},
+		"broken": &langClientMock{fail: true},
+	}
+	req := &ParseRequest{Content: "int x;", Filename: "x.h"}
+
+	best, alts, err := ParseBest(context.Background(), clients, req, nil)
+	require.NoError(t, err)
+	require.Equal(t, "cpp", best.Language)
+	require.Equal(t, "cpp", best.Response.Language)
+	require.Equal(t, float64(1), best.Score)
+	var langs []string
+	for _, r := range alts {
+		langs = append(langs, r.Language)
+	}
+	require.Equal(t, []string{"objc", "c", "broken"}, langs)
+	require.True(t, driver.ErrSyntax.Is(alts[1].Err))
+	require.True(t, alts[2].Failed())
+
+	// prefer the largest tree, regardless of errors
+	size := func(r *LanguageResult) float64 {
+		return float64(nodes.Count(r.UAST, nodes.KindsNotNil))
+	}
+	best, _, err = ParseBest(context.Background(), clients, req, size)
+	require.NoError(t, err)
+	require.Equal(t, "c", best.Language)
+
+	best, alts, err = ParseBest(context.Background(), map[string]DriverClient{
+		"a": &langClientMock{fail: true},
+		"b": &langClientMock{fail: true},
+	}, req, nil)
+	require.True(t, driver.ErrDriverFailure.Is(err))
+	require.Nil(t, best)
+	require.Len(t, alts, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, _, err = ParseBest(ctx, map[string]DriverClient{
+		"cpp":  clients["cpp"],
+		"slow": &langClientMock{block: true},
+	}, req, nil)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestPeekParseRequest(t *testing.T) {
+	req := &ParseRequest{
+		Content:  "package main\n\nfunc main() {}\n",
+		Language: "go",
+		Filename: "main.go",
+		Mode:     Mode_Semantic,
+		Options:  map[string]string{"k": "v"},
+		Encoding: Encoding_EncodingBase64,
+	}
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	fname, lang, sz, uri, enc, err := PeekParseRequest(data)
+	require.NoError(t, err)
+	require.Equal(t, req.Filename, fname)
+	require.Equal(t, req.Language, lang)
+	require.Equal(t, len(req.Content), sz)
+	require.Empty(t, uri)
+	require.Equal(t, req.Encoding, enc)
+
+	// all truncated messages must be rejected the same way as Unmarshal does
+	for i := 0; i < len(data); i++ {
+		var r ParseRequest
+		uerr := r.Unmarshal(data[:i])
+		_, _, _, _, _, err := PeekParseRequest(data[:i])
+		require.Equal(t, uerr, err, "length: %d", i)
+	}
+
+	// the content may be stored in other fields
+	req = &ParseRequest{RawContent: []byte("abc"), Encoding: Encoding_EncodingUTF16LE}
+	data, err = req.Marshal()
+	require.NoError(t, err)
+	_, _, sz, _, enc, err = PeekParseRequest(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, sz)
+	require.Equal(t, req.Encoding, enc)
+
+	req = &ParseRequest{ContentURI: "file:///src/main.go"}
+	data, err = req.Marshal()
+	require.NoError(t, err)
+	_, _, sz, uri, _, err = PeekParseRequest(data)
+	require.NoError(t, err)
+	require.Equal(t, 0, sz)
+	require.Equal(t, req.ContentURI, uri)
+}
+
+func TestTreeBuilder(t *testing.T) {
+	arr := make(nodes.Array, 0, 2*streamBatchSize)
+	for i := 0; i < cap(arr); i++ {
+		arr = append(arr, nodes.Object{"i": nodes.Int(i)})
+	}
+	root := nodes.Object{
+		"arr":   arr,
+		"empty": nodes.Array{},
+		"obj":   nodes.Object{},
+		"null":  nil,
+		"str":   nodes.String("v"),
+		"int":   nodes.Int(-1),
+		"uint":  nodes.Uint(1),
+		"float": nodes.Float(1.5),
+		"bool":  nodes.Bool(true),
+		"nested": nodes.Array{
+			nil, nodes.Array{nodes.String("a")}, nodes.Object{"k": nil},
+		},
+	}
+	var b TreeBuilder
+	err := WalkEvents(root, b.Push)
+	require.NoError(t, err)
+	out, err := b.Node()
+	require.NoError(t, err)
+	require.Equal(t, root, out)
+
+	b = TreeBuilder{}
+	require.NoError(t, b.Push(&NodeEvent{Type: NodeEventType_ObjectStart}))
+	_, err = b.Node()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.Error(t, b.Push(&NodeEvent{Type: NodeEventType_ArrayEnd}))
+}
+
+// reversedObject is an external object that violates the contract and returns keys in a reverse order.
+type reversedObject struct {
+	nodes.Object
+}
+
+func (o reversedObject) Keys() []string {
+	keys := o.Object.Keys()
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	return keys
+}
+
+func TestWalkEventsOrder(t *testing.T) {
+	obj := nodes.Object{"a": nodes.Int(1), "b": nodes.Int(2)}
+	var keys []string
+	err := WalkEvents(reversedObject{obj}, func(ev *NodeEvent) error {
+		if ev.Type == NodeEventType_Value {
+			keys = append(keys, ev.Key)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestParseStream(t *testing.T) {
+	arr := make(nodes.Array, 0, 3*streamBatchSize)
+	for i := 0; i < cap(arr); i++ {
+		arr = append(arr, nodes.String("v"))
+	}
+	exp := nodes.Object{"arr": arr}
+
+	d := &driverMock{uast: exp, err: driver.ErrSyntax.Wrap(errors.New("invalid source"))}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	var (
+		b      TreeBuilder
+		events int
+	)
+	lang, err := ParseStream(context.Background(), NewDriverClient(cc), &ParseRequest{
+		Content: "test", Language: "go",
+	}, func(ev *NodeEvent) error {
+		events++
+		return b.Push(ev)
+	})
+	require.True(t, driver.ErrSyntax.Is(err), "%v", err)
+	require.Equal(t, "go", lang)
+	require.Equal(t, len(arr)+4, events)
+
+	out, err := b.Node()
+	require.NoError(t, err)
+	require.Equal(t, exp, out)
+
+	d.uast, d.err = nil, driver.ErrLanguageDetection.New()
+	_, err = ParseStream(context.Background(), NewDriverClient(cc), &ParseRequest{}, func(ev *NodeEvent) error {
+		return nil
+	})
+	require.True(t, driver.ErrLanguageDetection.Is(err), "%v", err)
+}
+
+func TestParseStreamPipeline(t *testing.T) {
+	const src = "a = \"é\""
+	d := &driverMock{uast: defaultUAST(), warn: []string{"deprecated syntax"}}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	var b TreeBuilder
+	ctx, w := driver.WithWarnings(context.Background())
+	_, err := ParseStream(ctx, NewDriverClient(cc), &ParseRequest{
+		RawContent: []byte(encodeUTF16(src, binary.LittleEndian, true)),
+		Encoding:   Encoding_EncodingUTF16,
+	}, b.Push)
+	require.NoError(t, err)
+	require.Equal(t, src, d.src)
+	require.Equal(t, d.warn, w.List())
+
+	out, err := b.Node()
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), out)
+}
+
+func TestParseWithProgress(t *testing.T) {
+	d := &driverMock{uast: defaultUAST(), prog: []float64{25, 75, 120}}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	var got []Progress
+	resp, err := ParseWithProgress(context.Background(), NewDriverClient(cc), &ParseRequest{
+		Content: "test", Language: "go",
+	}, func(p *Progress) {
+		got = append(got, *p)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Progress{
+		{Phase: driver.PhaseDecoding, Percent: 0},
+		{Phase: driver.PhaseNativeParse, Percent: 25},
+		{Phase: driver.PhaseNativeParse, Percent: 75},
+		{Phase: driver.PhaseNativeParse, Percent: 100},
+	}, got)
+	require.Equal(t, "go", resp.Language)
+	out, err := resp.Nodes()
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), out)
+
+	// parsers that don't report progress; clients may ignore progress events
+	d.prog = nil
+	resp, err = ParseWithProgress(context.Background(), NewDriverClient(cc), &ParseRequest{Content: "test"}, nil)
+	require.NoError(t, err)
+	out, err = resp.Nodes()
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), out)
+
+	d.uast, d.err = nil, driver.ErrLanguageDetection.New()
+	_, err = ParseWithProgress(context.Background(), NewDriverClient(cc), &ParseRequest{}, nil)
+	require.True(t, driver.ErrLanguageDetection.Is(err), "%v", err)
+}
+
+func largeParseResponse() *ParseResponse {
+	resp := &ParseResponse{
+		Language: "go",
+		Uast:     bytes.Repeat([]byte("uast"), 64*1024),
+	}
+	for i := 0; i < 100; i++ {
+		resp.Errors = append(resp.Errors, &ParseError{Text: "syntax error"})
+	}
+	return resp
+}
+
+func TestParseResponseMarshalAppend(t *testing.T) {
+	resp := largeParseResponse()
+	exp, err := resp.Marshal()
+	require.NoError(t, err)
+
+	prefix := []byte("prefix")
+	out, err := resp.MarshalAppend(append([]byte{}, prefix...))
+	require.NoError(t, err)
+	require.Equal(t, prefix, out[:len(prefix)])
+	require.Equal(t, exp, out[len(prefix):])
+
+	// reuse the buffer
+	out, err = resp.MarshalAppend(out[:0])
+	require.NoError(t, err)
+	require.Equal(t, exp, out)
+
+	var got ParseResponse
+	require.NoError(t, got.Unmarshal(out))
+	require.Equal(t, resp.Uast, got.Uast)
+}
+
+func TestUnknownFields(t *testing.T) {
+	req := &ParseRequest{Content: "test", Language: "go"}
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	// field 100 with varint 42, and field 101 with a length-delimited value "new"
+	unknown := []byte{0xa0, 0x06, 0x2a, 0xaa, 0x06, 0x03, 'n', 'e', 'w'}
+	data = append(data, unknown...)
+
+	var got ParseRequest
+	require.NoError(t, got.Unmarshal(data))
+	require.Equal(t, req.Content, got.Content)
+	require.Equal(t, req.Language, got.Language)
+	require.Equal(t, unknown, got.XXX_unrecognized)
+
+	out, err := got.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, data, out)
+
+	// the same applies to nested messages
+	resp := &ParseResponse{Errors: []*ParseError{{Text: "err", XXX_unrecognized: unknown}}}
+	buf, err := resp.MarshalAppend(nil)
+	require.NoError(t, err)
+	var resp2 ParseResponse
+	require.NoError(t, resp2.Unmarshal(buf))
+	require.Equal(t, unknown, resp2.Errors[0].XXX_unrecognized)
+}
+
+func BenchmarkParseResponseMarshal(b *testing.B) {
+	resp := largeParseResponse()
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := resp.Marshal(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("MarshalAppend", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			var err error
+			buf, err = resp.MarshalAppend(buf[:0])
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestServeStdio(t *testing.T) {
+	d := &driverMock{uast: defaultUAST()}
+	in := strings.Join([]string{
+		`{"content":"a","filename":"a.go"}`,
+		`{"content":`,
+		`{"content":"b","format":1}`,
+		`{"content":"c"}`, // not terminated
+	}, "\n")
+	out := bytes.NewBuffer(nil)
+	err := ServeStdio(context.Background(), strings.NewReader(in), out, d)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+
+	var resp StdioResponse
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &resp))
+	require.Empty(t, resp.Error)
+	nd, err := resp.Nodes()
+	require.NoError(t, err)
+	require.Equal(t, defaultUAST(), nd)
+
+	resp = StdioResponse{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &resp))
+	require.Nil(t, resp.ParseResponse)
+	require.Equal(t, codes.InvalidArgument.String(), resp.Code)
+	require.Contains(t, resp.Error, "invalid request")
+
+	resp = StdioResponse{}
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &resp))
+	require.Empty(t, resp.Error)
+	require.JSONEq(t, `{"k":"v"}`, string(resp.UastJSON))
+
+	// the last request is processed even without a new line
+	resp = StdioResponse{}
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &resp))
+	require.Empty(t, resp.Error)
+	require.Equal(t, "c", d.src)
+
+	// a trailing new line does not produce an empty request
+	out.Reset()
+	err = ServeStdio(context.Background(), strings.NewReader(`{"content":"a"}`+"\n"), out, d)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(out.String(), "\n"))
+
+	d.err = driver.ErrDriverFailure.New()
+	out.Reset()
+	err = ServeStdio(context.Background(), strings.NewReader(`{"content":"a"}`+"\n"), out, d)
+	require.NoError(t, err)
+	resp = StdioResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp))
+	require.Nil(t, resp.ParseResponse)
+	require.Equal(t, codes.Internal.String(), resp.Code)
+}
+
+func TestRequestFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bblfsh-request")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := "package main\n\n// ё\n"
+	path := filepath.Join(dir, "main.GO")
+	require.NoError(t, ioutil.WriteFile(path, []byte(src), 0644))
+
+	req, err := RequestFromFile(path)
+	require.NoError(t, err)
+	require.Equal(t, &ParseRequest{
+		Filename: "main.GO",
+		Language: "go",
+		Content:  src,
+		Encoding: Encoding_EncodingUTF8,
+	}, req)
+
+	bin := []byte{0xff, 0xfe, 0x00, 'a', 0xc3}
+	path = filepath.Join(dir, "data.unknown")
+	require.NoError(t, ioutil.WriteFile(path, bin, 0644))
+
+	req, err = RequestFromFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "data.unknown", req.Filename)
+	require.Equal(t, "", req.Language)
+	require.Equal(t, Encoding_EncodingBase64, req.Encoding)
+	out, err := DecodeContent(req.Content, req.Encoding)
+	require.NoError(t, err)
+	require.Equal(t, string(bin), out)
+
+	_, err = DecodeContent("not base64!", Encoding_EncodingBase64)
+	require.True(t, driver.ErrUnknownEncoding.Is(err), "%v", err)
+
+	_, err = RequestFromFile(filepath.Join(dir, "missing.go"))
+	require.Error(t, err)
+}
+
+type fetcherFunc func(ctx context.Context, uri *url.URL) ([]byte, error)
+
+func (f fetcherFunc) Fetch(ctx context.Context, uri *url.URL) ([]byte, error) {
+	return f(ctx, uri)
+}
+
+func TestParseContentURI(t *testing.T) {
+	root, err := ioutil.TempDir("", "bblfsh-root")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	out, err := ioutil.TempDir("", "bblfsh-outside")
+	require.NoError(t, err)
+	defer os.RemoveAll(out)
+
+	const src = "package main\n"
+	path := filepath.Join(root, "main.go")
+	require.NoError(t, ioutil.WriteFile(path, []byte(src), 0644))
+	secret := filepath.Join(out, "secret")
+	require.NoError(t, ioutil.WriteFile(secret, []byte("secret"), 0644))
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Symlink(secret, link))
+
+	fileURI := func(path string) string {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+
+	d := &driverMock{uast: defaultUAST()}
+	cc, closer := serveGRPC(t, d,
+		WithContentRoot(root),
+		WithContentFetcher("mem", fetcherFunc(func(ctx context.Context, uri *url.URL) ([]byte, error) {
+			return []byte("mem:" + uri.Opaque), nil
+		})),
+	)
+	defer closer()
+	c := NewDriverClient(cc)
+	ctx := context.Background()
+
+	_, err = c.Parse(ctx, &ParseRequest{ContentURI: fileURI(path)})
+	require.NoError(t, err)
+	require.Equal(t, src, d.src)
+
+	_, err = c.Parse(ctx, &ParseRequest{ContentURI: "mem:abc"})
+	require.NoError(t, err)
+	require.Equal(t, "mem:abc", d.src)
+
+	for _, c2 := range []struct {
+		name string
+		req  *ParseRequest
+		code codes.Code
+	}{
+		{"both", &ParseRequest{Content: src, ContentURI: fileURI(path)}, codes.InvalidArgument},
+		{"scheme", &ParseRequest{ContentURI: "s3://bucket/main.go"}, codes.InvalidArgument},
+		{"host", &ParseRequest{ContentURI: "file://example.com" + filepath.ToSlash(path)}, codes.InvalidArgument},
+		{"missing", &ParseRequest{ContentURI: fileURI(filepath.Join(root, "missing.go"))}, codes.NotFound},
+		{"outside", &ParseRequest{ContentURI: fileURI(secret)}, codes.PermissionDenied},
+		{"relative", &ParseRequest{ContentURI: fileURI(filepath.Join(root, "..", filepath.Base(out), "secret"))}, codes.PermissionDenied},
+		{"symlink", &ParseRequest{ContentURI: fileURI(link)}, codes.PermissionDenied},
+	} {
+		_, err = c.Parse(ctx, c2.req)
+		require.Error(t, err, c2.name)
+		require.Equal(t, c2.code, status.Code(err), "%s: %v", c2.name, err)
+	}
+
+	// file URIs are disabled by default
+	cc2, closer2 := serveGRPC(t, d)
+	defer closer2()
+	_, err = NewDriverClient(cc2).Parse(ctx, &ParseRequest{ContentURI: fileURI(path)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "%v", err)
+}
+
+func TestParseRequestJSON(t *testing.T) {
+	req := &ParseRequest{
+		Content:    "AAE=",
+		Language:   "go",
+		Filename:   "main.go",
+		Mode:       Mode_Semantic,
+		Options:    map[string]string{"dialect": "x"},
+		Format:     Format_FormatJSON,
+		Encoding:   Encoding_EncodingBase64,
+		ContentURI: "file:///main.go",
+	}
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"content": "AAE=",
+		"language": "go",
+		"filename": "main.go",
+		"mode": "SEMANTIC",
+		"options": {"dialect": "x"},
+		"format": "FORMAT_JSON",
+		"encoding": "ENCODING_BASE64",
+		"content_uri": "file:///main.go"
+	}`, string(data))
+
+	var req2 ParseRequest
+	require.NoError(t, json.Unmarshal(data, &req2))
+	require.Equal(t, req, &req2)
+
+	// numeric values are accepted as well
+	require.NoError(t, json.Unmarshal([]byte(`{"mode": 4, "encoding": 1}`), &req2))
+	require.Equal(t, Mode_Annotated, req2.Mode)
+	require.Equal(t, Encoding_EncodingUTF16, req2.Encoding)
+
+	err = json.Unmarshal([]byte(`{"mode": "UNKNOWN"}`), &req2)
+	require.Error(t, err)
+
+	resp := &ParseResponse{
+		Uast:     []byte{0, 1, 2},
+		Language: "go",
+		Errors:   []*ParseError{{Text: "syntax error"}},
+		Warnings: []string{"deprecated"},
+		UastJSON: []byte(`{}`),
+	}
+	data, err = json.Marshal(resp)
+	require.NoError(t, err)
+	var resp2 ParseResponse
+	require.NoError(t, json.Unmarshal(data, &resp2))
+	require.Equal(t, resp, &resp2)
+}
+
+func TestResponseJSON(t *testing.T) {
+	ast := nodes.Object{
+		uast.KeyType:  nodes.String("File"),
+		uast.KeyRoles: uast.RoleList(role.File),
+		uast.KeyPos: uast.Positions{
+			uast.KeyStart: {Offset: 0, Line: 1, Col: 1},
+			uast.KeyEnd:   {Offset: 12, Line: 2, Col: 5},
+		}.ToObject(),
+		"Body": nodes.Array{
+			nodes.Object{
+				uast.KeyType:  nodes.String("Ident"),
+				uast.KeyToken: nodes.String("a"),
+				uast.KeyRoles: uast.RoleList(role.Identifier, role.Expression),
+				"Ctx":         nil,
+			},
+			nodes.Object{
+				uast.KeyType: nodes.String("Num"),
+				"Value":      nodes.Float(1.5),
+				"Neg":        nodes.Int(-3),
+				"Ok":         nodes.Bool(true),
+			},
+		},
+	}
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, nodesproto.WriteTo(buf, ast))
+	resp := &ParseResponse{
+		Uast:     buf.Bytes(),
+		Language: "go",
+		Errors:   []*ParseError{{Text: "syntax error"}},
+		Warnings: []string{"deprecated"},
+	}
+
+	data, err := MarshalResponseJSON(resp)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"uast_tree":{"@pos":{`)
+	require.Contains(t, string(data), `"@role":["File"]`)
+
+	resp2, err := UnmarshalResponseJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, resp.Language, resp2.Language)
+	require.Equal(t, resp.Errors, resp2.Errors)
+	require.Equal(t, resp.Warnings, resp2.Warnings)
+	require.Empty(t, resp2.UastJSON)
+	// positions are restored as unsigned values, thus the binary UAST is the same
+	require.Equal(t, resp.Uast, resp2.Uast)
+
+	out, err := resp2.Nodes()
+	require.True(t, driver.ErrSyntax.Is(err))
+	require.Equal(t, ast, out)
+
+	// the round trip is stable
+	data2, err := MarshalResponseJSON(resp2)
+	require.NoError(t, err)
+	require.Equal(t, string(data), string(data2))
+
+	// UAST in JSON format is preserved as is, and the format is decoded by its name
+	resp = &ParseResponse{UastJSON: []byte(`{"k":"v"}`)}
+	data, err = MarshalResponseJSON(resp)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"format":"FORMAT_JSON","uast_tree":{"k":"v"}}`, string(data))
+	resp2, err = UnmarshalResponseJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, resp, resp2)
+
+	_, err = UnmarshalResponseJSON([]byte(`{"format":"FORMAT_XML","uast_tree":{}}`))
+	require.Error(t, err)
+
+	// the default JSON encoding of the response stores the UAST as base64 and must be rejected
+	data, err = json.Marshal(&ParseResponse{Uast: buf.Bytes(), Language: "go"})
+	require.NoError(t, err)
+	_, err = UnmarshalResponseJSON(data)
+	require.Error(t, err)
+
+	_, err = UnmarshalResponseJSON([]byte(`{"uast_tree":"AAEC"}`))
+	require.Error(t, err)
+}
+
+// flakyDriver fails with a given error for the first fails calls.
+type flakyDriver struct {
+	driverMock
+	fails int
+	calls int
+	ferr  error
+}
+
+func (d *flakyDriver) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	d.calls++
+	if d.calls <= d.fails {
+		return nil, d.ferr
+	}
+	return d.driverMock.Parse(ctx, src, opts)
+}
+
+func (d *flakyDriver) Version(ctx context.Context) (driver.Version, error) {
+	d.calls++
+	if d.calls <= d.fails {
+		return driver.Version{}, d.ferr
+	}
+	return d.driverMock.Version(ctx)
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	t.Run("driver failure", func(t *testing.T) {
+		d := &flakyDriver{
+			driverMock: driverMock{uast: defaultUAST()},
+			fails:      2, ferr: driver.ErrDriverFailure.New(),
+		}
+		// errors must survive the gRPC round trip
+		cli, closer := serveDriver(t, d)
+		defer closer()
+
+		out, err := WithRetry(cli, policy).Parse(ctx, "x", nil)
+		require.NoError(t, err)
+		require.Equal(t, defaultUAST(), out)
+		require.Equal(t, 3, d.calls)
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		d := &flakyDriver{
+			driverMock: driverMock{vers: driver.Version{Version: "1.0"}},
+			fails:      1, ferr: status.Error(codes.Unavailable, "restarting"),
+		}
+		vers, err := WithRetry(d, policy).Version(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", vers.Version)
+		require.Equal(t, 2, d.calls)
+	})
+	t.Run("exhausted", func(t *testing.T) {
+		d := &flakyDriver{fails: 10, ferr: driver.ErrDriverFailure.New()}
+		_, err := WithRetry(d, policy).Parse(ctx, "x", nil)
+		require.True(t, driver.ErrDriverFailure.Is(err), "%v", err)
+		require.Equal(t, 3, d.calls)
+	})
+	t.Run("syntax", func(t *testing.T) {
+		d := &flakyDriver{fails: 10, ferr: driver.ErrSyntax.New()}
+		_, err := WithRetry(d, policy).Parse(ctx, "x", nil)
+		require.True(t, driver.ErrSyntax.Is(err), "%v", err)
+		require.Equal(t, 1, d.calls)
+	})
+	t.Run("predicate", func(t *testing.T) {
+		d := &flakyDriver{
+			driverMock: driverMock{uast: defaultUAST()},
+			fails:      1, ferr: driver.ErrTransformFailure.New(),
+		}
+		p := policy
+		p.Retryable = driver.ErrTransformFailure.Is
+		_, err := WithRetry(d, p).Parse(ctx, "x", nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, d.calls)
+
+		d = &flakyDriver{fails: 1, ferr: driver.ErrDriverFailure.New()}
+		_, err = WithRetry(d, p).Parse(ctx, "x", nil)
+		require.Error(t, err)
+		require.Equal(t, 1, d.calls)
+	})
+	t.Run("deadline", func(t *testing.T) {
+		d := &flakyDriver{fails: 10, ferr: driver.ErrDriverFailure.New()}
+		dctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		p := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute}
+		start := time.Now()
+		_, err := WithRetry(d, p).Parse(dctx, "x", nil)
+		require.True(t, driver.ErrDriverFailure.Is(err), "%v", err)
+		require.Equal(t, 1, d.calls)
+		require.True(t, time.Since(start) < time.Second)
+	})
+}
+
+func TestParsePreprocessors(t *testing.T) {
+	const bom = "\ufeff"
+	stripBOM := driver.PreprocessorFunc(func(ctx context.Context, src string) (string, driver.OffsetMap, error) {
+		if !strings.HasPrefix(src, bom) {
+			return src, nil, nil
+		}
+		return src[len(bom):], func(off int) int { return off + len(bom) }, nil
+	})
+	// the mock driver reports positions relative to the preprocessed content: "a\nbc\n"
+	ident := func(start, end uast.Position) nodes.Node {
+		n, err := uast.ToNode(uast.Identifier{
+			GenNode: uast.GenNode{Positions: uast.Positions{uast.KeyStart: start, uast.KeyEnd: end}},
+			Name:    "bc",
+		})
+		require.NoError(t, err)
+		return n
+	}
+	d := &driverMock{uast: ident(
+		uast.Position{Offset: 2, Line: 2, Col: 1},
+		uast.Position{Offset: 4, Line: 2, Col: 3},
+	)}
+	cc, closer := serveGRPC(t, d, WithPreprocessors(stripBOM, driver.NormalizeLineEndings()))
+	defer closer()
+	c := NewDriverClient(cc)
+
+	const src = bom + "a\r\nbc\r\n"
+	resp, err := c.Parse(context.Background(), &ParseRequest{Content: src})
+	require.NoError(t, err)
+	require.Equal(t, "a\nbc\n", d.src)
+
+	ast, err := resp.Nodes()
+	require.NoError(t, err)
+	exp := ident(
+		uast.Position{Offset: 6, Line: 2, Col: 1},
+		uast.Position{Offset: 8, Line: 2, Col: 3},
+	)
+	require.True(t, nodes.Equal(exp, ast), "%v", ast)
+	require.Equal(t, "bc", src[6:8])
+
+	// content without CRLF is not changed
+	d.uast = defaultUAST()
+	_, err = c.Parse(context.Background(), &ParseRequest{Content: "a\nb"})
+	require.NoError(t, err)
+	require.Equal(t, "a\nb", d.src)
+
+	// positions of parsing errors are remapped as well
+	d.err = driver.ErrSyntax.Wrap(&driver.PositionedError{
+		Text: "unexpected token", Position: &uast.Position{Offset: 2, Line: 2, Col: 1},
+	})
+	resp, err = c.Parse(context.Background(), &ParseRequest{Content: src})
+	require.NoError(t, err)
+	require.Equal(t, []*ParseError{
+		{Text: "unexpected token", Position: &ErrorPosition{Offset: 6, Line: 2, Col: 1}},
+	}, resp.Errors)
+}
+
+// slowDriver blocks the parsing until the delay expires, or until the context is cancelled if it is context-aware.
+type slowDriver struct {
+	driverMock
+	delay    time.Duration
+	ctxAware bool
+}
+
+func (d *slowDriver) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	t := time.NewTimer(d.delay)
+	defer t.Stop()
+	if d.ctxAware {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	} else {
+		<-t.C
+	}
+	return d.uast, nil
+}
+
+func TestParseTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	for _, c := range []struct {
+		name string
+		d    *slowDriver
+	}{
+		{"context-aware", &slowDriver{delay: time.Minute, ctxAware: true}},
+		{"ignores context", &slowDriver{delay: 2 * time.Second}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cc, closer := serveGRPC(t, c.d, WithParseTimeout(timeout))
+			defer closer()
+
+			start := time.Now()
+			_, err := NewDriverClient(cc).Parse(context.Background(), &ParseRequest{Content: "x"})
+			require.Equal(t, codes.DeadlineExceeded, status.Code(err), "%v", err)
+			require.True(t, time.Since(start) < time.Second, "%v", time.Since(start))
+		})
+	}
+
+	// fast requests are not affected
+	d := &slowDriver{driverMock: driverMock{uast: defaultUAST()}, delay: time.Millisecond, ctxAware: true}
+	cc, closer := serveGRPC(t, d, WithParseTimeout(time.Minute))
+	defer closer()
+	_, err := AsDriver(cc).Parse(context.Background(), "x", nil)
+	require.NoError(t, err)
+}
+
+// lateProgressDriver ignores the context and reports progress after the parse timeout expires.
+type lateProgressDriver struct {
+	driverMock
+	delay    time.Duration
+	reported chan struct{}
+}
+
+func (d *lateProgressDriver) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	time.Sleep(d.delay)
+	driver.ReportProgress(ctx, driver.PhaseNativeParse, 50)
+	close(d.reported)
+	return d.uast, nil
+}
+
+// progressServerMock records messages sent to the stream and fails the test if the stream is used
+// after the handler returns.
+type progressServerMock struct {
+	grpc.ServerStream
+	t      testing.TB
+	mu     sync.Mutex
+	closed bool
+	msgs   []*ParseProgressResponse
+}
+
+func (s *progressServerMock) Context() context.Context {
+	return context.Background()
+}
+
+func (s *progressServerMock) Send(m *ParseProgressResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		s.t.Error("stream is used after the handler returned")
+	}
+	s.msgs = append(s.msgs, m)
+	return nil
+}
+
+func TestParseWithProgressTimeout(t *testing.T) {
+	d := &lateProgressDriver{delay: 50 * time.Millisecond, reported: make(chan struct{})}
+	s := &driverServer{d: d}
+	WithParseTimeout(time.Millisecond)(s)
+
+	srv := &progressServerMock{t: t}
+	err := s.ParseWithProgress(&ParseRequest{Content: "x"}, srv)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err), "%v", err)
+
+	srv.mu.Lock()
+	srv.closed = true
+	srv.mu.Unlock()
+	<-d.reported
+}
+
+// echoDriver returns the source as a string node and tracks the number of concurrent requests.
+type echoDriver struct {
+	driverMock
+	delay  time.Duration
+	active int32
+	max    int32
+}
+
+func (d *echoDriver) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	n := atomic.AddInt32(&d.active, 1)
+	defer atomic.AddInt32(&d.active, -1)
+	for {
+		m := atomic.LoadInt32(&d.max)
+		if n <= m || atomic.CompareAndSwapInt32(&d.max, m, n) {
+			break
+		}
+	}
+	time.Sleep(d.delay)
+	return nodes.String(src), nil
+}
+
+func TestMuxClient(t *testing.T) {
+	const (
+		workers  = 20
+		inFlight = 3
+	)
+	d := &echoDriver{delay: 20 * time.Millisecond}
+	cc, closer := serveGRPC(t, d)
+	defer closer()
+
+	c := NewMuxClient(cc, MuxOptions{MaxInFlight: inFlight})
+	ctx := context.Background()
+
+	var (
+		wg   sync.WaitGroup
+		errc = make(chan error, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src := fmt.Sprintf("worker %d", i)
+			resp, err := c.Parse(ctx, &ParseRequest{Content: src})
+			if err != nil {
+				errc <- err
+				return
+			}
+			ast, err := resp.Nodes()
+			if err != nil {
+				errc <- err
+				return
+			} else if ast != nodes.String(src) {
+				errc <- fmt.Errorf("unexpected response for %q: %v", src, ast)
+			}
+		}(i)
+	}
+	// wait for the requests to queue up
+	var st MuxStats
+	for i := 0; i < 100; i++ {
+		if st = c.Stats(); st.Queued != 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(t, inFlight, st.InFlight)
+	require.True(t, st.Queued > 0)
+
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(inFlight), atomic.LoadInt32(&d.max))
+	require.Equal(t, MuxStats{MaxInFlight: inFlight}, c.Stats())
+
+	// cancelled requests leave the queue
+	for i := 0; i < inFlight; i++ {
+		require.NoError(t, c.acquire(ctx))
+	}
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := c.Parse(cctx, &ParseRequest{Content: "x"})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, MuxStats{InFlight: inFlight, MaxInFlight: inFlight}, c.Stats())
+	for i := 0; i < inFlight; i++ {
+		c.release()
+	}
+
+	// streams hold the slot until they are received
+	s, err := c.ParseWithProgress(ctx, &ParseRequest{Content: "x"})
+	require.NoError(t, err)
+	require.Equal(t, 1, c.Stats().InFlight)
+	for {
+		resp, err := s.Recv()
+		require.NoError(t, err)
+		if resp.GetResponse() != nil {
+			break
+		}
+	}
+	require.Equal(t, 0, c.Stats().InFlight)
+}
+
+func TestSniffDirectives(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		lang    string
+		charset string
+	}{
+		{name: "env", src: "#!/usr/bin/env python3\nprint(1)\n", lang: "python"},
+		{name: "path", src: "#!/bin/bash\necho 1\n", lang: "bash"},
+		{name: "space", src: "#! /usr/bin/ruby -w\n", lang: "ruby"},
+		{name: "env flags", src: "#!/usr/bin/env -S NODE_ENV=test node --harmony\n", lang: "javascript"},
+		{name: "version", src: "#!/usr/local/bin/python2.7\r\n", lang: "python"},
+		{name: "unknown", src: "#!/usr/bin/env awk -f\n"},
+		{name: "coding", src: "# -*- coding: utf-8 -*-\nimport os\n", charset: "utf-8"},
+		{name: "shebang and coding", src: "#!/usr/bin/env python\n# coding=Latin-1\n", lang: "python", charset: "iso-8859-1"},
+		{name: "vim", src: "\xef\xbb\xbf# vim: set fileencoding=UTF_8 :\n", charset: "utf-8"},
+		{name: "third line", src: "#!/bin/sh\n\n# coding: utf-8\n", lang: "bash"},
+		{name: "not a comment", src: "x = 1 # coding: utf-8\n"},
+		{name: "none", src: "package main\n\nfunc main() {}\n"},
+		{name: "empty"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			lang, charset, ok := SniffDirectives([]byte(c.src))
+			require.Equal(t, c.lang, lang)
+			require.Equal(t, c.charset, charset)
+			require.Equal(t, c.lang != "" || c.charset != "", ok)
+		})
+	}
+}
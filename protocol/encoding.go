@@ -0,0 +1,82 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/bblfsh/sdk/v3/driver"
+)
+
+// DecodeContent converts the content of the source file in a given encoding to UTF-8.
+//
+// Base64 content is decoded to its original bytes, which are not required to be a valid UTF-8.
+//
+// For UTF-16 encodings, the byte order mark is skipped, if present. The byte order of EncodingUTF16 is
+// detected from the BOM, and defaults to little-endian. Unpaired surrogates are replaced with U+FFFD.
+//
+// Note that the UAST positions will be relative to the UTF-8 content. If the native parser reports
+// offsets in UTF-16 code units, drivers should use positioner.FromUTF16Offset to remap them.
+//
+// It returns driver.ErrUnknownEncoding if the content cannot be decoded.
+func DecodeContent(content string, enc Encoding) (string, error) {
+	var order binary.ByteOrder
+	switch enc {
+	case Encoding_EncodingUTF8:
+		return content, nil
+	case Encoding_EncodingUTF16, Encoding_EncodingUTF16LE:
+		order = binary.LittleEndian
+	case Encoding_EncodingUTF16BE:
+		order = binary.BigEndian
+	case Encoding_EncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", driver.ErrUnknownEncoding.Wrap(err)
+		}
+		return string(data), nil
+	default:
+		return "", driver.ErrUnknownEncoding.Wrap(fmt.Errorf("unsupported encoding: %v", enc))
+	}
+	data := []byte(content)
+	if len(data)%2 != 0 {
+		return "", driver.ErrUnknownEncoding.Wrap(fmt.Errorf("odd number of bytes in UTF-16 content: %d", len(data)))
+	}
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xff && data[1] == 0xfe && enc != Encoding_EncodingUTF16BE:
+			order = binary.LittleEndian
+			data = data[2:]
+		case data[0] == 0xfe && data[1] == 0xff && enc != Encoding_EncodingUTF16LE:
+			order = binary.BigEndian
+			data = data[2:]
+		}
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// isUTF16 checks if the encoding is one of UTF-16 encodings.
+func isUTF16(enc Encoding) bool {
+	switch enc {
+	case Encoding_EncodingUTF16, Encoding_EncodingUTF16LE, Encoding_EncodingUTF16BE:
+		return true
+	}
+	return false
+}
+
+// ParseEncoding parses an encoding name, as it may be specified by the user in a command line flag or a config file.
+// Names are case-insensitive and may omit the "ENCODING_" prefix and dashes, e.g. "utf-16le" or "base64".
+func ParseEncoding(s string) (Encoding, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	name = strings.TrimPrefix(name, "ENCODING_")
+	name = strings.Replace(name, "-", "", -1)
+	if v, ok := Encoding_value["ENCODING_"+name]; ok {
+		return Encoding(v), nil
+	}
+	return 0, fmt.Errorf("unknown encoding: %q", s)
+}
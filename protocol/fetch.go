@@ -0,0 +1,106 @@
+package protocol
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContentFetcher reads the content of source files referenced by ParseRequest.ContentURI.
+type ContentFetcher interface {
+	// Fetch returns the content of the file with a given URI.
+	Fetch(ctx context.Context, uri *url.URL) ([]byte, error)
+}
+
+// FileFetcher is a ContentFetcher for file:// URIs. It only allows reading files inside the Root directory.
+// All requests are rejected if the Root is not set.
+type FileFetcher struct {
+	Root string
+}
+
+// Fetch implements ContentFetcher.
+func (f FileFetcher) Fetch(ctx context.Context, uri *url.URL) ([]byte, error) {
+	if f.Root == "" {
+		return nil, status.Error(codes.PermissionDenied, "file URIs are disabled on the server")
+	}
+	if uri.Host != "" && uri.Host != "localhost" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported file URI host: %q", uri.Host)
+	}
+	root, err := filepath.Abs(f.Root)
+	if err != nil {
+		return nil, err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	// resolve symlinks to prevent them from pointing outside of the root
+	path, err := filepath.EvalSymlinks(filepath.FromSlash(uri.Path))
+	if os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound, "file not found: %q", uri.Path)
+	} else if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, status.Errorf(codes.PermissionDenied, "file is outside of the content root: %q", uri.Path)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// WithContentFetcher enables content URIs with a given scheme in parse requests.
+func WithContentFetcher(scheme string, f ContentFetcher) RegisterOption {
+	return func(s *driverServer) {
+		if s.fetchers == nil {
+			s.fetchers = make(map[string]ContentFetcher)
+		}
+		s.fetchers[strings.ToLower(scheme)] = f
+	}
+}
+
+// WithContentRoot enables file:// content URIs in parse requests. Only files inside the root directory
+// can be read by the server.
+func WithContentRoot(root string) RegisterOption {
+	return WithContentFetcher("file", FileFetcher{Root: root})
+}
+
+// content returns the source file content for the request, fetching it if ContentURI is set.
+func (s *driverServer) content(ctx context.Context, req *ParseRequest) (string, error) {
+	set := 0
+	for _, ok := range []bool{req.Content != "", len(req.RawContent) != 0, req.ContentURI != ""} {
+		if ok {
+			set++
+		}
+	}
+	switch {
+	case set > 1:
+		return "", status.Error(codes.InvalidArgument, "only one of content, raw content and content URI can be set")
+	case len(req.RawContent) != 0:
+		return string(req.RawContent), nil
+	case req.ContentURI == "":
+		if req.Content != "" && isUTF16(req.Encoding) {
+			// UTF-16 is not a valid protobuf string and is rejected by other protobuf runtimes
+			return "", status.Errorf(codes.InvalidArgument, "content with %v encoding must be sent as raw content", req.Encoding)
+		}
+		return req.Content, nil
+	}
+	uri, err := url.Parse(req.ContentURI)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid content URI: %v", err)
+	}
+	f := s.fetchers[strings.ToLower(uri.Scheme)]
+	if f == nil {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported content URI scheme: %q", uri.Scheme)
+	}
+	data, err := f.Fetch(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
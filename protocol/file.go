@@ -0,0 +1,63 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// extLanguages maps file extensions to languages of the official Babelfish drivers.
+var extLanguages = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".java": "java",
+	".js":   "javascript",
+	".jsx":  "javascript",
+	".mjs":  "javascript",
+	".ts":   "typescript",
+	".tsx":  "typescript",
+	".rb":   "ruby",
+	".php":  "php",
+	".sh":   "bash",
+	".bash": "bash",
+	".cs":   "csharp",
+	".c":    "cpp",
+	".h":    "cpp",
+	".cc":   "cpp",
+	".cpp":  "cpp",
+	".cxx":  "cpp",
+	".hpp":  "cpp",
+}
+
+// LanguageByFilename detects the language of the file from its extension.
+// It returns an empty string if the extension is unknown.
+//
+// It is also used by the polyglot server (see server.ParserRegistry) for requests without a language, unless the
+// extension was registered for a different driver explicitly. In this case, the registered driver takes precedence.
+func LanguageByFilename(filename string) string {
+	return extLanguages[strings.ToLower(filepath.Ext(filename))]
+}
+
+// RequestFromFile reads a file and creates a parse request for it. The language is detected from the file
+// extension, and is left empty if the extension is unknown. The filename is set to the base name of the path.
+//
+// Files that are not valid UTF-8 (e.g. binary files) are sent with the base64 encoding.
+func RequestFromFile(path string) (*ParseRequest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	req := &ParseRequest{
+		Filename: filepath.Base(path),
+		Language: LanguageByFilename(path),
+	}
+	if utf8.Valid(data) {
+		req.Content = string(data)
+	} else {
+		req.Content = base64.StdEncoding.EncodeToString(data)
+		req.Encoding = Encoding_EncodingBase64
+	}
+	return req, nil
+}
@@ -0,0 +1,37 @@
+//go:build gofuzz
+// +build gofuzz
+
+package protocol
+
+import "github.com/bblfsh/sdk/v3/uast/nodes/nodesproto"
+
+// fuzzLimits bounds the size of UAST decoded by the fuzzers, to catch unbounded allocations.
+var fuzzLimits = nodesproto.Limits{MaxDepth: 1000, MaxNodes: 1 << 20}
+
+// FuzzParseRequestUnmarshal is a go-fuzz entry point for decoding parse requests.
+func FuzzParseRequestUnmarshal(data []byte) int {
+	var req ParseRequest
+	if err := req.Unmarshal(data); err != nil {
+		return 0
+	}
+	out, err := req.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	if err = req.Unmarshal(out); err != nil {
+		panic(err)
+	}
+	return 1
+}
+
+// FuzzParseResponseUnmarshal is a go-fuzz entry point for decoding parse responses, including the UAST.
+func FuzzParseResponseUnmarshal(data []byte) int {
+	var resp ParseResponse
+	if err := resp.Unmarshal(data); err != nil {
+		return 0
+	}
+	if _, err := resp.NodesWithLimits(fuzzLimits); err != nil {
+		return 0
+	}
+	return 1
+}
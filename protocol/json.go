@@ -0,0 +1,224 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/nodes/nodesproto"
+	"github.com/bblfsh/sdk/v3/uast/uastjson"
+)
+
+// Enums used in parse requests and responses are encoded to JSON by their names, as defined in the protobuf file.
+// Numeric values are still accepted when decoding, and values without a name are encoded as numbers.
+var (
+	_ json.Marshaler   = Encoding(0)
+	_ json.Unmarshaler = (*Encoding)(nil)
+	_ json.Marshaler   = Format(0)
+	_ json.Unmarshaler = (*Format)(nil)
+	_ json.Marshaler   = Mode(0)
+	_ json.Unmarshaler = (*Mode)(nil)
+	_ json.Marshaler   = Severity(0)
+	_ json.Unmarshaler = (*Severity)(nil)
+)
+
+// MarshalJSON implements json.Marshaler.
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(int32(e), Encoding_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnumJSON(data, "encoding", Encoding_value)
+	if err != nil {
+		return err
+	}
+	*e = Encoding(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f Format) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(int32(f), Format_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Format) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnumJSON(data, "format", Format_value)
+	if err != nil {
+		return err
+	}
+	*f = Format(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Mode) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(int32(m), Mode_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Mode) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnumJSON(data, "mode", Mode_value)
+	if err != nil {
+		return err
+	}
+	*m = Mode(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(int32(s), Severity_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnumJSON(data, "severity", Severity_value)
+	if err != nil {
+		return err
+	}
+	*s = Severity(v)
+	return nil
+}
+
+func marshalEnumJSON(v int32, names map[int32]string) ([]byte, error) {
+	if name, ok := names[v]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(v)
+}
+
+func unmarshalEnumJSON(data []byte, typ string, values map[string]int32) (int32, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		v, ok := values[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown %s: %q", typ, name)
+		}
+		return v, nil
+	}
+	var v int32
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", typ, data)
+	}
+	return v, nil
+}
+
+// responseJSON is a self-describing JSON form of ParseResponse. The UAST is stored as a JSON tree instead of
+// the binary encoding, and the format field records which of the response fields it was taken from.
+//
+// The tree is stored under a key that differs from the ones used by the default JSON encoding of ParseResponse
+// ("uast" and "uast_json"), so the two forms cannot be confused. Those keys are only decoded to reject the input.
+type responseJSON struct {
+	Format   Format          `json:"format,omitempty"`
+	Tree     json.RawMessage `json:"uast_tree,omitempty"`
+	Language string          `json:"language,omitempty"`
+	Errors   []*ParseError   `json:"errors,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+
+	Binary json.RawMessage `json:"uast,omitempty"`
+	JSON   json.RawMessage `json:"uast_json,omitempty"`
+}
+
+// MarshalResponseJSON encodes the parse response to JSON, with the UAST stored as a JSON tree (see uastjson).
+// It is useful to persist parse responses in a human-readable form. See UnmarshalResponseJSON.
+func MarshalResponseJSON(resp *ParseResponse) ([]byte, error) {
+	r := responseJSON{
+		Language: resp.Language,
+		Errors:   resp.Errors,
+		Warnings: resp.Warnings,
+	}
+	if len(resp.Uast) != 0 {
+		ast, err := nodesproto.ReadTree(bytes.NewReader(resp.Uast))
+		if err != nil {
+			return nil, err
+		}
+		r.Tree, err = uastjson.Marshal(ast)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(resp.UastJSON) != 0 {
+		r.Format = Format_FormatJSON
+		r.Tree = bytes.TrimSpace(resp.UastJSON)
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalResponseJSON decodes the parse response from the JSON form produced by MarshalResponseJSON.
+//
+// The UAST is converted back to the format it was stored in the original response. JSON does not distinguish
+// signed and unsigned integers, thus fields of positional information (see uast.Position) are restored as unsigned
+// values. This makes the round trip lossless for trees produced by drivers: the binary UAST is the same as in the
+// original response.
+func UnmarshalResponseJSON(data []byte) (*ParseResponse, error) {
+	var r responseJSON
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	if len(r.Binary) != 0 || len(r.JSON) != 0 {
+		return nil, errors.New("parse response is not in the form produced by MarshalResponseJSON")
+	}
+	if len(r.Tree) != 0 && r.Tree[0] == '"' {
+		return nil, errors.New("expected a JSON tree for the UAST, got a string")
+	}
+	resp := &ParseResponse{
+		Language: r.Language,
+		Errors:   r.Errors,
+		Warnings: r.Warnings,
+	}
+	if len(r.Tree) == 0 {
+		return resp, nil
+	}
+	switch r.Format {
+	case Format_FormatProto:
+		ast, err := uastjson.Unmarshal(r.Tree)
+		if err != nil {
+			return nil, err
+		}
+		ast = restorePositions(ast)
+		buf := bytes.NewBuffer(nil)
+		if err = nodesproto.WriteTo(buf, ast); err != nil {
+			return nil, err
+		}
+		resp.Uast = buf.Bytes()
+	case Format_FormatJSON:
+		resp.UastJSON = []byte(r.Tree)
+	default:
+		return nil, fmt.Errorf("unsupported UAST format: %v", r.Format)
+	}
+	return resp, nil
+}
+
+// restorePositions converts integer fields of positional information in the tree decoded from JSON back to
+// unsigned values, as they are stored by uast.Position.
+func restorePositions(n nodes.Node) nodes.Node {
+	out, ok := nodes.Apply(n, func(n nodes.Node) (nodes.Node, bool) {
+		obj, ok := n.(nodes.Object)
+		if !ok || uast.TypeOf(obj) != uast.TypePosition {
+			return n, false
+		}
+		var changed nodes.Object
+		for _, k := range []string{uast.KeyPosOff, uast.KeyPosLine, uast.KeyPosCol} {
+			v, ok := obj[k].(nodes.Int)
+			if !ok || v < 0 {
+				continue
+			}
+			if changed == nil {
+				changed = obj.CloneObject()
+			}
+			changed[k] = nodes.Uint(v)
+		}
+		if changed == nil {
+			return n, false
+		}
+		return changed, true
+	})
+	if !ok {
+		return n
+	}
+	return out
+}
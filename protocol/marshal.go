@@ -0,0 +1,30 @@
+package protocol
+
+// MarshalAppend appends the binary encoding of the response to buf and returns the extended buffer.
+//
+// The output is the same as returned by Marshal, but the caller may reuse the buffer to avoid allocations.
+func (m *ParseResponse) MarshalAppend(buf []byte) ([]byte, error) {
+	size := m.ProtoSize()
+	buf, start := growBuffer(buf, size)
+	n, err := m.MarshalToSizedBuffer(buf[start:])
+	if err != nil {
+		return buf[:start], err
+	}
+	return buf[:start+n], nil
+}
+
+// growBuffer extends the buffer by n bytes, reallocating it only if there is not enough capacity.
+// It returns the new buffer and the offset of the added region.
+func growBuffer(buf []byte, n int) ([]byte, int) {
+	start := len(buf)
+	if cap(buf)-start < n {
+		sz := 2 * cap(buf)
+		if sz < start+n {
+			sz = start + n
+		}
+		nbuf := make([]byte, start, sz)
+		copy(nbuf, buf)
+		buf = nbuf
+	}
+	return buf[:start+n], start
+}
@@ -0,0 +1,193 @@
+package protocol
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultMaxInFlight is a default limit of concurrent requests for MuxClient.
+const DefaultMaxInFlight = 16
+
+var _ DriverClient = (*MuxClient)(nil)
+
+// MuxOptions controls the behavior of MuxClient.
+type MuxOptions struct {
+	// MaxInFlight is a maximal number of concurrent requests sent to the server. DefaultMaxInFlight is used if not set.
+	MaxInFlight int
+}
+
+// MuxStats is a snapshot of the MuxClient state.
+type MuxStats struct {
+	// InFlight is the number of requests being processed by the server.
+	InFlight int
+	// Queued is the number of requests waiting for the in-flight limit.
+	Queued int
+	// MaxInFlight is the configured in-flight limit.
+	MaxInFlight int
+}
+
+// MuxClient is a DriverClient that allows multiple workers to share a single connection, while limiting the number of
+// concurrent requests sent to the server.
+//
+// When the limit is reached, new requests wait until one of the in-flight requests completes. Waiting requests are
+// served in the order they arrived. The wait is interrupted if the request context is cancelled.
+// Streaming requests occupy the slot until Recv returns an error (including io.EOF) or the request context is
+// cancelled. Requests with progress also free the slot once the final response is received.
+type MuxClient struct {
+	c   DriverClient
+	max int
+
+	mu       sync.Mutex
+	inFlight int
+	queue    *list.List // of chan struct{}
+}
+
+// NewMuxClient creates a new multiplexing client on top of a given connection.
+func NewMuxClient(cc *grpc.ClientConn, opt MuxOptions) *MuxClient {
+	if opt.MaxInFlight <= 0 {
+		opt.MaxInFlight = DefaultMaxInFlight
+	}
+	return &MuxClient{
+		c:     NewDriverClient(cc),
+		max:   opt.MaxInFlight,
+		queue: list.New(),
+	}
+}
+
+// Stats returns the current queue depth and the number of in-flight requests.
+func (c *MuxClient) Stats() MuxStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return MuxStats{InFlight: c.inFlight, Queued: c.queue.Len(), MaxInFlight: c.max}
+}
+
+// acquire waits for a free request slot.
+func (c *MuxClient) acquire(ctx context.Context) error {
+	c.mu.Lock()
+	if c.inFlight < c.max && c.queue.Len() == 0 {
+		c.inFlight++
+		c.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	e := c.queue.PushBack(ready)
+	c.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+	c.mu.Lock()
+	select {
+	case <-ready:
+		// the slot was handed to us concurrently with the cancellation - pass it further
+		c.mu.Unlock()
+		c.release()
+	default:
+		c.queue.Remove(e)
+		c.mu.Unlock()
+	}
+	return ctx.Err()
+}
+
+// release frees the request slot, or hands it to the first request in the queue.
+func (c *MuxClient) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := c.queue.Front(); e != nil {
+		c.queue.Remove(e)
+		close(e.Value.(chan struct{}))
+		return
+	}
+	c.inFlight--
+}
+
+// Parse implements DriverClient.
+func (c *MuxClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.c.Parse(ctx, in, opts...)
+}
+
+// ParseStream implements DriverClient.
+func (c *MuxClient) ParseStream(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseStreamClient, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	cli, err := c.c.ParseStream(ctx, in, opts...)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	s := &muxParseStream{Driver_ParseStreamClient: cli}
+	s.done = c.releaseOnDone(ctx)
+	return s, nil
+}
+
+// ParseWithProgress implements DriverClient.
+func (c *MuxClient) ParseWithProgress(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (Driver_ParseWithProgressClient, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	cli, err := c.c.ParseWithProgress(ctx, in, opts...)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	s := &muxProgressStream{Driver_ParseWithProgressClient: cli}
+	s.done = c.releaseOnDone(ctx)
+	return s, nil
+}
+
+// releaseOnDone releases the request slot when the returned function is called, or when the context is cancelled.
+func (c *MuxClient) releaseOnDone(ctx context.Context) func() {
+	var once sync.Once
+	stop := make(chan struct{})
+	done := func() {
+		once.Do(func() {
+			close(stop)
+			c.release()
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			done()
+		case <-stop:
+		}
+	}()
+	return done
+}
+
+type muxParseStream struct {
+	Driver_ParseStreamClient
+	done func()
+}
+
+func (s *muxParseStream) Recv() (*ParseStreamResponse, error) {
+	resp, err := s.Driver_ParseStreamClient.Recv()
+	if err != nil {
+		s.done()
+	}
+	return resp, err
+}
+
+type muxProgressStream struct {
+	Driver_ParseWithProgressClient
+	done func()
+}
+
+func (s *muxProgressStream) Recv() (*ParseProgressResponse, error) {
+	resp, err := s.Driver_ParseWithProgressClient.Recv()
+	if err != nil || resp.GetResponse() != nil {
+		// the final response was received; the server is done with the request
+		s.done()
+	}
+	return resp, err
+}
@@ -0,0 +1,44 @@
+package protocol
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// PeekParseRequest reads scalar fields of a binary-encoded ParseRequest without decoding the whole message.
+// The content is skipped and only its length in bytes is returned, for either Content or RawContent.
+// The length is zero if the content is read from the ContentURI, which is returned as well, thus both must be
+// checked to limit the size of requests.
+//
+// Fields are delimited with the generated skipDriver, and all fields except the content are decoded with
+// ParseRequest.Unmarshal, thus malformed messages are rejected with the same errors as Unmarshal returns.
+func PeekParseRequest(data []byte) (filename, language string, contentLen int, contentURI string, enc Encoding, err error) {
+	var req ParseRequest
+	for i := 0; i < len(data); {
+		skip, err := skipDriver(data[i:])
+		if err == nil && (skip <= 0 || i+skip > len(data)) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			// report malformed fields the same way as Unmarshal does
+			if uerr := req.Unmarshal(data[i:]); uerr != nil {
+				err = uerr
+			}
+			return "", "", 0, "", 0, err
+		}
+		field := data[i : i+skip]
+		i += skip
+		tag, n := proto.DecodeVarint(field)
+		if num := tag >> 3; (num == 1 || num == 9) && tag&0x7 == proto.WireBytes {
+			// only the length of the content is decoded, the content itself is not copied
+			sz, _ := proto.DecodeVarint(field[n:])
+			contentLen = int(sz)
+			continue
+		}
+		if err = req.Unmarshal(field); err != nil {
+			return "", "", 0, "", 0, err
+		}
+	}
+	return req.Filename, req.Language, contentLen, req.ContentURI, req.Encoding, nil
+}
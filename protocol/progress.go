@@ -0,0 +1,87 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/bblfsh/sdk/v3/driver"
+)
+
+// ParseWithProgress implements DriverServer.
+func (s *driverServer) ParseWithProgress(req *ParseRequest, srv Driver_ParseWithProgressServer) error {
+	sp, ctx := opentracing.StartSpanFromContext(srv.Context(), "bblfsh.server.ParseWithProgress")
+	defer sp.Finish()
+
+	// the driver may still report progress after the handler returns, e.g. if it ignores the parse timeout,
+	// thus the stream is guarded and cannot be used once the handler is done
+	var (
+		mu   sync.Mutex
+		done bool
+		serr error
+	)
+	ctx = driver.WithProgress(ctx, func(phase string, percent float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done || serr != nil {
+			return
+		}
+		serr = srv.Send(&ParseProgressResponse{
+			Event: &ParseProgressResponse_Progress{Progress: &Progress{
+				Phase:   phase,
+				Percent: float32(percent),
+			}},
+		})
+	})
+	resp, err := s.parse(ctx, req)
+
+	mu.Lock()
+	defer mu.Unlock()
+	done = true
+	if err != nil {
+		return err
+	} else if serr != nil {
+		return serr
+	}
+	return srv.Send(&ParseProgressResponse{
+		Event: &ParseProgressResponse_Response{Response: resp},
+	})
+}
+
+// ParseWithProgress sends the parse request to the driver and calls fnc for each progress event, as it is received.
+// Progress callback is optional.
+//
+// It returns the final response. Similar to Parse, the UAST can be decoded from it with ParseResponse.Nodes.
+func ParseWithProgress(ctx context.Context, c DriverClient, req *ParseRequest, fnc func(p *Progress)) (*ParseResponse, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "bblfsh.client.ParseWithProgress")
+	defer sp.Finish()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.ParseWithProgress(ctx, req)
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return nil, fromGRPCError(err)
+		}
+		switch ev := msg.Event.(type) {
+		case *ParseProgressResponse_Progress:
+			if fnc != nil {
+				fnc(ev.Progress)
+			}
+		case *ParseProgressResponse_Response:
+			return ev.Response, nil
+		default:
+			return nil, fmt.Errorf("unexpected event type: %T", ev)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/driver/manifest"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+const (
+	// DefaultRetryAttempts is a default number of attempts made by WithRetry.
+	DefaultRetryAttempts = 3
+	// DefaultRetryDelay is a default delay before the first retry in WithRetry.
+	DefaultRetryDelay = 100 * time.Millisecond
+)
+
+// RetryPolicy controls how WithRetry retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is a maximal number of attempts, including the first one. DefaultRetryAttempts is used if not set.
+	MaxAttempts int
+	// BaseDelay is a delay before the first retry. It is doubled for each consecutive retry.
+	// DefaultRetryDelay is used if not set.
+	BaseDelay time.Duration
+	// MaxDelay limits the delay between retries. The delay is not limited if it is not set.
+	MaxDelay time.Duration
+	// Retryable reports if the request that failed with a given error should be retried.
+	// IsTransientError is used if not set.
+	Retryable func(err error) bool
+}
+
+// IsTransientError reports if the error is likely to go away if the request is retried.
+// This includes native driver failures (for example, when the native process is restarted)
+// and unavailable servers. Syntax errors are never transient.
+func IsTransientError(err error) bool {
+	if err == nil || driver.ErrSyntax.Is(err) {
+		return false
+	}
+	return driver.ErrDriverFailure.Is(err) || status.Code(err) == codes.Unavailable
+}
+
+var _ driver.Driver = (*retryDriver)(nil)
+
+// WithRetry wraps the driver client to retry Parse and Version requests that fail with transient errors.
+// Retries use an exponential backoff and stop when the number of attempts is exhausted, or when the next
+// retry would happen after the context deadline. In both cases the last error is returned.
+func WithRetry(d driver.Driver, policy RetryPolicy) driver.Driver {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryAttempts
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryDelay
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransientError
+	}
+	return &retryDriver{d: d, policy: policy}
+}
+
+type retryDriver struct {
+	d      driver.Driver
+	policy RetryPolicy
+}
+
+// retry calls fnc until it succeeds, fails with a permanent error or the retry policy gives up.
+func (d *retryDriver) retry(ctx context.Context, fnc func() error) error {
+	delay := d.policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fnc()
+		if err == nil || attempt >= d.policy.MaxAttempts || !d.policy.Retryable(err) {
+			return err
+		}
+		if dl, ok := ctx.Deadline(); ok && time.Until(dl) < delay {
+			return err
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		delay *= 2
+		if d.policy.MaxDelay > 0 && delay > d.policy.MaxDelay {
+			delay = d.policy.MaxDelay
+		}
+	}
+}
+
+// Parse implements driver.Driver.
+func (d *retryDriver) Parse(ctx context.Context, src string, opts *driver.ParseOptions) (nodes.Node, error) {
+	var ast nodes.Node
+	err := d.retry(ctx, func() error {
+		var err error
+		ast, err = d.d.Parse(ctx, src, opts)
+		return err
+	})
+	return ast, err
+}
+
+// Version implements driver.Driver.
+func (d *retryDriver) Version(ctx context.Context) (driver.Version, error) {
+	var vers driver.Version
+	err := d.retry(ctx, func() error {
+		var err error
+		vers, err = d.d.Version(ctx)
+		return err
+	})
+	return vers, err
+}
+
+// Languages implements driver.Driver.
+func (d *retryDriver) Languages(ctx context.Context) ([]manifest.Manifest, error) {
+	return d.d.Languages(ctx)
+}
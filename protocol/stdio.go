@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/driver/native/jsonlines"
+)
+
+// StdioResponse is a response frame written by ServeStdio.
+//
+// Error and Code are set if the request failed. In this case, the rest of the fields are not set.
+// Syntax errors are not reported this way; they are returned in the Errors field of the response instead.
+type StdioResponse struct {
+	*ParseResponse
+	// Error is a message of the error that occurred while processing the request.
+	Error string `json:"error,omitempty"`
+	// Code is a name of the gRPC status code of the error.
+	Code string `json:"code,omitempty"`
+}
+
+// ServeStdio serves parse requests for the driver over a pair of pipes, for example stdin and stdout.
+//
+// Each request is a ParseRequest encoded as a single line of JSON, and each response is a StdioResponse
+// encoded the same way. Requests are processed sequentially, using the same logic as the gRPC handler.
+// Malformed requests are reported as errors in the response and do not stop the loop.
+//
+// The function returns nil when the reader reaches EOF. The last request is processed even if it is not terminated
+// by a new line.
+func ServeStdio(ctx context.Context, r io.Reader, w io.Writer, d driver.Driver) error {
+	srv := &driverServer{d: d}
+	br := bufio.NewReaderSize(r, jsonlines.DefaultBufferSize)
+	enc := jsonlines.NewEncoder(w)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := br.ReadBytes('\n')
+		last := err == io.EOF
+		if last && len(bytes.TrimSpace(line)) == 0 {
+			return nil
+		} else if err != nil && !last {
+			return err
+		}
+		var req ParseRequest
+		err = json.Unmarshal(line, &req)
+		var resp StdioResponse
+		if err != nil {
+			resp.Error = "invalid request: " + err.Error()
+			resp.Code = codes.InvalidArgument.String()
+		} else if resp.ParseResponse, err = srv.Parse(ctx, &req); err != nil {
+			st, _ := status.FromError(err)
+			resp.Error = st.Message()
+			resp.Code = st.Code().String()
+		}
+		if err = enc.Encode(&resp); err != nil {
+			return err
+		} else if last {
+			return nil
+		}
+	}
+}
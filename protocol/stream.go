@@ -0,0 +1,240 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/bblfsh/sdk/v3/driver"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// streamBatchSize is the maximal number of node events sent in a single ParseStream message.
+const streamBatchSize = 1024
+
+// WalkEvents walks the tree in pre-order and calls fnc for each node event.
+// The sequence of events is sufficient to rebuild the tree, see TreeBuilder.
+func WalkEvents(n nodes.External, fnc func(ev *NodeEvent) error) error {
+	return walkEvents("", n, fnc)
+}
+
+func walkEvents(key string, n nodes.External, fnc func(ev *NodeEvent) error) error {
+	switch kind := nodes.KindOf(n); kind {
+	case nodes.KindObject:
+		obj, ok := n.(nodes.ExternalObject)
+		if !ok {
+			return fmt.Errorf("unexpected object type: %T", n)
+		}
+		if err := fnc(&NodeEvent{Type: NodeEventType_ObjectStart, Key: key}); err != nil {
+			return err
+		}
+		for _, k := range nodes.SortedKeys(obj) {
+			v, _ := obj.ValueAt(k)
+			if err := walkEvents(k, v, fnc); err != nil {
+				return err
+			}
+		}
+		return fnc(&NodeEvent{Type: NodeEventType_ObjectEnd})
+	case nodes.KindArray:
+		arr, ok := n.(nodes.ExternalArray)
+		if !ok {
+			return fmt.Errorf("unexpected array type: %T", n)
+		}
+		if err := fnc(&NodeEvent{Type: NodeEventType_ArrayStart, Key: key}); err != nil {
+			return err
+		}
+		sz := arr.Size()
+		for i := 0; i < sz; i++ {
+			if err := walkEvents("", arr.ValueAt(i), fnc); err != nil {
+				return err
+			}
+		}
+		return fnc(&NodeEvent{Type: NodeEventType_ArrayEnd})
+	}
+	ev := &NodeEvent{Type: NodeEventType_Value, Key: key}
+	if n == nil {
+		return fnc(ev)
+	}
+	switch v := n.Value().(type) {
+	case nil:
+	case nodes.String:
+		ev.Value = &NodeEvent_StringValue{StringValue: string(v)}
+	case nodes.Int:
+		ev.Value = &NodeEvent_IntValue{IntValue: int64(v)}
+	case nodes.Uint:
+		ev.Value = &NodeEvent_UintValue{UintValue: uint64(v)}
+	case nodes.Float:
+		ev.Value = &NodeEvent_FloatValue{FloatValue: float64(v)}
+	case nodes.Bool:
+		ev.Value = &NodeEvent_BoolValue{BoolValue: bool(v)}
+	default:
+		return fmt.Errorf("unexpected value type: %T", v)
+	}
+	return fnc(ev)
+}
+
+// TreeBuilder reconstructs the tree from a sequence of node events, as emitted by WalkEvents.
+type TreeBuilder struct {
+	stack []builderFrame
+	root  nodes.Node
+	done  bool
+}
+
+type builderFrame struct {
+	key  string // key of this node in the parent object
+	node nodes.Node
+}
+
+// Push adds the next event to the tree.
+func (b *TreeBuilder) Push(ev *NodeEvent) error {
+	if b.done {
+		return errors.New("unexpected event after the end of the tree")
+	}
+	switch ev.Type {
+	case NodeEventType_ObjectStart:
+		b.stack = append(b.stack, builderFrame{key: ev.Key, node: nodes.Object{}})
+		return nil
+	case NodeEventType_ArrayStart:
+		b.stack = append(b.stack, builderFrame{key: ev.Key, node: nodes.Array{}})
+		return nil
+	case NodeEventType_ObjectEnd, NodeEventType_ArrayEnd:
+		if len(b.stack) == 0 {
+			return fmt.Errorf("unexpected %v event", ev.Type)
+		}
+		top := b.stack[len(b.stack)-1]
+		_, isObj := top.node.(nodes.Object)
+		if isObj != (ev.Type == NodeEventType_ObjectEnd) {
+			return fmt.Errorf("unexpected %v event", ev.Type)
+		}
+		b.stack = b.stack[:len(b.stack)-1]
+		return b.add(top.key, top.node)
+	case NodeEventType_Value:
+		var v nodes.Node
+		switch val := ev.Value.(type) {
+		case nil:
+		case *NodeEvent_StringValue:
+			v = nodes.String(val.StringValue)
+		case *NodeEvent_IntValue:
+			v = nodes.Int(val.IntValue)
+		case *NodeEvent_UintValue:
+			v = nodes.Uint(val.UintValue)
+		case *NodeEvent_FloatValue:
+			v = nodes.Float(val.FloatValue)
+		case *NodeEvent_BoolValue:
+			v = nodes.Bool(val.BoolValue)
+		default:
+			return fmt.Errorf("unexpected value type: %T", val)
+		}
+		return b.add(ev.Key, v)
+	}
+	return fmt.Errorf("unknown event type: %v", ev.Type)
+}
+
+// add appends a complete node to the parent node.
+func (b *TreeBuilder) add(key string, n nodes.Node) error {
+	if len(b.stack) == 0 {
+		b.root, b.done = n, true
+		return nil
+	}
+	switch parent := b.stack[len(b.stack)-1].node.(type) {
+	case nodes.Object:
+		if _, ok := parent[key]; ok {
+			return fmt.Errorf("duplicate field: %q", key)
+		}
+		parent[key] = n
+	case nodes.Array:
+		b.stack[len(b.stack)-1].node = append(parent, n)
+	}
+	return nil
+}
+
+// Node returns the tree. It returns an error if the event sequence is incomplete.
+func (b *TreeBuilder) Node() (nodes.Node, error) {
+	if !b.done {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b.root, nil
+}
+
+// ParseStream implements DriverServer.
+func (s *driverServer) ParseStream(req *ParseRequest, srv Driver_ParseStreamServer) error {
+	sp, ctx := opentracing.StartSpanFromContext(srv.Context(), "bblfsh.server.ParseStream")
+	defer sp.Finish()
+
+	var resp ParseResponse
+	n, err := s.parseTree(ctx, req, &resp)
+	if err != nil {
+		return err
+	}
+
+	msg := &ParseStreamResponse{}
+	err = WalkEvents(n, func(ev *NodeEvent) error {
+		msg.Events = append(msg.Events, ev)
+		if len(msg.Events) < streamBatchSize {
+			return nil
+		}
+		if err := srv.Send(msg); err != nil {
+			return err
+		}
+		msg = &ParseStreamResponse{}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	msg.Language = resp.Language
+	msg.Errors = resp.Errors
+	msg.Warnings = resp.Warnings
+	return srv.Send(msg)
+}
+
+// ParseStream sends the parse request to the driver and calls fnc for each node event of the resulting UAST,
+// as it is received. TreeBuilder can be used to reconstruct the tree from events.
+//
+// It returns the language of the file. Similar to Parse, it returns driver.ErrSyntax if the file was parsed
+// partially, after all events were processed.
+func ParseStream(ctx context.Context, c DriverClient, req *ParseRequest, fnc func(ev *NodeEvent) error) (string, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "bblfsh.client.ParseStream")
+	defer sp.Finish()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.ParseStream(ctx, req)
+	if err != nil {
+		return "", fromGRPCError(err)
+	}
+	var (
+		lang string
+		errs []*ParseError
+	)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", fromGRPCError(err)
+		}
+		for _, ev := range msg.Events {
+			if err := fnc(ev); err != nil {
+				return "", err
+			}
+		}
+		if msg.Language != "" {
+			lang = msg.Language
+		}
+		errs = append(errs, msg.Errors...)
+		driver.AddWarnings(ctx, msg.Warnings...)
+	}
+	if len(errs) != 0 {
+		list := make([]error, 0, len(errs))
+		for _, e := range errs {
+			list = append(list, e.toError())
+		}
+		return lang, driver.ErrSyntax.Wrap(driver.JoinErrors(list))
+	}
+	return lang, nil
+}
@@ -0,0 +1,85 @@
+package uast
+
+import (
+	"sort"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// Canonicalize converts the tree to a canonical form. Two semantically identical trees will have the same
+// canonical form, thus their binary or JSON encoding will be the same as well. It is useful for building cache keys,
+// see nodes.HashOf.
+//
+// The following normalizations are applied:
+//   - roles are sorted by their numeric value and deduplicated;
+//   - non-negative integers are converted to nodes.Uint, the same way as positions store them.
+//
+// Object keys have no particular order in nodes.Object, and all encoders already write them in sorted order.
+// Positional information is preserved as-is.
+//
+// The function never modifies the source tree. Canonicalizing the same tree twice is a no-op.
+func Canonicalize(n nodes.Node) nodes.Node {
+	nn, ok := nodes.Apply(n, func(n nodes.Node) (nodes.Node, bool) {
+		switch n := n.(type) {
+		case nodes.Int:
+			if n >= 0 {
+				return nodes.Uint(n), true
+			}
+		case nodes.Object:
+			arr, ok := n[KeyRoles].(nodes.Array)
+			if !ok {
+				return n, false
+			}
+			roles, ok := canonicalRoles(arr)
+			if !ok {
+				return n, false
+			}
+			n = n.CloneObject()
+			n[KeyRoles] = roles
+			return n, true
+		}
+		return n, false
+	})
+	if !ok {
+		return n
+	}
+	return nn
+}
+
+// canonicalRoles sorts and deduplicates a list of roles. It returns false if the list is already canonical,
+// or if it contains non-string elements.
+func canonicalRoles(arr nodes.Array) (nodes.Array, bool) {
+	type namedRole struct {
+		name string
+		role role.Role
+	}
+	roles := make([]namedRole, 0, len(arr))
+	seen := make(map[string]struct{}, len(arr))
+	for _, v := range arr {
+		s, ok := v.(nodes.String)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := seen[string(s)]; ok {
+			continue
+		}
+		seen[string(s)] = struct{}{}
+		roles = append(roles, namedRole{name: string(s), role: role.FromString(string(s))})
+	}
+	less := func(i, j int) bool {
+		if roles[i].role != roles[j].role {
+			return roles[i].role < roles[j].role
+		}
+		return roles[i].name < roles[j].name
+	}
+	if len(roles) == len(arr) && sort.SliceIsSorted(roles, less) {
+		return nil, false
+	}
+	sort.Slice(roles, less)
+	out := make(nodes.Array, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, nodes.String(r.name))
+	}
+	return out, true
+}
@@ -0,0 +1,62 @@
+package uast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+func TestCanonicalize(t *testing.T) {
+	pos := Positions{
+		KeyStart: {Offset: 1, Line: 1, Col: 2},
+	}.ToObject()
+	inp := nodes.Array{
+		nodes.Object{
+			KeyType:  nodes.String("A"),
+			KeyPos:   pos,
+			KeyRoles: RoleList(role.Name, role.Identifier, role.Name),
+			"num":    nodes.Int(3),
+			"neg":    nodes.Int(-1),
+			"sub": nodes.Object{
+				KeyRoles: RoleList(role.Identifier),
+			},
+		},
+	}
+	orig := inp.Clone()
+
+	exp := nodes.Array{
+		nodes.Object{
+			KeyType:  nodes.String("A"),
+			KeyPos:   pos,
+			KeyRoles: RoleList(role.Identifier, role.Name),
+			"num":    nodes.Uint(3),
+			"neg":    nodes.Int(-1),
+			"sub": nodes.Object{
+				KeyRoles: RoleList(role.Identifier),
+			},
+		},
+	}
+	out := Canonicalize(inp)
+	require.Equal(t, exp, out)
+	require.Equal(t, orig, inp, "source tree modified")
+
+	// idempotent
+	require.Equal(t, exp, Canonicalize(out))
+
+	other := nodes.Array{
+		nodes.Object{
+			KeyType:  nodes.String("A"),
+			KeyPos:   pos,
+			KeyRoles: RoleList(role.Name, role.Identifier),
+			"num":    nodes.Int(3),
+			"neg":    nodes.Int(-1),
+			"sub": nodes.Object{
+				KeyRoles: RoleList(role.Identifier),
+			},
+		},
+	}
+	require.Equal(t, nodes.HashOf(Canonicalize(other)), nodes.HashOf(out))
+}
@@ -0,0 +1,57 @@
+package uast
+
+import (
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// Index assigns a unique ID to each object node in the tree. IDs are assigned sequentially starting from 1, in the
+// pre-order traversal with object keys visited in sorted order (see nodes.SortedKeys), even for external objects
+// that do not return sorted keys. Thus, IDs are stable for the same tree.
+//
+// Contrary to nodes.HashOf, IDs reflect the node position in the tree, thus two identical subtrees will get
+// different IDs. Values and arrays are not assigned any IDs.
+//
+// It returns a map from an ID to a node, and a function that returns an ID of a given node. The index holds
+// references to original nodes, thus the tree must not be modified while the index is in use.
+func Index(root nodes.External) (map[uint64]nodes.External, func(nodes.External) (uint64, bool)) {
+	var (
+		last  uint64
+		byID  = make(map[uint64]nodes.External)
+		byKey = make(map[nodes.Comparable]uint64)
+		ext   []extID // external nodes that cannot be used as map keys
+	)
+	nodes.WalkPreOrderExt(root, func(n nodes.External) bool {
+		if nodes.KindOf(n) != nodes.KindObject {
+			return true
+		}
+		last++
+		byID[last] = n
+		if nd, ok := n.(nodes.Node); ok {
+			byKey[nodes.UniqueKey(nd)] = last
+		} else {
+			ext = append(ext, extID{id: last, n: n})
+		}
+		return true
+	})
+	idOf := func(n nodes.External) (uint64, bool) {
+		if nodes.KindOf(n) != nodes.KindObject {
+			return 0, false
+		}
+		if nd, ok := n.(nodes.Node); ok {
+			id, ok := byKey[nodes.UniqueKey(nd)]
+			return id, ok
+		}
+		for _, e := range ext {
+			if nodes.Same(e.n, n) {
+				return e.id, true
+			}
+		}
+		return 0, false
+	}
+	return byID, idOf
+}
+
+type extID struct {
+	id uint64
+	n  nodes.External
+}
@@ -0,0 +1,90 @@
+package uast
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	a := nodes.Object{KeyType: nodes.String("Ident"), "Name": nodes.String("a")}
+	// the same content as a, but a different node
+	a2 := nodes.Object{KeyType: nodes.String("Ident"), "Name": nodes.String("a")}
+	b := nodes.Object{KeyType: nodes.String("Ident"), "Name": nodes.String("b")}
+	root := nodes.Object{
+		KeyType: nodes.String("File"),
+		"x":     a,
+		"y":     nodes.Array{b, a2},
+	}
+
+	byID, idOf := Index(root)
+	require.Len(t, byID, 4)
+
+	exp := []nodes.Object{root, a, b, a2}
+	for i, n := range exp {
+		id := uint64(i + 1)
+		require.True(t, byID[id].SameAs(n), "id: %d", id)
+		got, ok := idOf(n)
+		require.True(t, ok)
+		require.Equal(t, id, got)
+	}
+
+	_, ok := idOf(nodes.Object{})
+	require.False(t, ok)
+	_, ok = idOf(nodes.String("a"))
+	require.False(t, ok)
+
+	// IDs must be stable for the same tree
+	byID2, _ := Index(root.CloneObject())
+	require.Equal(t, len(byID), len(byID2))
+	for id, n := range byID {
+		require.True(t, nodes.Equal(n, byID2[id]), "id: %d", id)
+	}
+}
+
+// reversedObject is an external object that returns keys in a reverse order, violating the contract.
+type reversedObject struct {
+	m map[string]nodes.External
+}
+
+func (o *reversedObject) Kind() nodes.Kind   { return nodes.KindObject }
+func (o *reversedObject) Value() nodes.Value { return nil }
+func (o *reversedObject) Size() int          { return len(o.m) }
+
+func (o *reversedObject) SameAs(n nodes.External) bool {
+	o2, ok := n.(*reversedObject)
+	return ok && o == o2
+}
+
+func (o *reversedObject) Keys() []string {
+	keys := make([]string, 0, len(o.m))
+	for k := range o.m {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	return keys
+}
+
+func (o *reversedObject) ValueAt(k string) (nodes.External, bool) {
+	v, ok := o.m[k]
+	return v, ok
+}
+
+func TestIndexExternal(t *testing.T) {
+	a := &reversedObject{m: map[string]nodes.External{"Name": nodes.String("a")}}
+	b := &reversedObject{m: map[string]nodes.External{"Name": nodes.String("b")}}
+	root := &reversedObject{m: map[string]nodes.External{"x": a, "y": b}}
+
+	byID, idOf := Index(root)
+	require.Len(t, byID, 3)
+	// keys are visited in sorted order, regardless of the order returned by the object
+	for i, n := range []nodes.External{root, a, b} {
+		id := uint64(i + 1)
+		require.True(t, byID[id].SameAs(n), "id: %d", id)
+		got, ok := idOf(n)
+		require.True(t, ok)
+		require.Equal(t, id, got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"sort"
 
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
 )
 
 // NewPositionalIterator creates a new iterator that enumerates all object nodes, sorting them by positions in the source file.
@@ -97,3 +98,414 @@ func (it *positionIter) Node() nodes.External {
 	}
 	return it.nodes[0]
 }
+
+// PositionedNode is a node with a valid start position, as returned by CollectPositions.
+type PositionedNode struct {
+	Node  nodes.External
+	Type  string
+	Roles role.Roles
+	Start Position
+	// End is a zero position if the node has no end position.
+	End Position
+}
+
+// CollectPositions returns all object nodes that have a valid start position, in a single traversal
+// of the tree. Nodes are sorted by start offset, then by end offset and then by type, which makes
+// the result suitable for building source maps.
+func CollectPositions(root nodes.External) []PositionedNode {
+	var out []PositionedNode
+	posType := TypeOf(Positions{})
+	nodes.WalkPreOrderExt(root, func(n nodes.External) bool {
+		if n == nil || n.Kind() != nodes.KindObject {
+			return true
+		}
+		obj, ok := n.(nodes.ExternalObject)
+		if !ok {
+			return true
+		}
+		typ := TypeOf(obj)
+		if typ == posType {
+			// skip position nodes
+			return false
+		}
+		m, _ := obj.ValueAt(KeyPos)
+		if m == nil || m.Kind() != nodes.KindObject {
+			return true
+		}
+		var ps Positions
+		if err := NodeAs(m, &ps); err != nil {
+			return true
+		}
+		start := ps.Start()
+		if start == nil || !start.Valid() {
+			return true
+		}
+		pn := PositionedNode{
+			Node:  n,
+			Type:  typ,
+			Roles: AnnotatedRoles(obj),
+			Start: *start,
+		}
+		if end := ps.End(); end != nil {
+			pn.End = *end
+		}
+		out = append(out, pn)
+		return true
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Start.Offset != b.Start.Offset {
+			return a.Start.Offset < b.Start.Offset
+		}
+		if a.End.Offset != b.End.Offset {
+			return a.End.Offset < b.End.Offset
+		}
+		return a.Type < b.Type
+	})
+	return out
+}
+
+// Token is a leaf token of the source file, as returned by TokenStream.
+type Token struct {
+	Text  string
+	Start Position
+	// End is a zero position if the node has no end position.
+	End Position
+}
+
+// TokenStream returns tokens of all object nodes that have both a non-empty token and a valid start position.
+// Tokens are sorted by start offset and then by end offset, reconstructing the token stream of the source file.
+// Tokens with the same range are ordered the same way as in CollectPositions, thus the order is deterministic.
+func TokenStream(root nodes.External) []Token {
+	var out []Token
+	for _, pn := range CollectPositions(root) {
+		obj, ok := pn.Node.(nodes.ExternalObject)
+		if !ok {
+			continue
+		}
+		v, _ := obj.ValueAt(KeyToken)
+		if v == nil || v.Kind() == nodes.KindObject || v.Kind() == nodes.KindArray {
+			continue
+		}
+		tok := TokenOf(v.Value())
+		if tok == "" {
+			continue
+		}
+		out = append(out, Token{Text: tok, Start: pn.Start, End: pn.End})
+	}
+	return out
+}
+
+// TopLevelDeclarations returns the immediate children of the root with the Declaration role, e.g. functions
+// and types declared in the file. Arrays stored in the fields of the root are unfolded, but other objects are
+// not searched, thus declarations nested in objects without the role are not returned. Use TopLevelNodes to
+// search the whole tree instead.
+//
+// Returned nodes are sorted in the same way as in TopLevelNodes.
+func TopLevelDeclarations(root nodes.External) []nodes.External {
+	var out []nodes.External
+	it := nodes.NewIterator(root, nodes.ChildrenOrder)
+	for it.Next() {
+		if n := it.Node(); isDeclaration(n) {
+			out = append(out, n)
+		}
+	}
+	sortByStart(out)
+	return out
+}
+
+// isDeclaration checks if the node is an object with the Declaration role.
+func isDeclaration(n nodes.External) bool {
+	for _, r := range AnnotatedRoles(n) {
+		if r == role.Declaration {
+			return true
+		}
+	}
+	return false
+}
+
+// TopLevelNodes returns the top-most objects in the tree that match the filter. The root itself is never
+// returned, and descendants of matched objects are not searched. It allows drivers to split the file into
+// independent subtrees, e.g. by selecting objects of specific types.
+//
+// Returned nodes are the subtrees of the original tree, thus their positions are preserved. If all of them
+// have positional information, they are sorted by the start offset, otherwise they are returned in the
+// pre-order of the tree. The function returns nil if there are no matching objects.
+func TopLevelNodes(root nodes.External, filter func(n nodes.External) bool) []nodes.External {
+	var (
+		out   []nodes.External
+		first = true
+	)
+	nodes.WalkPreOrderExt(root, func(n nodes.External) bool {
+		if first {
+			first = false
+			return true
+		} else if n == nil {
+			return false
+		}
+		if n.Kind() != nodes.KindObject || TypeOf(n) == TypePositions {
+			return n.Kind() == nodes.KindArray
+		}
+		if filter(n) {
+			out = append(out, n)
+			return false
+		}
+		return true
+	})
+	sortByStart(out)
+	return out
+}
+
+// sortByStart sorts nodes by the start offset, if all of them have positional information.
+func sortByStart(out []nodes.External) {
+	if len(out) < 2 {
+		return
+	}
+	type withOffset struct {
+		node nodes.External
+		off  uint32
+	}
+	list := make([]withOffset, len(out))
+	for i, n := range out {
+		start := startOf(n)
+		if start == nil || !start.Valid() {
+			return
+		}
+		list[i] = withOffset{node: n, off: start.Offset}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].off < list[j].off
+	})
+	for i, e := range list {
+		out[i] = e.node
+	}
+}
+
+// startOf returns the start position of the object, or nil if it has no positional information.
+func startOf(n nodes.External) *Position {
+	obj, ok := n.(nodes.ExternalObject)
+	if !ok {
+		return nil
+	}
+	m, _ := obj.ValueAt(KeyPos)
+	if m == nil || m.Kind() != nodes.KindObject {
+		return nil
+	}
+	var ps Positions
+	if err := NodeAs(m, &ps); err != nil {
+		return nil
+	}
+	return ps.Start()
+}
+
+// Types returns all object types found in the tree with the number of occurrences of each type.
+// Objects without a type are counted under an empty string.
+func Types(root nodes.External) map[string]int {
+	out := make(map[string]int)
+	nodes.WalkPreOrderExt(root, func(n nodes.External) bool {
+		if n != nil && n.Kind() == nodes.KindObject {
+			out[TypeOf(n)]++
+		}
+		return true
+	})
+	return out
+}
+
+// NodeAtOffset returns the deepest object node whose position range contains a given byte offset.
+// The start offset of the range is inclusive and the end offset is exclusive. A zero-width range
+// contains only its start offset.
+//
+// Nodes without positions, or without an end position, are never returned, but their children are
+// still searched. Children of nodes that do not contain the offset are not searched. If multiple
+// children contain the offset, the one with the narrowest range is used, and the first one in the
+// order of sorted field names and array indexes wins if their ranges are equal.
+func NodeAtOffset(root nodes.External, offset int) (nodes.External, bool) {
+	if offset < 0 {
+		return nil, false
+	}
+	n, _, ok := nodeAtOffset(root, uint32(offset))
+	return n, ok
+}
+
+// nodeAtOffset returns the deepest node containing the offset together with the width of its range.
+func nodeAtOffset(n nodes.External, off uint32) (nodes.External, uint32, bool) {
+	var (
+		best  nodes.External
+		width uint32
+		found bool
+	)
+	visit := func(c nodes.External) {
+		if cn, w, ok := nodeAtOffset(c, off); ok && (!found || w < width) {
+			best, width, found = cn, w, true
+		}
+	}
+	switch nodes.KindOf(n) {
+	case nodes.KindArray:
+		arr, ok := n.(nodes.ExternalArray)
+		if !ok {
+			return nil, 0, false
+		}
+		for i := 0; i < arr.Size(); i++ {
+			visit(arr.ValueAt(i))
+		}
+		return best, width, found
+	case nodes.KindObject:
+	default:
+		return nil, 0, false
+	}
+	obj, ok := n.(nodes.ExternalObject)
+	if !ok || TypeOf(obj) == TypePositions {
+		return nil, 0, false
+	}
+	var (
+		start, end *Position
+		ps         Positions
+	)
+	if m, _ := obj.ValueAt(KeyPos); m != nil && m.Kind() == nodes.KindObject && NodeAs(m, &ps) == nil {
+		start, end = ps.Start(), ps.End()
+	}
+	hasRange := start != nil && end != nil && start.HasOffset() && end.HasOffset() && start.Offset <= end.Offset
+	if hasRange {
+		if start.Offset == end.Offset {
+			if off != start.Offset {
+				return nil, 0, false
+			}
+		} else if off < start.Offset || off >= end.Offset {
+			return nil, 0, false
+		}
+	}
+	for _, k := range nodes.SortedKeys(obj) {
+		if k == KeyPos {
+			continue
+		}
+		v, _ := obj.ValueAt(k)
+		visit(v)
+	}
+	if found || !hasRange {
+		return best, width, found
+	}
+	return n, end.Offset - start.Offset, true
+}
+
+// TrimToRange prunes the tree to the objects that intersect a given byte range. The range is half-open: start is
+// inclusive and end is exclusive. A zero-width range intersects objects that contain its start offset, and a
+// zero-width object intersects the range if its offset is inside the range.
+//
+// Objects with both start and end positions are kept if their range intersects the given one, otherwise they are
+// removed together with their children. Children of kept objects are pruned the same way. Objects and arrays without
+// positions are kept if any of their descendants is kept, and removed if all their descendants with positions are
+// removed. Nodes without descendants with positions are kept if their parent is kept. Fields and array elements
+// that contained removed nodes are removed as well.
+//
+// The function returns nil if the root is removed. The source tree is never modified.
+func TrimToRange(root nodes.Node, start, end int) nodes.Node {
+	if start < 0 {
+		start = 0
+	}
+	if end < start {
+		end = start
+	}
+	out, st := trimToRange(root, uint32(start), uint32(end))
+	if st == trimDrop {
+		return nil
+	}
+	return out
+}
+
+type trimState int
+
+const (
+	// trimNoPos is set for nodes that have no descendants with positions
+	trimNoPos = trimState(iota)
+	// trimKeep is set for nodes that intersect the range or have such descendants
+	trimKeep
+	// trimDrop is set for nodes that must be removed
+	trimDrop
+)
+
+// intersects checks if a range [s, e) intersects the range [start, end).
+func intersects(s, e, start, end uint32) bool {
+	if s == e {
+		return s >= start && (s < end || s == start)
+	} else if start == end {
+		return start >= s && start < e
+	}
+	return s < end && start < e
+}
+
+func trimToRange(n nodes.Node, start, end uint32) (nodes.Node, trimState) {
+	switch n := n.(type) {
+	case nodes.Object:
+		st := trimNoPos
+		if ps := PositionsOf(n); ps != nil {
+			s, e := ps.Start(), ps.End()
+			if s != nil && e != nil && s.HasOffset() && e.HasOffset() && s.Offset <= e.Offset {
+				if !intersects(s.Offset, e.Offset, start, end) {
+					return nil, trimDrop
+				}
+				st = trimKeep
+			}
+		}
+		var (
+			out     nodes.Object
+			dropped bool
+		)
+		for _, k := range n.Keys() {
+			if k == KeyPos {
+				continue
+			}
+			v, cst := trimToRange(n[k], start, end)
+			switch cst {
+			case trimKeep:
+				st = trimKeep
+			case trimDrop:
+				dropped = true
+			}
+			if cst == trimDrop || !nodes.Same(v, n[k]) {
+				if out == nil {
+					out = n.CloneObject()
+				}
+				if cst == trimDrop {
+					delete(out, k)
+				} else {
+					out[k] = v
+				}
+			}
+		}
+		if st == trimNoPos && dropped {
+			return nil, trimDrop
+		} else if out == nil {
+			return n, st
+		}
+		return out, st
+	case nodes.Array:
+		var (
+			out     nodes.Array
+			st      = trimNoPos
+			dropped bool
+		)
+		for i, v := range n {
+			nv, cst := trimToRange(v, start, end)
+			switch cst {
+			case trimKeep:
+				st = trimKeep
+			case trimDrop:
+				dropped = true
+			}
+			if out == nil && (cst == trimDrop || !nodes.Same(nv, v)) {
+				out = make(nodes.Array, 0, len(n))
+				out = append(out, n[:i]...)
+			}
+			if out != nil && cst != trimDrop {
+				out = append(out, nv)
+			}
+		}
+		if st == trimNoPos && dropped {
+			return nil, trimDrop
+		} else if out == nil {
+			return n, st
+		}
+		return out, st
+	}
+	return n, trimNoPos
+}
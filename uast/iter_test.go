@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
 )
 
 func toNode(o interface{}) nodes.Node {
@@ -52,3 +53,270 @@ func TestPosIterator(t *testing.T) {
 	it := NewPositionalIterator(root)
 	expect(t, it, b, a, c)
 }
+
+func TestCollectPositions(t *testing.T) {
+	span := func(start, end uint32) GenNode {
+		return GenNode{
+			Positions: Positions{
+				KeyStart: Position{Offset: start, Line: 1, Col: start + 1},
+				KeyEnd:   Position{Offset: end, Line: 1, Col: end + 1},
+			},
+		}
+	}
+	id := toNode(Identifier{GenNode: span(4, 5), Name: "b"})
+	str := toNode(String{GenNode: span(0, 3), Value: "a"})
+	noPos := toNode(Identifier{Name: "c"})
+	alias := toNode(Alias{GenNode: span(0, 5), Name: Identifier{Name: "d"}, Node: nodes.Array{id, noPos}})
+	str.(nodes.Object)[KeyRoles] = nodes.Array{nodes.String(role.Literal.String())}
+
+	root := nodes.Array{alias, str}
+	got := CollectPositions(root)
+	require.Len(t, got, 3)
+
+	require.Equal(t, str, got[0].Node)
+	require.Equal(t, TypeOf(String{}), got[0].Type)
+	require.Equal(t, role.Roles{role.Literal}, got[0].Roles)
+	require.Equal(t, uint32(3), got[0].End.Offset)
+
+	require.Equal(t, alias, got[1].Node)
+	require.Equal(t, TypeOf(Alias{}), got[1].Type)
+	require.Equal(t, uint32(0), got[1].Start.Offset)
+	require.Equal(t, uint32(5), got[1].End.Offset)
+
+	require.Equal(t, id, got[2].Node)
+	require.Equal(t, uint32(4), got[2].Start.Offset)
+}
+
+func TestTokenStream(t *testing.T) {
+	// func f(a) { return a }
+	span := func(start, end uint32) nodes.Object {
+		return Positions{
+			KeyStart: Position{Offset: start, Line: 1, Col: start + 1},
+			KeyEnd:   Position{Offset: end, Line: 1, Col: end + 1},
+		}.ToObject()
+	}
+	tok := func(typ, text string, start uint32) nodes.Object {
+		return nodes.Object{
+			KeyType:  nodes.String(typ),
+			KeyToken: nodes.String(text),
+			KeyPos:   span(start, start+uint32(len(text))),
+		}
+	}
+	root := nodes.Object{
+		KeyType:  nodes.String("FuncDecl"),
+		KeyToken: nodes.String("func"),
+		KeyPos:   span(0, 22),
+		"Name":   tok("Ident", "f", 5),
+		"Params": nodes.Array{tok("Ident", "a", 7)},
+		"Body": nodes.Object{
+			KeyType: nodes.String("Block"),
+			KeyPos:  span(10, 22),
+			"Stmts": nodes.Array{
+				nodes.Object{
+					KeyType:  nodes.String("Return"),
+					KeyToken: nodes.String("return"),
+					KeyPos:   span(12, 20),
+					"Value":  tok("Ident", "a", 19),
+				},
+				// no position
+				nodes.Object{KeyType: nodes.String("Ident"), KeyToken: nodes.String("b")},
+			},
+		},
+		// same start as the function, but a smaller range
+		"Keyword": tok("Keyword", "func", 0),
+	}
+	got := TokenStream(root)
+	var texts []string
+	for _, tk := range got {
+		texts = append(texts, tk.Text)
+	}
+	require.Equal(t, []string{"func", "func", "f", "a", "return", "a"}, texts)
+	require.Equal(t, uint32(4), got[0].End.Offset)
+	require.Equal(t, uint32(22), got[1].End.Offset)
+	require.Equal(t, Token{
+		Text:  "a",
+		Start: Position{Offset: 19, Line: 1, Col: 20},
+		End:   Position{Offset: 20, Line: 1, Col: 21},
+	}, got[5])
+	require.Equal(t, got, TokenStream(root))
+}
+
+func TestTopLevelDeclarations(t *testing.T) {
+	fnc := func(name string, start, end uint32, body ...nodes.Node) nodes.Object {
+		return nodes.Object{
+			KeyType:  nodes.String("FuncDecl"),
+			KeyRoles: RoleList(role.Function, role.Declaration),
+			KeyPos: Positions{
+				KeyStart: {Offset: start, Line: 1, Col: start + 1},
+				KeyEnd:   {Offset: end, Line: 1, Col: end + 1},
+			}.ToObject(),
+			"Name": nodes.String(name),
+			"Body": nodes.Array(body),
+		}
+	}
+	inner := fnc("inner", 4, 8)
+	a := fnc("a", 0, 10, inner)
+	b := fnc("b", 12, 20)
+	c := fnc("c", 22, 30)
+	root := nodes.Object{
+		KeyType:  nodes.String("File"),
+		KeyRoles: RoleList(role.File),
+		"Decls":  nodes.Array{b, a},
+		"Tail":   nodes.Object{KeyType: nodes.String("Group"), "List": nodes.Array{c}},
+	}
+
+	got := TopLevelDeclarations(root)
+	require.Equal(t, []nodes.External{a, b}, got)
+	require.Equal(t, PositionsOf(a), PositionsOf(got[0].(nodes.Object)))
+
+	// declarations nested in other objects are only found by a deep search
+	require.Equal(t, []nodes.External{a, b, c}, TopLevelNodes(root, isDeclaration))
+
+	// root is never returned
+	require.Equal(t, []nodes.External{inner}, TopLevelDeclarations(a))
+
+	// custom filter
+	byType := func(n nodes.External) bool { return TypeOf(n) == "Group" }
+	require.Equal(t, []nodes.External{root["Tail"]}, TopLevelNodes(root, byType))
+
+	// no declarations
+	expr := nodes.Object{
+		KeyType: nodes.String("File"),
+		"Body":  nodes.Object{KeyType: nodes.String("Call"), KeyRoles: RoleList(role.Call, role.Expression)},
+	}
+	require.Nil(t, TopLevelDeclarations(expr))
+	require.Nil(t, TopLevelDeclarations(nil))
+}
+
+func TestTypes(t *testing.T) {
+	id := toNode(Identifier{GenNode: pos(0, 1, 1), Name: "a"})
+	root := nodes.Array{
+		id,
+		toNode(Identifier{Name: "b"}),
+		nodes.Object{"k": nodes.Object{}},
+		nodes.String("x"),
+		nil,
+	}
+	require.Equal(t, map[string]int{
+		TypeOf(Identifier{}): 2,
+		TypeOf(Positions{}):  2,
+		TypeOf(Position{}):   1,
+		"":                   2,
+	}, Types(root))
+	require.Empty(t, Types(nil))
+}
+
+func TestNodeAtOffset(t *testing.T) {
+	span := func(start, end uint32) GenNode {
+		return GenNode{
+			Positions: Positions{
+				KeyStart: Position{Offset: start, Line: 1, Col: start + 1},
+				KeyEnd:   Position{Offset: end, Line: 1, Col: end + 1},
+			},
+		}
+	}
+	// f(a, bc)
+	f := toNode(Identifier{GenNode: span(0, 1), Name: "f"})
+	a := toNode(Identifier{GenNode: span(2, 3), Name: "a"})
+	bc := toNode(Identifier{GenNode: span(5, 7), Name: "bc"})
+	empty1 := toNode(Block{GenNode: span(4, 4)})
+	empty2 := toNode(Block{GenNode: span(4, 4)})
+	call := nodes.Object{
+		KeyType: nodes.String("Call"),
+		KeyPos:  span(0, 8).Positions.ToObject(),
+		"Func":  f,
+		"Args": nodes.Array{
+			// wrapper without positions
+			nodes.Object{"x": a},
+			empty1,
+			empty2,
+			bc,
+		},
+	}
+	root := nodes.Array{call}
+
+	for _, c := range []struct {
+		name string
+		off  int
+		exp  nodes.Node
+	}{
+		{"narrowest", 0, f},
+		{"in wrapper", 2, a},
+		{"gap", 3, call},
+		{"zero width", 4, empty1},
+		{"nested", 6, bc},
+		{"closing", 7, call},
+	} {
+		got, ok := NodeAtOffset(root, c.off)
+		require.True(t, ok, c.name)
+		require.True(t, nodes.Same(c.exp, got.(nodes.Node)), "%s: %v", c.name, got)
+	}
+
+	_, ok := NodeAtOffset(root, 8)
+	require.False(t, ok)
+	_, ok = NodeAtOffset(root, -1)
+	require.False(t, ok)
+	_, ok = NodeAtOffset(nodes.Object{"x": toNode(Identifier{Name: "x"})}, 0)
+	require.False(t, ok)
+}
+
+func TestTrimToRange(t *testing.T) {
+	node := func(typ string, start, end uint32, fields nodes.Object) nodes.Object {
+		obj := nodes.Object{
+			KeyType: nodes.String(typ),
+			KeyPos: Positions{
+				KeyStart: {Offset: start, Line: 1, Col: start + 1},
+				KeyEnd:   {Offset: end, Line: 1, Col: end + 1},
+			}.ToObject(),
+		}
+		for k, v := range fields {
+			obj[k] = v
+		}
+		return obj
+	}
+	// three top-level declarations: [0,10), [10,20) and [20,30)
+	a1 := node("Ident", 0, 3, nodes.Object{"Name": nodes.String("a1")})
+	a2 := node("Ident", 6, 9, nodes.Object{"Name": nodes.String("a2")})
+	b1 := node("Ident", 10, 12, nodes.Object{"Name": nodes.String("b1")})
+	b2 := node("Ident", 16, 19, nodes.Object{"Name": nodes.String("b2")})
+	c1 := node("Ident", 21, 22, nodes.Object{"Name": nodes.String("c1")})
+	root := nodes.Object{
+		KeyType: nodes.String("File"),
+		"Decls": nodes.Array{
+			node("Func", 0, 10, nodes.Object{"Body": nodes.Array{a1, a2}, "Public": nodes.Bool(true)}),
+			node("Func", 10, 20, nodes.Object{"Body": nodes.Array{b1, b2}}),
+			// wrapper without positions
+			nodes.Object{"Decl": node("Func", 20, 30, nodes.Object{"Body": nodes.Array{c1}})},
+		},
+		"Comments": nodes.Array{
+			node("Comment", 25, 28, nil),
+		},
+		"Lang": nodes.String("go"),
+	}
+	orig := root.Clone()
+
+	out := TrimToRange(root, 5, 15)
+	require.Equal(t, nodes.Object{
+		KeyType: nodes.String("File"),
+		"Decls": nodes.Array{
+			node("Func", 0, 10, nodes.Object{"Body": nodes.Array{a2}, "Public": nodes.Bool(true)}),
+			node("Func", 10, 20, nodes.Object{"Body": nodes.Array{b1}}),
+		},
+		"Lang": nodes.String("go"),
+	}, out)
+	require.Equal(t, orig, root)
+	// untouched nodes are shared with the source tree
+	require.True(t, nodes.Same(a2, out.(nodes.Object)["Decls"].(nodes.Array)[0].(nodes.Object)["Body"].(nodes.Array)[0]))
+
+	out = TrimToRange(root, 21, 21)
+	require.Equal(t, nodes.Object{
+		KeyType: nodes.String("File"),
+		"Decls": nodes.Array{
+			nodes.Object{"Decl": node("Func", 20, 30, nodes.Object{"Body": nodes.Array{c1}})},
+		},
+		"Lang": nodes.String("go"),
+	}, out)
+
+	require.True(t, nodes.Same(root, TrimToRange(root, 0, 30)))
+	require.Nil(t, TrimToRange(root, 40, 50))
+}
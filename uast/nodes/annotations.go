@@ -0,0 +1,82 @@
+package nodes
+
+// Annotations is a side table that associates arbitrary Go values with nodes of the tree. It allows analysis
+// passes to share data without storing it in the tree, thus annotations never appear in the encoded UAST and
+// are not checked by the schema validation. The zero value is an empty table ready to use.
+//
+// Nodes are identified by reference (see Same), thus only non-nil objects and non-empty arrays can be annotated.
+// The table holds references to annotated nodes, which guarantees that their identity is stable for the lifetime
+// of the table. Transformations that copy nodes (see Apply) produce new nodes without annotations.
+//
+// Annotations is not safe for concurrent use.
+type Annotations struct {
+	m map[annotationKey]annotation
+}
+
+// annotationKey identifies an annotated node. Arrays that share the same backing array have the same UniqueKey,
+// thus the length is used to distinguish them.
+type annotationKey struct {
+	ptr Comparable
+	len int
+}
+
+func keyOf(n Node) annotationKey {
+	k := annotationKey{ptr: UniqueKey(n)}
+	if arr, ok := n.(Array); ok {
+		k.len = len(arr)
+	}
+	return k
+}
+
+type annotation struct {
+	node  Node
+	value interface{}
+}
+
+// hasIdentity checks if the node can be distinguished from other nodes with the same content.
+func hasIdentity(n Node) bool {
+	switch n := n.(type) {
+	case Object:
+		return n != nil
+	case Array:
+		return len(n) != 0
+	}
+	return false
+}
+
+// Set associates a value with the node, replacing the previous value. It returns false if the node cannot be
+// annotated, for example if it is a value node.
+func (a *Annotations) Set(n Node, v interface{}) bool {
+	if !hasIdentity(n) {
+		return false
+	}
+	if a.m == nil {
+		a.m = make(map[annotationKey]annotation)
+	}
+	a.m[keyOf(n)] = annotation{node: n, value: v}
+	return true
+}
+
+// Get returns the value associated with the node.
+func (a *Annotations) Get(n Node) (interface{}, bool) {
+	if !hasIdentity(n) {
+		return nil, false
+	}
+	e, ok := a.m[keyOf(n)]
+	if !ok {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Delete removes the value associated with the node.
+func (a *Annotations) Delete(n Node) {
+	if hasIdentity(n) {
+		delete(a.m, keyOf(n))
+	}
+}
+
+// Len returns the number of annotated nodes.
+func (a *Annotations) Len() int {
+	return len(a.m)
+}
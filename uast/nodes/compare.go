@@ -0,0 +1,104 @@
+package nodes
+
+import "math"
+
+// EqualOption is an option that relaxes the comparison of nodes, see NewComparer.
+type EqualOption func(c *Comparer)
+
+// IgnoreFields skips object fields with given names on all levels of the tree.
+func IgnoreFields(names ...string) EqualOption {
+	return func(c *Comparer) {
+		if c.ignore == nil {
+			c.ignore = make(map[string]struct{}, len(names))
+		}
+		for _, name := range names {
+			c.ignore[name] = struct{}{}
+		}
+	}
+}
+
+// FloatTolerance considers two Float values equal if the absolute difference between them is less than or equal to eps.
+// This is useful when different parsers emit slightly different representations of the same float literal.
+//
+// Int and Uint values are always compared exactly.
+func FloatTolerance(eps float64) EqualOption {
+	return func(c *Comparer) {
+		c.floatEps = eps
+	}
+}
+
+// Comparer checks if two trees are equal by value, according to a set of options.
+// The zero value compares nodes exactly, the same way as NodeEqual.
+type Comparer struct {
+	ignore map[string]struct{}
+	// floatEps is a maximal absolute difference between two float values that are considered equal
+	floatEps float64
+}
+
+// NewComparer creates a comparer with given options.
+func NewComparer(opts ...EqualOption) *Comparer {
+	c := &Comparer{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Ignored checks if the object field with a given name is skipped by the comparer.
+func (c *Comparer) Ignored(field string) bool {
+	_, ok := c.ignore[field]
+	return ok
+}
+
+// EqualValues compares two nodes that are not objects or arrays, taking the float tolerance into account.
+func (c *Comparer) EqualValues(a, b Node) bool {
+	if c.floatEps > 0 {
+		f1, ok1 := a.(Float)
+		f2, ok2 := b.(Float)
+		if ok1 && ok2 {
+			return f1 == f2 || math.Abs(float64(f1-f2)) <= c.floatEps
+		}
+	}
+	return NodeEqual(a, b)
+}
+
+// Equal compares two trees.
+func (c *Comparer) Equal(a, b Node) bool {
+	switch a := a.(type) {
+	case Object:
+		b, ok := b.(Object)
+		if !ok {
+			return false
+		}
+		for k, v := range a {
+			if c.Ignored(k) {
+				continue
+			}
+			v2, ok := b[k]
+			if !ok || !c.Equal(v, v2) {
+				return false
+			}
+		}
+		for k := range b {
+			if c.Ignored(k) {
+				continue
+			}
+			if _, ok := a[k]; !ok {
+				return false
+			}
+		}
+		return true
+	case Array:
+		b, ok := b.(Array)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !c.Equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return c.EqualValues(a, b)
+}
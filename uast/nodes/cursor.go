@@ -0,0 +1,288 @@
+package nodes
+
+import "sort"
+
+// Cursor is a zipper over a tree of nodes. It points to a single node of the tree and allows to move to the parent,
+// children and siblings of the node, and to edit the tree around it.
+//
+// Edits never modify the original tree. Instead, objects and arrays on the path from the root to the edited node are
+// copied, and the rest of the tree is shared with the original one. Thus, nodes that were not edited explicitly,
+// including their positional information, are left untouched. The edited tree is returned by Root.
+//
+// Children of objects are ordered by the field name, thus Left and Right move between fields of an object in the
+// sorted order.
+type Cursor struct {
+	stack []cursorFrame
+	cur   Node
+	// changed is set if the current node differs from the one stored in the parent
+	changed bool
+	// owned is set if the current node is a copy made by the cursor, thus it can be modified in place
+	owned bool
+}
+
+type cursorFrame struct {
+	parent  Node // Object or Array
+	elem    PathElem
+	changed bool
+	owned   bool
+}
+
+// NewCursor creates a cursor pointing to the root of the tree.
+func NewCursor(root Node) *Cursor {
+	return &Cursor{cur: root}
+}
+
+// Node returns the node the cursor points to.
+func (c *Cursor) Node() Node {
+	return c.cur
+}
+
+// Path returns a path from the root to the current node.
+func (c *Cursor) Path() Path {
+	path := make(Path, 0, len(c.stack))
+	for _, f := range c.stack {
+		path = append(path, f.elem)
+	}
+	return path
+}
+
+// IsRoot checks if the cursor points to the root of the tree.
+func (c *Cursor) IsRoot() bool {
+	return len(c.stack) == 0
+}
+
+// commit stores the current node in the parent, if it was changed.
+func (c *Cursor) commit() {
+	if !c.changed || len(c.stack) == 0 {
+		return
+	}
+	f := &c.stack[len(c.stack)-1]
+	f.own()
+	switch p := f.parent.(type) {
+	case Object:
+		p[f.elem.Key] = c.cur
+	case Array:
+		p[f.elem.Index] = c.cur
+	}
+	f.changed = true
+	c.changed = false
+}
+
+// own makes a copy of the parent node, unless it was already copied by the cursor.
+func (f *cursorFrame) own() {
+	if f.owned {
+		return
+	}
+	switch p := f.parent.(type) {
+	case Object:
+		f.parent = p.CloneObject()
+	case Array:
+		f.parent = p.CloneList()
+	}
+	f.owned = true
+}
+
+// move sets the current node to a child of the top-most parent.
+func (c *Cursor) move(e PathElem) {
+	f := &c.stack[len(c.stack)-1]
+	f.elem = e
+	if e.IsIndex() {
+		c.cur = f.parent.(Array)[e.Index]
+	} else {
+		c.cur = f.parent.(Object)[e.Key]
+	}
+	c.changed, c.owned = false, false
+}
+
+// Up moves the cursor to the parent node. It returns false if the cursor points to the root.
+func (c *Cursor) Up() bool {
+	if len(c.stack) == 0 {
+		return false
+	}
+	c.commit()
+	f := c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+	c.cur, c.changed, c.owned = f.parent, f.changed, f.owned
+	return true
+}
+
+// Down moves the cursor to the first child of the current node. For objects, it is the field with the smallest name.
+// It returns false if the current node is not an object or an array, or if it has no children.
+func (c *Cursor) Down() bool {
+	switch n := c.cur.(type) {
+	case Object:
+		if len(n) == 0 {
+			return false
+		}
+		return c.DownTo(FieldElem(n.Keys()[0]))
+	case Array:
+		return c.DownTo(IndexElem(0))
+	}
+	return false
+}
+
+// DownTo moves the cursor to a given field or element of the current node.
+// It returns false if the current node has no such child.
+func (c *Cursor) DownTo(e PathElem) bool {
+	switch n := c.cur.(type) {
+	case Object:
+		if e.IsIndex() {
+			return false
+		} else if _, ok := n[e.Key]; !ok {
+			return false
+		}
+	case Array:
+		if !e.IsIndex() || e.Index >= len(n) {
+			return false
+		}
+	default:
+		return false
+	}
+	c.stack = append(c.stack, cursorFrame{parent: c.cur, changed: c.changed, owned: c.owned})
+	c.move(e)
+	return true
+}
+
+// sibling returns a path element of the sibling of the current node at a given offset.
+func (c *Cursor) sibling(d int) (PathElem, bool) {
+	if len(c.stack) == 0 {
+		return PathElem{}, false
+	}
+	f := c.stack[len(c.stack)-1]
+	if f.elem.IsIndex() {
+		i := f.elem.Index + d
+		if i < 0 || i >= len(f.parent.(Array)) {
+			return PathElem{}, false
+		}
+		return IndexElem(i), true
+	}
+	keys := f.parent.(Object).Keys()
+	i := sort.SearchStrings(keys, f.elem.Key)
+	if i < len(keys) && keys[i] == f.elem.Key {
+		i += d
+	} else if d > 0 {
+		// the current field was deleted; the next one is already at the insertion point
+		i += d - 1
+	} else {
+		i += d
+	}
+	if i < 0 || i >= len(keys) {
+		return PathElem{}, false
+	}
+	return FieldElem(keys[i]), true
+}
+
+// Left moves the cursor to the previous sibling of the current node.
+// It returns false if the cursor points to the root or to the first child.
+func (c *Cursor) Left() bool {
+	e, ok := c.sibling(-1)
+	if !ok {
+		return false
+	}
+	c.commit()
+	c.move(e)
+	return true
+}
+
+// Right moves the cursor to the next sibling of the current node.
+// It returns false if the cursor points to the root or to the last child.
+func (c *Cursor) Right() bool {
+	e, ok := c.sibling(+1)
+	if !ok {
+		return false
+	}
+	c.commit()
+	c.move(e)
+	return true
+}
+
+// Replace replaces the current node with a new one. The cursor points to the new node afterwards.
+func (c *Cursor) Replace(n Node) {
+	c.cur, c.changed, c.owned = n, true, false
+}
+
+// InsertAfter inserts a node after the current one. The current node must be an element of an array.
+// The cursor keeps pointing to the current node. It returns false if the parent is not an array.
+func (c *Cursor) InsertAfter(n Node) bool {
+	if len(c.stack) == 0 || !c.stack[len(c.stack)-1].elem.IsIndex() {
+		return false
+	}
+	c.commit()
+	f := &c.stack[len(c.stack)-1]
+	p := f.parent.(Array)
+	i := f.elem.Index + 1
+	arr := make(Array, 0, len(p)+1)
+	arr = append(arr, p[:i]...)
+	arr = append(arr, n)
+	arr = append(arr, p[i:]...)
+	f.parent, f.changed, f.owned = arr, true, true
+	return true
+}
+
+// Delete removes the current node from the parent: an element is removed from an array and a field is removed
+// from an object. The cursor moves to the next sibling, the previous sibling if there is no next one, or to the parent
+// if there are no siblings left. It returns false if the cursor points to the root.
+func (c *Cursor) Delete() bool {
+	if len(c.stack) == 0 {
+		return false
+	}
+	f := &c.stack[len(c.stack)-1]
+	if f.elem.IsIndex() {
+		p := f.parent.(Array)
+		i := f.elem.Index
+		arr := make(Array, 0, len(p)-1)
+		arr = append(arr, p[:i]...)
+		arr = append(arr, p[i+1:]...)
+		f.parent, f.changed, f.owned = arr, true, true
+		c.changed = false
+		switch {
+		case i < len(arr):
+			c.move(IndexElem(i))
+		case i > 0:
+			c.move(IndexElem(i - 1))
+		default:
+			c.Up()
+		}
+		return true
+	}
+	f.own()
+	delete(f.parent.(Object), f.elem.Key)
+	f.changed = true
+	c.changed = false
+	if e, ok := c.sibling(+1); ok {
+		c.move(e)
+	} else if e, ok = c.sibling(-1); ok {
+		c.move(e)
+	} else {
+		c.Up()
+	}
+	return true
+}
+
+// Root returns the root of the edited tree. The cursor keeps pointing to the same node and can be used for further
+// edits, which will not affect the returned tree. If no edits were made, the original root is returned.
+func (c *Cursor) Root() Node {
+	c.commit()
+	v := c.cur
+	for i := len(c.stack) - 1; i >= 0; i-- {
+		f := &c.stack[i]
+		if i > 0 && f.changed {
+			p := &c.stack[i-1]
+			p.own()
+			switch pn := p.parent.(type) {
+			case Object:
+				pn[p.elem.Key] = f.parent
+			case Array:
+				pn[p.elem.Index] = f.parent
+			}
+			p.changed = true
+		}
+		v = f.parent
+	}
+	// the returned tree shares copies made by the cursor, thus they must be copied again on the next edit
+	c.owned = false
+	for i := range c.stack {
+		c.stack[i].owned = false
+	}
+	return v
+}
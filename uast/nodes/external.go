@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"fmt"
+	"sort"
 )
 
 // External is a node interface that can be implemented by other packages.
@@ -34,6 +35,22 @@ type ExternalObject interface {
 	ValueAt(key string) (External, bool)
 }
 
+// SortedKeys returns object keys in a sorted order. Object.Keys is always sorted, but external implementations
+// may not follow the contract of ExternalObject, thus callers that depend on a stable iteration order
+// (serialization, hashing, tree traversal) should use this function instead.
+func SortedKeys(o ExternalObject) []string {
+	if o == nil {
+		return nil
+	}
+	keys := o.Keys()
+	if sort.StringsAreSorted(keys) {
+		return keys
+	}
+	keys = append([]string{}, keys...)
+	sort.Strings(keys)
+	return keys
+}
+
 // toNodeExt converts the external node to a native node type.
 // The returned value is the copy of an original node.
 func toNodeExt(n External) (Node, error) {
@@ -7,7 +7,6 @@ import (
 	"hash"
 	"io"
 	"math"
-	"sort"
 )
 
 // HashSize is the size of hash used for nodes.
@@ -115,10 +114,7 @@ func (h *Hasher) hashObject(w io.Writer, obj ExternalObject) error {
 	if err != nil {
 		return err
 	}
-	keys := obj.Keys()
-	if !sort.StringsAreSorted(keys) {
-		return fmt.Errorf("object keys are not sorted: %T", obj)
-	}
+	keys := SortedKeys(obj)
 	for _, key := range keys {
 		v, ok := obj.ValueAt(key)
 		if !ok {
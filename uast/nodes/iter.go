@@ -69,7 +69,7 @@ func eachChild(n External, fnc func(v External)) {
 	switch KindOf(n) {
 	case KindObject:
 		if m, ok := n.(ExternalObject); ok {
-			keys := m.Keys()
+			keys := SortedKeys(m)
 			for _, k := range keys {
 				if v, _ := m.ValueAt(k); v != nil {
 					fnc(v)
@@ -92,7 +92,7 @@ func eachChildRev(n External, fnc func(v External)) {
 	switch KindOf(n) {
 	case KindObject:
 		if m, ok := n.(ExternalObject); ok {
-			keys := m.Keys()
+			keys := SortedKeys(m)
 			// reverse order
 			for i := len(keys) - 1; i >= 0; i-- {
 				if v, _ := m.ValueAt(keys[i]); v != nil {
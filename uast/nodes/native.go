@@ -0,0 +1,116 @@
+package nodes
+
+import "fmt"
+
+// ToGo converts a node to a generic Go representation that can be consumed by libraries
+// unaware of the node types (templates, JSON tools, etc).
+//
+// Objects are converted to map[string]interface{}, arrays to []interface{}, and values to
+// native scalars: string, int64, uint64, float64 and bool. Nil nodes are converted to nil.
+//
+// The returned value can be converted back with FromGo.
+func ToGo(n External) interface{} {
+	if n == nil {
+		return nil
+	}
+	if nd, ok := n.(Node); ok {
+		return nd.Native()
+	}
+	switch n.Kind() {
+	case KindNil:
+		return nil
+	case KindObject:
+		o, ok := n.(ExternalObject)
+		if !ok {
+			return nil
+		}
+		keys := o.Keys()
+		m := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			v, _ := o.ValueAt(k)
+			m[k] = ToGo(v)
+		}
+		return m
+	case KindArray:
+		a, ok := n.(ExternalArray)
+		if !ok {
+			return nil
+		}
+		sz := a.Size()
+		arr := make([]interface{}, 0, sz)
+		for i := 0; i < sz; i++ {
+			arr = append(arr, ToGo(a.ValueAt(i)))
+		}
+		return arr
+	default:
+		v := n.Value()
+		if v == nil {
+			return nil
+		}
+		return v.Native()
+	}
+}
+
+// FromGo converts a generic Go representation returned by ToGo back to a node.
+//
+// Unlike ToNode, numeric types are never reinterpreted: signed integers are converted to Int,
+// unsigned integers to Uint and floating point numbers to Float, even if they hold an integer value.
+// An error is returned for unsupported types.
+func FromGo(o interface{}) (Node, error) {
+	switch o := o.(type) {
+	case nil:
+		return nil, nil
+	case Node:
+		return o, nil
+	case map[string]interface{}:
+		n := make(Object, len(o))
+		for k, v := range o {
+			nv, err := FromGo(v)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = nv
+		}
+		return n, nil
+	case []interface{}:
+		n := make(Array, 0, len(o))
+		for _, v := range o {
+			nv, err := FromGo(v)
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nv)
+		}
+		return n, nil
+	case string:
+		return String(o), nil
+	case bool:
+		return Bool(o), nil
+	case int:
+		return Int(o), nil
+	case int8:
+		return Int(o), nil
+	case int16:
+		return Int(o), nil
+	case int32:
+		return Int(o), nil
+	case int64:
+		return Int(o), nil
+	case uint:
+		return Uint(o), nil
+	case uint8:
+		return Uint(o), nil
+	case uint16:
+		return Uint(o), nil
+	case uint32:
+		return Uint(o), nil
+	case uint64:
+		return Uint(o), nil
+	case float32:
+		return Float(o), nil
+	case float64:
+		return Float(o), nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %T", o)
+	}
+}
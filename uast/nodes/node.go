@@ -900,7 +900,7 @@ func WalkPreOrderExt(root External, walk func(External) bool) {
 	switch KindOf(root) {
 	case KindObject:
 		if n, ok := root.(ExternalObject); ok {
-			for _, k := range n.Keys() {
+			for _, k := range SortedKeys(n) {
 				v, _ := n.ValueAt(k)
 				WalkPreOrderExt(v, walk)
 			}
@@ -1,6 +1,7 @@
 package nodes
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -612,6 +613,94 @@ func TestNodeNative(t *testing.T) {
 	}
 }
 
+var casesGo = []struct {
+	name string
+	n    Node
+	v    interface{}
+}{
+	{name: "nil", n: nil, v: nil},
+	{name: "string", n: String("a"), v: "a"},
+	{name: "int", n: Int(-1), v: int64(-1)},
+	{name: "uint", n: Uint(1), v: uint64(1)},
+	{name: "float", n: Float(1), v: float64(1)},
+	{name: "bool", n: Bool(true), v: true},
+	{
+		name: "tree",
+		n: Object{
+			"@type": String("node"),
+			"arr":   Array{Int(1), nil, Float(2.5)},
+			"obj":   Object{"k": Bool(false), "n": nil},
+		},
+		v: map[string]interface{}{
+			"@type": "node",
+			"arr":   []interface{}{int64(1), nil, float64(2.5)},
+			"obj":   map[string]interface{}{"k": false, "n": nil},
+		},
+	},
+}
+
+func TestGoRoundTrip(t *testing.T) {
+	for _, c := range casesGo {
+		t.Run(c.name, func(t *testing.T) {
+			v := ToGo(c.n)
+			require.Equal(t, c.v, v)
+
+			n, err := FromGo(v)
+			require.NoError(t, err)
+			require.True(t, Equal(c.n, n))
+		})
+	}
+}
+
+func TestFromGo(t *testing.T) {
+	n, err := FromGo([]interface{}{int32(1), uint8(2), float32(3)})
+	require.NoError(t, err)
+	require.Equal(t, Array{Int(1), Uint(2), Float(3)}, n)
+
+	_, err = FromGo(map[string]interface{}{"k": struct{}{}})
+	require.Error(t, err)
+}
+
+var _ ExternalObject = reversedObject{}
+
+// reversedObject is an external object that violates the contract and returns keys in a reverse order.
+type reversedObject struct {
+	Object
+}
+
+func (o reversedObject) Keys() []string {
+	keys := o.Object.Keys()
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	return keys
+}
+
+func TestSortedKeys(t *testing.T) {
+	obj := Object{"c": Int(3), "a": Int(1), "b": Int(2)}
+	exp := []string{"a", "b", "c"}
+	require.Equal(t, exp, obj.Keys())
+	require.Equal(t, exp, SortedKeys(obj))
+
+	ext := reversedObject{obj}
+	require.Equal(t, []string{"c", "b", "a"}, ext.Keys())
+	require.Equal(t, exp, SortedKeys(ext))
+
+	var vals []External
+	it := NewIterator(ext, PreOrder)
+	for it.Next() {
+		vals = append(vals, it.Node())
+	}
+	require.Equal(t, []External{ext, Int(1), Int(2), Int(3)}, vals)
+
+	vals = nil
+	WalkPreOrderExt(ext, func(n External) bool {
+		vals = append(vals, n)
+		return true
+	})
+	require.Equal(t, []External{ext, Int(1), Int(2), Int(3)}, vals)
+
+	require.Equal(t, HashOf(obj), HashOf(ext))
+}
+
 func TestCount(t *testing.T) {
 	root := Array{
 		Int(3),
@@ -662,3 +751,273 @@ func BenchmarkNodeEqual(b *testing.B) {
 		})
 	}
 }
+
+func TestPathResolve(t *testing.T) {
+	leaf := String("v")
+	root := Object{
+		"a": Array{Int(1), Object{"b": leaf}},
+		"n": nil,
+	}
+	for _, c := range []struct {
+		path Path
+		str  string
+		exp  Node
+		ok   bool
+	}{
+		{path: nil, str: "", exp: root, ok: true},
+		{path: Path{FieldElem("a"), IndexElem(1), FieldElem("b")}, str: ".a[1].b", exp: leaf, ok: true},
+		{path: Path{FieldElem("a"), IndexElem(0)}, str: ".a[0]", exp: Int(1), ok: true},
+		{path: Path{FieldElem("n")}, str: ".n", exp: nil, ok: true},
+		{path: Path{FieldElem("a"), IndexElem(2)}, str: ".a[2]"},
+		{path: Path{FieldElem("a"), FieldElem("b")}, str: ".a.b"},
+		{path: Path{IndexElem(0)}, str: "[0]"},
+		{path: Path{FieldElem("n"), FieldElem("x")}, str: ".n.x"},
+	} {
+		require.Equal(t, c.str, c.path.String())
+		n, ok := c.path.Resolve(root)
+		require.Equal(t, c.ok, ok, "%q", c.str)
+		if c.ok {
+			require.True(t, Equal(c.exp, n), "%q", c.str)
+		}
+	}
+}
+
+func TestWalkPair(t *testing.T) {
+	a := Object{
+		"type": String("Call"),
+		"args": Array{String("x"), Object{"name": String("y")}, Int(1)},
+		"only": Object{"k": String("skipped")},
+		"n":    nil,
+	}
+	b := Object{
+		"type": String("Call"),
+		"args": Array{String("x"), Array{String("y")}},
+		"n":    nil,
+		"new":  Int(2),
+	}
+	type visit struct {
+		pa, pb string
+		na, nb Node
+	}
+	var got []visit
+	str := func(p Path) string {
+		if p == nil {
+			return "<nil>"
+		}
+		return "$" + p.String()
+	}
+	toNode := func(n External) Node {
+		if n == nil {
+			return nil
+		}
+		return n.(Node)
+	}
+	WalkPair(a, b, func(pa, pb Path, na, nb External) bool {
+		got = append(got, visit{pa: str(pa), pb: str(pb), na: toNode(na), nb: toNode(nb)})
+		return true
+	})
+	require.Equal(t, []visit{
+		{"$", "$", a, b},
+		{"$.args", "$.args", a["args"], b["args"]},
+		{"$.args[0]", "$.args[0]", String("x"), String("x")},
+		// shapes diverge: children are not visited
+		{"$.args[1]", "$.args[1]", Object{"name": String("y")}, Array{String("y")}},
+		{"$.args[2]", "<nil>", Int(1), nil},
+		{"$.n", "$.n", nil, nil},
+		{"<nil>", "$.new", nil, Int(2)},
+		{"$.only", "<nil>", a["only"], nil},
+		{"$.type", "$.type", String("Call"), String("Call")},
+	}, got)
+
+	// children are skipped if the callback returns false
+	n := 0
+	WalkPair(a, b, func(pa, pb Path, na, nb External) bool {
+		n++
+		return false
+	})
+	require.Equal(t, 1, n)
+}
+
+// sharedKeysObject is an external object that returns its internal slice of keys.
+type sharedKeysObject struct {
+	Object
+	keys []string
+}
+
+func (o sharedKeysObject) Keys() []string {
+	return o.keys
+}
+
+func TestWalkPairSharedKeys(t *testing.T) {
+	keys := make([]string, 0, 4)
+	keys = append(keys, "a", "b")
+	a := sharedKeysObject{Object: Object{"a": Int(1), "b": Int(2)}, keys: keys}
+	b := Object{"0": Int(0), "a": Int(1)}
+
+	var got []string
+	WalkPair(a, b, func(pa, pb Path, na, nb External) bool {
+		if pa != nil {
+			got = append(got, pa.String())
+		} else {
+			got = append(got, pb.String())
+		}
+		return true
+	})
+	require.Equal(t, []string{"", ".0", ".a", ".b"}, got)
+	// keys of the object are not modified
+	require.Equal(t, []string{"a", "b"}, a.keys)
+}
+
+func TestCursor(t *testing.T) {
+	pos := Object{"start": Int(1), "end": Int(5)}
+	root := Object{
+		"@type": String("File"),
+		"@pos":  pos,
+		"body": Array{
+			Object{"@type": String("Ident"), "name": String("a")},
+			Object{"@type": String("Ident"), "name": String("b")},
+		},
+	}
+	orig := root.Clone()
+
+	// no edits
+	c := NewCursor(root)
+	require.True(t, c.Down())
+	require.Equal(t, Path{FieldElem("@pos")}, c.Path())
+	require.True(t, c.Right())
+	require.True(t, c.Right())
+	require.Equal(t, Path{FieldElem("body")}, c.Path())
+	require.False(t, c.Right())
+	require.True(t, Same(root, c.Root()))
+
+	// insert after the first element and replace the second one
+	require.True(t, c.Down())
+	require.False(t, c.Left())
+	require.True(t, c.InsertAfter(Object{"@type": String("Ident"), "name": String("c")}))
+	require.True(t, c.Right())
+	require.Equal(t, Path{FieldElem("body"), IndexElem(1)}, c.Path())
+	require.True(t, c.Right())
+	require.True(t, c.DownTo(FieldElem("name")))
+	c.Replace(String("d"))
+	require.False(t, c.Down())
+
+	out := c.Root()
+	require.Equal(t, Object{
+		"@type": String("File"),
+		"@pos":  pos,
+		"body": Array{
+			Object{"@type": String("Ident"), "name": String("a")},
+			Object{"@type": String("Ident"), "name": String("c")},
+			Object{"@type": String("Ident"), "name": String("d")},
+		},
+	}, out)
+	require.True(t, Same(pos, out.(Object)["@pos"]))
+	require.True(t, Same(root["body"].(Array)[0], out.(Object)["body"].(Array)[0]))
+	require.Equal(t, orig, root)
+
+	// delete nodes; the previously returned tree must not change
+	out2 := out.Clone()
+	require.True(t, c.Up())
+	require.True(t, c.Delete())
+	require.Equal(t, Path{FieldElem("body"), IndexElem(1)}, c.Path())
+	require.True(t, c.Left())
+	require.True(t, c.Delete())
+	require.Equal(t, Path{FieldElem("body"), IndexElem(0)}, c.Path())
+	require.True(t, c.Delete())
+	require.Equal(t, Path{FieldElem("body")}, c.Path())
+	require.True(t, c.Delete())
+	require.Equal(t, Path{FieldElem("@type")}, c.Path())
+	require.True(t, c.Up())
+	require.True(t, c.IsRoot())
+	require.False(t, c.Up())
+	require.False(t, c.Delete())
+
+	require.Equal(t, Object{
+		"@type": String("File"),
+		"@pos":  pos,
+	}, c.Root())
+	require.Equal(t, out2, out)
+	require.Equal(t, orig, root)
+}
+
+func TestAnnotations(t *testing.T) {
+	a := Object{"name": String("a")}
+	b := Object{"name": String("b")}
+	list := Array{a, b}
+	root := Object{"type": String("File"), "decls": list}
+	orig := root.Clone()
+
+	var ann Annotations
+	WalkPreOrder(root, func(n Node) bool {
+		if obj, ok := n.(Object); ok {
+			ann.Set(obj, len(obj))
+		}
+		return true
+	})
+	require.Equal(t, 3, ann.Len())
+	require.True(t, ann.Set(list, "decls"))
+
+	// the same content, but a different node
+	v, ok := ann.Get(Object{"name": String("a")})
+	require.False(t, ok)
+	require.Nil(t, v)
+	_, ok = ann.Get(list[:1])
+	require.False(t, ok)
+
+	// a sub-slice shares the backing array, but is annotated separately
+	require.True(t, ann.Set(list[:1], "first"))
+	v, ok = ann.Get(list[:1])
+	require.True(t, ok)
+	require.Equal(t, "first", v)
+	v, ok = ann.Get(list)
+	require.True(t, ok)
+	require.Equal(t, "decls", v)
+	ann.Delete(list[:1])
+
+	// annotations survive a read-only traversal
+	var got []interface{}
+	WalkPreOrder(root, func(n Node) bool {
+		if v, ok := ann.Get(n); ok {
+			got = append(got, v)
+		}
+		return true
+	})
+	require.Equal(t, []interface{}{2, "decls", 1, 1}, got)
+
+	// values cannot be annotated
+	require.False(t, ann.Set(String("a"), 1))
+	require.False(t, ann.Set(Array{}, 1))
+	require.False(t, ann.Set(Object(nil), 1))
+
+	ann.Delete(list[:1])
+	require.Equal(t, 4, ann.Len())
+	ann.Delete(a)
+	_, ok = ann.Get(a)
+	require.False(t, ok)
+	require.Equal(t, 3, ann.Len())
+
+	// the tree is not changed, thus annotations never appear in the output
+	require.Equal(t, orig, root)
+	data, err := json.Marshal(root)
+	require.NoError(t, err)
+	require.Equal(t, `{"decls":[{"name":"a"},{"name":"b"}],"type":"File"}`, string(data))
+}
+
+func TestComparer(t *testing.T) {
+	a := Object{"v": Float(1.0), "pos": Int(1), "arr": Array{String("x")}}
+	b := Object{"v": Float(1.2), "pos": Int(2), "arr": Array{String("x")}}
+
+	require.False(t, NewComparer().Equal(a, b))
+	require.False(t, NewComparer(IgnoreFields("pos")).Equal(a, b))
+	require.False(t, NewComparer(FloatTolerance(0.5)).Equal(a, b))
+	require.True(t, NewComparer(IgnoreFields("pos"), FloatTolerance(0.5)).Equal(a, b))
+
+	// ignored fields may be missing on either side
+	delete(b, "pos")
+	require.True(t, NewComparer(IgnoreFields("pos"), FloatTolerance(0.5)).Equal(a, b))
+	require.True(t, NewComparer(IgnoreFields("pos"), FloatTolerance(0.5)).Equal(b, a))
+
+	// integers are always compared exactly
+	require.False(t, NewComparer(FloatTolerance(5)).Equal(Int(1), Int(2)))
+	require.False(t, NewComparer(FloatTolerance(5)).Equal(Int(1), Float(1)))
+}
@@ -0,0 +1,28 @@
+//go:build gofuzz
+// +build gofuzz
+
+package nodesproto
+
+import "bytes"
+
+// fuzzLimits bounds the size of UAST decoded by the fuzzers, to catch unbounded allocations.
+var fuzzLimits = Limits{MaxDepth: 1000, MaxNodes: 1 << 20}
+
+// FuzzNodeUnmarshal is a go-fuzz entry point for decoding the binary UAST format.
+func FuzzNodeUnmarshal(data []byte) int {
+	if _, err := ReadRaw(bytes.NewReader(data)); err != nil {
+		return 0
+	}
+	n, err := ReadTreeWithLimits(bytes.NewReader(data), fuzzLimits)
+	if err != nil {
+		return 0
+	}
+	buf := bytes.NewBuffer(nil)
+	if err = WriteTo(buf, n); err != nil {
+		panic(err)
+	}
+	if _, err = ReadTree(buf); err != nil {
+		panic(err)
+	}
+	return 1
+}
@@ -1,6 +1,7 @@
 package nodesproto
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
@@ -93,6 +94,17 @@ func WriteTo(w io.Writer, n nodes.Node) error {
 	return nil
 }
 
+// MarshalAppend appends the binary encoding of the tree to buf and returns the extended buffer.
+//
+// The output is the same as written by WriteTo, but the caller may reuse the buffer to avoid allocations.
+func MarshalAppend(buf []byte, n nodes.Node) ([]byte, error) {
+	b := bytes.NewBuffer(buf)
+	if err := WriteTo(b, n); err != nil {
+		return buf, err
+	}
+	return b.Bytes(), nil
+}
+
 func newTreeWriter() *treeWriter {
 	return &treeWriter{
 		vals: make(map[nodes.Value]uint64),
@@ -291,13 +303,83 @@ func (g *treeWriter) addNode(n nodes.Node) uint64 {
 // ReadTree reads a binary graph from r and tries to decode it as a tree.
 // If the graph is cyclic, an error is returned.
 func ReadTree(r io.Reader) (nodes.Node, error) {
+	return ReadTreeWithLimits(r, Limits{})
+}
+
+// Limits bounds the shape of a tree decoded by ReadTreeWithLimits.
+// Zero value of each field means no limit.
+type Limits struct {
+	// MaxDepth is the maximal nesting depth of the tree. The root node has depth 1.
+	MaxDepth int
+	// MaxNodes is the maximal number of decoded nodes, including object keys and values.
+	MaxNodes int
+}
+
+// LimitError is returned when a decoded tree exceeds one of the Limits.
+type LimitError struct {
+	// Limit is the name of the exceeded limit: "depth" or "nodes".
+	Limit string
+	// Max is the configured value of the limit.
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("tree exceeds the %s limit (%d)", e.Limit, e.Max)
+}
+
+// ReadTreeWithLimits is like ReadTree, but returns a *LimitError if the decoded tree exceeds the limits.
+// It should be used to decode the trees received from untrusted sources.
+func ReadTreeWithLimits(r io.Reader, l Limits) (nodes.Node, error) {
 	g := newGraphReader()
+	g.limits = l
 	if err := g.readGraph(r); err != nil {
 		return nil, err
 	}
 	return g.asTree()
 }
 
+// CheckLimits returns a *LimitError if the tree exceeds the limits. Nodes are counted the same way as in
+// ReadTreeWithLimits, thus it allows to enforce the same limits for trees decoded from other formats.
+func CheckLimits(n nodes.Node, l Limits) error {
+	if l.MaxDepth <= 0 && l.MaxNodes <= 0 {
+		return nil
+	}
+	count := 0
+	var check func(n nodes.Node, depth int) error
+	check = func(n nodes.Node, depth int) error {
+		if n == nil {
+			return nil
+		}
+		if max := l.MaxDepth; max > 0 && depth > max {
+			return &LimitError{Limit: "depth", Max: max}
+		}
+		count++
+		if max := l.MaxNodes; max > 0 && count > max {
+			return &LimitError{Limit: "nodes", Max: max}
+		}
+		switch n := n.(type) {
+		case nodes.Object:
+			for _, k := range n.Keys() {
+				// keys are stored as separate nodes
+				if err := check(nodes.String(k), depth+1); err != nil {
+					return err
+				}
+				if err := check(n[k], depth+1); err != nil {
+					return err
+				}
+			}
+		case nodes.Array:
+			for _, v := range n {
+				if err := check(v, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return check(n, 1)
+}
+
 type RawNode struct {
 	ID     uint64      `json:"id"`
 	Kind   nodes.Kind  `json:"kind"`
@@ -353,6 +435,9 @@ type graphReader struct {
 	root     uint64
 	meta     uint64
 	last     uint64
+
+	limits Limits
+	count  int // number of decoded nodes
 }
 
 func (g *graphReader) readHeader(r io.Reader) error {
@@ -465,9 +550,14 @@ func (g *graphReader) readGraph(r io.Reader) error {
 		if len(g.detached) == 1 {
 			g.root = g.detached[0]
 		} else {
+			// the last ID in the header may be wrong; make sure the new node does not replace an existing one
+			if g.last < prevID {
+				g.last = prevID
+			}
 			g.last++
 			id := g.last
 			g.nodes[id] = &Node{Id: id, Values: g.detached}
+			g.root = id
 		}
 	}
 	return nil
@@ -478,7 +568,8 @@ func (g *graphReader) asTree() (nodes.Node, error) {
 		return nil, nil
 	}
 	seen := make(map[uint64]bool, len(g.nodes))
-	return g.asNode(g.root, seen)
+	g.count = 0
+	return g.asNode(g.root, seen, 1)
 }
 
 func (m *Node) Kind() nodes.Kind {
@@ -507,10 +598,17 @@ func asValue(n *Node) (nodes.Value, error) {
 	}
 	return nil, fmt.Errorf("unsupported node type: %T", n.Value)
 }
-func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error) {
+func (g *graphReader) asNode(id uint64, seen map[uint64]bool, depth int) (nodes.Node, error) {
 	if id == 0 {
 		return nil, nil
 	}
+	if max := g.limits.MaxDepth; max > 0 && depth > max {
+		return nil, &LimitError{Limit: "depth", Max: max}
+	}
+	g.count++
+	if max := g.limits.MaxNodes; max > 0 && g.count > max {
+		return nil, &LimitError{Limit: "nodes", Max: max}
+	}
 	n, ok := g.nodes[id]
 	if !ok {
 		return nil, fmt.Errorf("node %v is not defined", id)
@@ -532,7 +630,7 @@ func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error
 				}
 				return false
 			}
-			if g.nodes[sid].Value == nil {
+			if sn := g.nodes[sid]; sn == nil || sn.Value == nil {
 				return false
 			}
 		}
@@ -546,7 +644,7 @@ func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error
 				}
 				return false
 			}
-			if g.nodes[sid].Value == nil {
+			if sn := g.nodes[sid]; sn == nil || sn.Value == nil {
 				return false
 			}
 		}
@@ -567,7 +665,7 @@ func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error
 		}
 		m := make(nodes.Object, len(n.Keys))
 		for i, k := range n.Keys {
-			nk, err := g.asNode(k, seen)
+			nk, err := g.asNode(k, seen, depth+1)
 			if err != nil {
 				return nil, err
 			}
@@ -576,7 +674,7 @@ func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error
 				return nil, fmt.Errorf("only string keys are supported")
 			}
 			v := n.Values[i]
-			nv, err := g.asNode(v, seen)
+			nv, err := g.asNode(v, seen, depth+1)
 			if err != nil {
 				return nil, err
 			}
@@ -586,7 +684,7 @@ func (g *graphReader) asNode(id uint64, seen map[uint64]bool) (nodes.Node, error
 	} else {
 		m := make(nodes.Array, 0, len(n.Values))
 		for _, v := range n.Values {
-			nv, err := g.asNode(v, seen)
+			nv, err := g.asNode(v, seen, depth+1)
 			if err != nil {
 				return nil, err
 			}
@@ -2,10 +2,12 @@ package nodesproto
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"testing"
 
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/nodes/nodesproto/pio"
 	"github.com/stretchr/testify/require"
 )
 
@@ -171,3 +173,94 @@ func TestTree(t *testing.T) {
 		})
 	}
 }
+
+func TestTreeLimits(t *testing.T) {
+	in := nodes.Object{
+		"a": nodes.Object{
+			"b": nodes.Object{
+				"c": nodes.Int(1),
+			},
+		},
+	}
+	buf := bytes.NewBuffer(nil)
+	err := WriteTo(buf, in)
+	require.NoError(t, err)
+
+	read := func(l Limits) (nodes.Node, error) {
+		return ReadTreeWithLimits(bytes.NewReader(buf.Bytes()), l)
+	}
+
+	out, err := read(Limits{})
+	require.NoError(t, err)
+	require.True(t, nodes.Equal(in, out))
+
+	out, err = read(Limits{MaxDepth: 4, MaxNodes: 7})
+	require.NoError(t, err)
+	require.True(t, nodes.Equal(in, out))
+
+	_, err = read(Limits{MaxDepth: 3})
+	require.Equal(t, &LimitError{Limit: "depth", Max: 3}, err)
+
+	_, err = read(Limits{MaxNodes: 6})
+	require.Equal(t, &LimitError{Limit: "nodes", Max: 6}, err)
+
+	// the same limits are enforced for decoded trees
+	require.NoError(t, CheckLimits(in, Limits{MaxDepth: 4, MaxNodes: 7}))
+	require.Equal(t, &LimitError{Limit: "depth", Max: 3}, CheckLimits(in, Limits{MaxDepth: 3}))
+	require.Equal(t, &LimitError{Limit: "nodes", Max: 6}, CheckLimits(in, Limits{MaxNodes: 6}))
+}
+
+func TestMarshalAppend(t *testing.T) {
+	prefix := []byte("prefix")
+	for _, c := range treeCases {
+		t.Run(c.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := WriteTo(buf, c.in)
+			require.NoError(t, err)
+
+			out, err := MarshalAppend(append([]byte{}, prefix...), c.in)
+			require.NoError(t, err)
+			require.Equal(t, prefix, out[:len(prefix)])
+			require.Equal(t, buf.Bytes(), out[len(prefix):])
+		})
+	}
+}
+
+func writeRawGraph(t testing.TB, gh *GraphHeader, list ...*Node) []byte {
+	buf := bytes.NewBuffer(nil)
+	var header [8]byte
+	copy(header[:4], magic)
+	binary.LittleEndian.PutUint32(header[4:], version)
+	buf.Write(header[:])
+	pw := pio.NewWriter(buf)
+	_, err := pw.WriteMsg(gh)
+	require.NoError(t, err)
+	for _, n := range list {
+		_, err = pw.WriteMsg(n)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func TestTreeMalformed(t *testing.T) {
+	str := func(id uint64, s string) *Node {
+		return &Node{Id: id, Value: &Node_String_{String_: s}}
+	}
+
+	// reference to an undefined node
+	data := writeRawGraph(t, &GraphHeader{Root: 1},
+		&Node{Id: 1, Values: []uint64{2, 5}},
+		str(2, "a"),
+	)
+	_, err := ReadTree(bytes.NewReader(data))
+	require.EqualError(t, err, "node 5 is not defined")
+
+	// the last ID in the header collides with existing nodes
+	data = writeRawGraph(t, &GraphHeader{LastId: 1},
+		str(1, "a"),
+		str(2, "b"),
+	)
+	out, err := ReadTree(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, nodes.Array{nodes.String("a"), nodes.String("b")}, out)
+}
@@ -0,0 +1,203 @@
+// Package nodestest provides helpers for comparing UAST nodes in tests.
+package nodestest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// ChangeType is a type of the difference between two trees.
+type ChangeType int
+
+const (
+	// Changed indicates that the node exists in both trees, but has a different value or kind.
+	Changed ChangeType = iota
+	// Added indicates that the node only exists in the actual tree.
+	Added
+	// Removed indicates that the node only exists in the expected tree.
+	Removed
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case Changed:
+		return "~"
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	}
+	return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Change is a single difference between the expected and the actual tree.
+type Change struct {
+	// Type is a type of the change.
+	Type ChangeType
+	// Path is a path to the node from the root, for example ".body[1].Name".
+	Path string
+	// Exp is the node in the expected tree. It is nil for Added changes.
+	Exp nodes.Node
+	// Got is the node in the actual tree. It is nil for Removed changes.
+	Got nodes.Node
+}
+
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "<root>"
+	}
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("%v %s: %s", c.Type, path, describe(c.Got))
+	case Removed:
+		return fmt.Sprintf("%v %s: %s", c.Type, path, describe(c.Exp))
+	}
+	return fmt.Sprintf("%v %s: %s -> %s", c.Type, path, describe(c.Exp), describe(c.Got))
+}
+
+// describe returns a compact description of the node: a value or a type of the subtree.
+func describe(n nodes.Node) string {
+	switch n := n.(type) {
+	case nil:
+		return "nil"
+	case nodes.Object:
+		if typ := uast.TypeOf(n); typ != "" {
+			return fmt.Sprintf("%s{%d fields}", typ, len(n))
+		}
+		return fmt.Sprintf("Object{%d fields}", len(n))
+	case nodes.Array:
+		return fmt.Sprintf("Array[%d]", len(n))
+	case nodes.String:
+		return strconv.Quote(string(n))
+	case nodes.Value:
+		return fmt.Sprintf("%v(%s)", n.Kind(), nodes.ToString(n))
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+// Option is an option for Diff and RequireEqual. It is the same option type used by nodes.NewComparer.
+type Option = nodes.EqualOption
+
+// IgnorePositions skips positional information (see uast.KeyPos) when comparing trees.
+func IgnorePositions() Option {
+	return IgnoreFields(uast.KeyPos)
+}
+
+// IgnoreFields skips object fields with given names when comparing trees. See nodes.IgnoreFields.
+func IgnoreFields(names ...string) Option {
+	return nodes.IgnoreFields(names...)
+}
+
+// FloatTolerance considers two Float values equal if the absolute difference between them is less than or equal
+// to eps. See nodes.FloatTolerance.
+func FloatTolerance(eps float64) Option {
+	return nodes.FloatTolerance(eps)
+}
+
+// differ collects differences between two trees.
+type differ struct {
+	*nodes.Comparer
+}
+
+// Diff compares two trees and returns a list of differences between them. Only the topmost differing node
+// is reported for each subtree, and changes are ordered by the path.
+func Diff(exp, got nodes.External, opts ...Option) ([]Change, error) {
+	c := differ{nodes.NewComparer(opts...)}
+	n1, err := nodes.ToNode(exp, nil)
+	if err != nil {
+		return nil, err
+	}
+	n2, err := nodes.ToNode(got, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []Change
+	c.diff(&out, "", n1, n2)
+	return out, nil
+}
+
+func (c differ) diff(out *[]Change, path string, exp, got nodes.Node) {
+	switch exp := exp.(type) {
+	case nodes.Object:
+		got, ok := got.(nodes.Object)
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{}, len(exp)+len(got))
+		for k := range exp {
+			keys[k] = struct{}{}
+		}
+		for k := range got {
+			keys[k] = struct{}{}
+		}
+		list := make([]string, 0, len(keys))
+		for k := range keys {
+			if !c.Ignored(k) {
+				list = append(list, k)
+			}
+		}
+		sort.Strings(list)
+		for _, k := range list {
+			sub := path + "." + k
+			v1, ok1 := exp[k]
+			v2, ok2 := got[k]
+			switch {
+			case !ok1:
+				*out = append(*out, Change{Type: Added, Path: sub, Got: v2})
+			case !ok2:
+				*out = append(*out, Change{Type: Removed, Path: sub, Exp: v1})
+			default:
+				c.diff(out, sub, v1, v2)
+			}
+		}
+		return
+	case nodes.Array:
+		got, ok := got.(nodes.Array)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(exp) || i < len(got); i++ {
+			sub := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(exp):
+				*out = append(*out, Change{Type: Added, Path: sub, Got: got[i]})
+			case i >= len(got):
+				*out = append(*out, Change{Type: Removed, Path: sub, Exp: exp[i]})
+			default:
+				c.diff(out, sub, exp[i], got[i])
+			}
+		}
+		return
+	}
+	if !c.EqualValues(exp, got) {
+		*out = append(*out, Change{Type: Changed, Path: path, Exp: exp, Got: got})
+	}
+}
+
+// RequireEqual checks that two trees are equal and fails the test with a compact path-based diff otherwise.
+func RequireEqual(t testing.TB, exp, got nodes.External, opts ...Option) {
+	t.Helper()
+	changes, err := Diff(exp, got, opts...)
+	if err != nil {
+		t.Fatalf("cannot compare trees: %v", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "trees are not equal (%d differences):\n", len(changes))
+	for _, ch := range changes {
+		buf.WriteString("\t")
+		buf.WriteString(ch.String())
+		buf.WriteString("\n")
+	}
+	t.Fatal(buf.String())
+}
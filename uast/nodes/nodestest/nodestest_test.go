@@ -0,0 +1,129 @@
+package nodestest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+func ident(name string, off uint32) nodes.Object {
+	return nodes.Object{
+		uast.KeyType: nodes.String("uast:Identifier"),
+		uast.KeyPos: uast.Positions{
+			uast.KeyStart: {Offset: off, Line: 1, Col: off + 1},
+		}.ToObject(),
+		"Name": nodes.String(name),
+	}
+}
+
+func TestDiff(t *testing.T) {
+	exp := nodes.Object{
+		uast.KeyType: nodes.String("Call"),
+		"func":       ident("f", 0),
+		"args":       nodes.Array{ident("a", 2), ident("b", 4)},
+		"async":      nodes.Bool(false),
+	}
+	got := nodes.Object{
+		uast.KeyType: nodes.String("Call"),
+		"func":       ident("g", 0),
+		"args":       nodes.Array{ident("a", 3)},
+		"kw":         nodes.Array{},
+		"async":      nodes.Int(0),
+	}
+
+	changes, err := Diff(exp, got)
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Type: Changed, Path: ".args[0].@pos.start.col", Exp: nodes.Uint(3), Got: nodes.Uint(4)},
+		{Type: Changed, Path: ".args[0].@pos.start.offset", Exp: nodes.Uint(2), Got: nodes.Uint(3)},
+		{Type: Removed, Path: ".args[1]", Exp: ident("b", 4)},
+		{Type: Changed, Path: ".async", Exp: nodes.Bool(false), Got: nodes.Int(0)},
+		{Type: Changed, Path: ".func.Name", Exp: nodes.String("f"), Got: nodes.String("g")},
+		{Type: Added, Path: ".kw", Got: nodes.Array{}},
+	}, changes)
+
+	changes, err = Diff(exp, got, IgnorePositions())
+	require.NoError(t, err)
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	require.Equal(t, []string{
+		`- .args[1]: uast:Identifier{3 fields}`,
+		`~ .async: Bool(false) -> Int(0)`,
+		`~ .func.Name: "f" -> "g"`,
+		`+ .kw: Array[0]`,
+	}, lines)
+
+	changes, err = Diff(exp, exp.CloneObject())
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+type fakeT struct {
+	testing.TB
+	msg string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatal(args ...interface{}) {
+	t.msg = fmt.Sprint(args...)
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.msg = fmt.Sprintf(format, args...)
+}
+
+func TestRequireEqual(t *testing.T) {
+	ft := &fakeT{TB: t}
+	RequireEqual(ft, ident("a", 0), ident("a", 1), IgnorePositions())
+	require.Empty(t, ft.msg)
+
+	RequireEqual(ft, ident("a", 0), ident("b", 0))
+	require.Equal(t, "trees are not equal (1 differences):\n\t~ .Name: \"a\" -> \"b\"\n", ft.msg)
+}
+
+func TestFloatTolerance(t *testing.T) {
+	lit := func(v nodes.Value) nodes.Object {
+		return nodes.Object{"value": v}
+	}
+	cases := []struct {
+		name  string
+		exp   nodes.Value
+		got   nodes.Value
+		equal bool
+	}{
+		{name: "literal", exp: nodes.Float(3.14), got: nodes.Float(3.1400000001), equal: true},
+		{name: "below", exp: nodes.Float(1), got: nodes.Float(1.4375), equal: true},
+		{name: "at", exp: nodes.Float(1), got: nodes.Float(1.5), equal: true},
+		{name: "above", exp: nodes.Float(1), got: nodes.Float(1.5625), equal: false},
+		{name: "above lower", exp: nodes.Float(1), got: nodes.Float(0.4375), equal: false},
+		{name: "int", exp: nodes.Int(1), got: nodes.Int(2), equal: false},
+		{name: "uint", exp: nodes.Uint(1), got: nodes.Uint(2), equal: false},
+		{name: "kinds", exp: nodes.Int(1), got: nodes.Float(1), equal: false},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			changes, err := Diff(lit(c.exp), lit(c.got), FloatTolerance(0.5))
+			require.NoError(t, err)
+			if c.equal {
+				require.Empty(t, changes)
+			} else {
+				require.Equal(t, []Change{
+					{Type: Changed, Path: ".value", Exp: c.exp, Got: c.got},
+				}, changes)
+			}
+		})
+	}
+
+	// without the option floats are compared exactly
+	changes, err := Diff(lit(nodes.Float(3.14)), lit(nodes.Float(3.1400000001)))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+}
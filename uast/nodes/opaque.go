@@ -0,0 +1,76 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Opaque values are values that cannot be represented by built-in value kinds without a loss of precision,
+// for example decimal or big number literals.
+//
+// By convention, an opaque value is stored as a String node with a type tag: OpaquePrefix, followed by the name
+// of the kind, a space and a text representation of the value, e.g. "@opaque:decimal 3.14". Since opaque values
+// are regular value nodes, queries and transformers treat them the same way as other values, and they round-trip
+// losslessly through all codecs. Opaque kinds must be registered with RegisterOpaqueKind before use.
+const OpaquePrefix = "@opaque:"
+
+var opaqueKinds = struct {
+	sync.RWMutex
+	m map[string]func(text string) error
+}{m: make(map[string]func(string) error)}
+
+// RegisterOpaqueKind registers a new kind of opaque values. The validate function checks that the text
+// is a valid representation of the value; it may be nil. It panics if the kind is already registered,
+// or if the name is empty or contains spaces.
+func RegisterOpaqueKind(kind string, validate func(text string) error) {
+	if kind == "" || strings.ContainsAny(kind, " \t\n") {
+		panic(fmt.Errorf("invalid opaque kind name: %q", kind))
+	}
+	opaqueKinds.Lock()
+	defer opaqueKinds.Unlock()
+	if _, ok := opaqueKinds.m[kind]; ok {
+		panic(fmt.Errorf("opaque kind %q is already registered", kind))
+	}
+	opaqueKinds.m[kind] = validate
+}
+
+// NewOpaque creates an opaque value node of a given kind. The kind must be registered with RegisterOpaqueKind.
+func NewOpaque(kind, text string) (String, error) {
+	opaqueKinds.RLock()
+	validate, ok := opaqueKinds.m[kind]
+	opaqueKinds.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown opaque kind: %q", kind)
+	}
+	if validate != nil {
+		if err := validate(text); err != nil {
+			return "", fmt.Errorf("invalid %s value %q: %v", kind, text, err)
+		}
+	}
+	return String(OpaquePrefix + kind + " " + text), nil
+}
+
+// AsOpaque checks if the node is an opaque value node of a registered kind and returns its kind and text.
+func AsOpaque(n External) (kind, text string, ok bool) {
+	if n == nil || n.Kind() != KindString {
+		return "", "", false
+	}
+	s, ok := n.Value().(String)
+	if !ok || !strings.HasPrefix(string(s), OpaquePrefix) {
+		return "", "", false
+	}
+	str := strings.TrimPrefix(string(s), OpaquePrefix)
+	i := strings.IndexByte(str, ' ')
+	if i < 0 {
+		return "", "", false
+	}
+	kind, text = str[:i], str[i+1:]
+	opaqueKinds.RLock()
+	_, ok = opaqueKinds.m[kind]
+	opaqueKinds.RUnlock()
+	if !ok {
+		return "", "", false
+	}
+	return kind, text, true
+}
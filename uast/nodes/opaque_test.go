@@ -0,0 +1,96 @@
+package nodes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/nodes/nodesproto"
+	"github.com/bblfsh/sdk/v3/uast/uastyaml"
+)
+
+const opaqueDecimal = "test:decimal"
+
+func init() {
+	nodes.RegisterOpaqueKind(opaqueDecimal, func(text string) error {
+		_, _, err := big.ParseFloat(text, 10, 512, big.ToNearestEven)
+		return err
+	})
+}
+
+func TestOpaqueRoundTrip(t *testing.T) {
+	// more digits than a float64 can hold
+	const text = "3.14159265358979323846264338327950288419716939937510582097494459"
+
+	v, err := nodes.NewOpaque(opaqueDecimal, text)
+	require.NoError(t, err)
+	require.Equal(t, nodes.KindString, v.Kind())
+	root := nodes.Object{"value": v, "other": nodes.Float(1.5)}
+
+	check := func(t *testing.T, out nodes.Node) {
+		obj, ok := out.(nodes.Object)
+		require.True(t, ok)
+		kind, got, ok := nodes.AsOpaque(obj["value"])
+		require.True(t, ok)
+		require.Equal(t, opaqueDecimal, kind)
+		require.Equal(t, text, got)
+
+		exp, _, err := big.ParseFloat(text, 10, 512, big.ToNearestEven)
+		require.NoError(t, err)
+		f, _, err := big.ParseFloat(got, 10, 512, big.ToNearestEven)
+		require.NoError(t, err)
+		require.Zero(t, exp.Cmp(f))
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(root)
+		require.NoError(t, err)
+		var m interface{}
+		require.NoError(t, json.Unmarshal(data, &m))
+		out, err := nodes.ToNode(m, nil)
+		require.NoError(t, err)
+		check(t, out)
+	})
+	t.Run("binary", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, nodesproto.WriteTo(buf, root))
+		out, err := nodesproto.ReadTree(buf)
+		require.NoError(t, err)
+		check(t, out)
+	})
+	t.Run("yaml", func(t *testing.T) {
+		data, err := uastyaml.Marshal(root)
+		require.NoError(t, err)
+		out, err := uastyaml.Unmarshal(data)
+		require.NoError(t, err)
+		check(t, out)
+	})
+}
+
+func TestOpaqueInvalid(t *testing.T) {
+	_, err := nodes.NewOpaque(opaqueDecimal, "1.2.3")
+	require.Error(t, err)
+
+	_, err = nodes.NewOpaque("test:unknown", "1")
+	require.Error(t, err)
+
+	_, _, ok := nodes.AsOpaque(nodes.String(nodes.OpaquePrefix + "test:unknown 1"))
+	require.False(t, ok)
+
+	_, _, ok = nodes.AsOpaque(nodes.String(nodes.OpaquePrefix + opaqueDecimal))
+	require.False(t, ok)
+
+	_, _, ok = nodes.AsOpaque(nodes.String("1"))
+	require.False(t, ok)
+
+	require.Panics(t, func() {
+		nodes.RegisterOpaqueKind(opaqueDecimal, nil)
+	})
+	require.Panics(t, func() {
+		nodes.RegisterOpaqueKind("test:big decimal", nil)
+	})
+}
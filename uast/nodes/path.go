@@ -0,0 +1,141 @@
+package nodes
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PathElem is a single step of a Path. It is either a field of an object or an index of an array element.
+type PathElem struct {
+	// Key is a name of the object field. Only used if Index is negative.
+	Key string
+	// Index is an index of the array element, or -1 for object fields.
+	Index int
+}
+
+// FieldElem returns a path element that selects an object field with a given name.
+func FieldElem(key string) PathElem {
+	return PathElem{Key: key, Index: -1}
+}
+
+// IndexElem returns a path element that selects an array element with a given index.
+func IndexElem(i int) PathElem {
+	return PathElem{Index: i}
+}
+
+// IsIndex checks if the element selects an array element.
+func (e PathElem) IsIndex() bool {
+	return e.Index >= 0
+}
+
+// String returns a string representation of the element, either ".key" or "[index]".
+func (e PathElem) String() string {
+	if e.IsIndex() {
+		return "[" + strconv.Itoa(e.Index) + "]"
+	}
+	return "." + e.Key
+}
+
+// Path is a path from the root of the tree to one of its nodes. An empty path points to the root itself.
+type Path []PathElem
+
+// String returns a string representation of the path, for example ".body[1].name".
+func (p Path) String() string {
+	var buf strings.Builder
+	for _, e := range p {
+		buf.WriteString(e.String())
+	}
+	return buf.String()
+}
+
+// Resolve returns a node of the tree that the path points to. It returns false if the path does not exist in the tree.
+func (p Path) Resolve(root External) (External, bool) {
+	cur := root
+	for _, e := range p {
+		if e.IsIndex() {
+			arr, ok := cur.(ExternalArray)
+			if !ok || cur.Kind() != KindArray || e.Index >= arr.Size() {
+				return nil, false
+			}
+			cur = arr.ValueAt(e.Index)
+			continue
+		}
+		obj, ok := cur.(ExternalObject)
+		if !ok || cur.Kind() != KindObject {
+			return nil, false
+		}
+		cur, ok = obj.ValueAt(e.Key)
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// WalkPair walks two trees in lockstep, in pre-order. The callback receives the nodes of both trees at the same path,
+// and returns false to skip the children of these nodes.
+//
+// The walk only descends into pairs of objects and pairs of arrays. Fields of objects are visited in sorted order,
+// and if one of the objects lacks a field, or one of the arrays is shorter, the callback is invoked with a nil node
+// and a nil path for the missing side. Children of such pairs are not visited. Note that a field with a nil value
+// is reported with a non-nil path. Paths passed to the callback can be retained.
+func WalkPair(a, b External, fn func(pa, pb Path, na, nb External) bool) {
+	walkPair(Path{}, a, b, true, true, fn)
+}
+
+func walkPair(path Path, a, b External, okA, okB bool, fn func(pa, pb Path, na, nb External) bool) {
+	var pa, pb Path
+	if okA {
+		pa = append(Path{}, path...)
+	}
+	if okB {
+		pb = append(Path{}, path...)
+	}
+	if !fn(pa, pb, a, b) || !okA || !okB {
+		return
+	}
+	ka, kb := KindOf(a), KindOf(b)
+	switch {
+	case ka == KindObject && kb == KindObject:
+		oa, ok1 := a.(ExternalObject)
+		ob, ok2 := b.(ExternalObject)
+		if !ok1 || !ok2 {
+			return
+		}
+		// the keys slice may be owned by the object, thus it must be copied before appending to it
+		keys := append([]string(nil), SortedKeys(oa)...)
+		for _, k := range ob.Keys() {
+			if _, ok := oa.ValueAt(k); !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			va, okA := oa.ValueAt(k)
+			vb, okB := ob.ValueAt(k)
+			walkPair(append(path, FieldElem(k)), va, vb, okA, okB, fn)
+		}
+	case ka == KindArray && kb == KindArray:
+		aa, ok1 := a.(ExternalArray)
+		ab, ok2 := b.(ExternalArray)
+		if !ok1 || !ok2 {
+			return
+		}
+		na, nb := aa.Size(), ab.Size()
+		n := na
+		if nb > n {
+			n = nb
+		}
+		for i := 0; i < n; i++ {
+			var va, vb External
+			if i < na {
+				va = aa.ValueAt(i)
+			}
+			if i < nb {
+				vb = ab.ValueAt(i)
+			}
+			walkPair(append(path, IndexElem(i)), va, vb, i < na, i < nb, fn)
+		}
+	}
+}
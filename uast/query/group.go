@@ -0,0 +1,75 @@
+package query
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// NewSliceIterator creates an iterator over a list of nodes, for example a list of collected query results.
+func NewSliceIterator(list []nodes.External) Iterator {
+	return &sliceIter{list: list, i: -1}
+}
+
+type sliceIter struct {
+	list []nodes.External
+	i    int
+}
+
+// Next implements Iterator.
+func (it *sliceIter) Next() bool {
+	if it.i >= len(it.list) {
+		return false
+	}
+	it.i++
+	return it.i < len(it.list)
+}
+
+// Node implements Iterator.
+func (it *sliceIter) Node() nodes.External {
+	if it.i < 0 || it.i >= len(it.list) {
+		return nil
+	}
+	return it.list[it.i]
+}
+
+// GroupByType groups query results by their type (see uast.KeyType). Results that are not objects or have
+// no type are grouped under an empty string. The order of results in each group is preserved.
+//
+// See NewSliceIterator for grouping a list of nodes.
+func GroupByType(it Iterator) map[string][]nodes.External {
+	out := make(map[string][]nodes.External)
+	for it.Next() {
+		n := it.Node()
+		typ := uast.TypeOf(n)
+		out[typ] = append(out[typ], n)
+	}
+	return out
+}
+
+// GroupByRole groups query results by their roles (see uast.KeyRoles). A result with multiple roles is added to
+// the group of each role. Results that have no roles are grouped under an empty string. The order of results
+// in each group is preserved.
+//
+// See NewSliceIterator for grouping a list of nodes.
+func GroupByRole(it Iterator) map[string][]nodes.External {
+	out := make(map[string][]nodes.External)
+	for it.Next() {
+		n := it.Node()
+		roles := uast.AnnotatedRoles(n)
+		if len(roles) == 0 {
+			out[""] = append(out[""], n)
+			continue
+		}
+		seen := make(map[role.Role]struct{}, len(roles))
+		for _, r := range roles {
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			k := r.String()
+			out[k] = append(out[k], n)
+		}
+	}
+	return out
+}
@@ -1,6 +1,8 @@
 package query
 
 import (
+	"context"
+
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 )
 
@@ -16,8 +18,60 @@ type Query interface {
 	Execute(root nodes.External) (Iterator, error)
 }
 
+// ContextQuery is an optional interface for queries that can be cancelled during the execution.
+type ContextQuery interface {
+	Query
+	// ExecuteCtx is like Execute, but stops the execution when the context is cancelled.
+	//
+	// Since results are computed lazily, the cancellation may also stop the returned iterator.
+	// In this case, the iterator implements ErrorIterator and reports the context error.
+	ExecuteCtx(ctx context.Context, root nodes.External) (Iterator, error)
+}
+
+// ExecuteCtx runs a query for a given subtree and stops the execution when the context is cancelled.
+// If the query does not implement ContextQuery, the context is only checked before the execution.
+func ExecuteCtx(ctx context.Context, q Query, root nodes.External) (Iterator, error) {
+	if cq, ok := q.(ContextQuery); ok {
+		return cq.ExecuteCtx(ctx, root)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return q.Execute(root)
+}
+
 type Iterator = nodes.Iterator
 
+// MatchType is a type of the query match.
+type MatchType int
+
+const (
+	// MatchNode is a match of a tree node (element).
+	MatchNode MatchType = iota
+	// MatchAttribute is a match of a node attribute. The iterator returns the attribute value as nodes.String.
+	MatchAttribute
+	// MatchText is a match of a text content of a node. The iterator returns the value node.
+	MatchText
+	// MatchValue is a single computed value, for example a result of count() or boolean expression.
+	MatchValue
+)
+
+// MatchIterator is an optional interface for query iterators that can report the type of the current match.
+type MatchIterator interface {
+	Iterator
+	// MatchType returns the type of the current match.
+	MatchType() MatchType
+}
+
+// MatchTypeOf returns the type of the current match of the iterator.
+// It returns MatchNode if the iterator does not implement MatchIterator.
+func MatchTypeOf(it Iterator) MatchType {
+	if mit, ok := it.(MatchIterator); ok {
+		return mit.MatchType()
+	}
+	return MatchNode
+}
+
 // AllNodes iterates over all nodes and returns them as a slice.
 func AllNodes(it Iterator) []nodes.External {
 	var out []nodes.External
@@ -35,3 +89,44 @@ func Count(it Iterator) int {
 	}
 	return n
 }
+
+// PathIterator is an optional interface for query iterators that can report the location of the current match.
+type PathIterator interface {
+	Iterator
+	// Path returns a path from the root of the tree to the current match. For attribute matches,
+	// it returns the path of the node that owns the attribute.
+	Path() nodes.Path
+}
+
+// PathOf returns a path from the root of the tree to the current match of the iterator.
+// It returns false if the iterator does not implement PathIterator.
+func PathOf(it Iterator) (nodes.Path, bool) {
+	if pit, ok := it.(PathIterator); ok {
+		return pit.Path(), true
+	}
+	return nil, false
+}
+
+// ErrorIterator is an optional interface for query iterators that may stop early because of an error,
+// for example when the query execution is cancelled.
+type ErrorIterator interface {
+	Iterator
+	// Err returns an error that stopped the iteration, if any.
+	Err() error
+}
+
+// IteratorErr returns an error that stopped the iteration, if any.
+// It returns nil if the iterator does not implement ErrorIterator.
+func IteratorErr(it Iterator) error {
+	if eit, ok := it.(ErrorIterator); ok {
+		return eit.Err()
+	}
+	return nil
+}
+
+// ParamQuery is an optional interface for queries that accept parameters bound at execution time.
+type ParamQuery interface {
+	Query
+	// ExecuteParams runs a query for a given subtree, binding values of the query parameters.
+	ExecuteParams(root nodes.External, params map[string]interface{}) (Iterator, error)
+}
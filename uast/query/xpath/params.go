@@ -0,0 +1,208 @@
+package xpath
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xpath"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/query"
+)
+
+var _ query.ParamQuery = (*xQuery)(nil)
+
+// template is an XPath expression split by variable references ($name).
+type template struct {
+	parts []string // len(parts) == len(vars)+1
+	vars  []string
+}
+
+// parseTemplate finds all variable references in the expression, skipping string literals.
+// It returns nil if the expression has no variables.
+func parseTemplate(expr string) *template {
+	var (
+		t     template
+		last  int
+		quote byte
+	)
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"':
+			quote = c
+			continue
+		case c != '$':
+			continue
+		}
+		j := i + 1
+		for j < len(expr) && isNameChar(expr[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			continue // not a variable; let the parser report an error
+		}
+		t.parts = append(t.parts, expr[last:i])
+		t.vars = append(t.vars, expr[i+1:j])
+		last = j
+		i = j - 1
+	}
+	if len(t.vars) == 0 {
+		return nil
+	}
+	t.parts = append(t.parts, expr[last:])
+	return &t
+}
+
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case first:
+		return false
+	}
+	return c == '-' || c == '.' || ('0' <= c && c <= '9')
+}
+
+// bind replaces variable references with XPath literals for the values of parameters.
+func (t *template) bind(params map[string]interface{}) (string, error) {
+	var buf strings.Builder
+	for i, name := range t.vars {
+		buf.WriteString(t.parts[i])
+		v, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("parameter $%s is not set", name)
+		}
+		lit, err := literal(v)
+		if err != nil {
+			return "", fmt.Errorf("parameter $%s: %v", name, err)
+		}
+		buf.WriteString(lit)
+	}
+	buf.WriteString(t.parts[len(t.parts)-1])
+	return buf.String(), nil
+}
+
+// compileEmpty compiles the expression with all parameters set to empty strings to check its syntax.
+func (t *template) compileEmpty() error {
+	params := make(map[string]interface{}, len(t.vars))
+	for _, name := range t.vars {
+		params[name] = ""
+	}
+	expr, err := t.bind(params)
+	if err != nil {
+		return err
+	}
+	_, err = xpath.Compile(expr)
+	return err
+}
+
+// unboundError returns an error for executing a query without binding its parameters.
+func (t *template) unboundError() error {
+	names := append([]string{}, t.vars...)
+	sort.Strings(names)
+	return fmt.Errorf("query parameters are not bound: $%s", strings.Join(names, ", $"))
+}
+
+// literal converts a Go value to an XPath literal.
+func literal(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nodes.String:
+		return stringLiteral(string(v)), nil
+	case string:
+		return stringLiteral(v), nil
+	case nodes.Bool:
+		return boolLiteral(bool(v)), nil
+	case bool:
+		return boolLiteral(v), nil
+	case nodes.Int:
+		return numLiteral(float64(v))
+	case nodes.Uint:
+		return numLiteral(float64(v))
+	case nodes.Float:
+		return numLiteral(float64(v))
+	case int:
+		return numLiteral(float64(v))
+	case int64:
+		return numLiteral(float64(v))
+	case uint:
+		return numLiteral(float64(v))
+	case uint64:
+		return numLiteral(float64(v))
+	case float64:
+		return numLiteral(v)
+	}
+	return "", fmt.Errorf("unsupported type: %T", v)
+}
+
+// stringLiteral quotes the string for XPath. XPath 1.0 has no escape sequences in string literals, thus strings
+// with both kinds of quotes are split into multiple literals that are joined with concat().
+func stringLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	} else if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	var args []string
+	for i, p := range strings.Split(s, "'") {
+		if i != 0 {
+			args = append(args, `"'"`)
+		}
+		if p != "" {
+			args = append(args, "'"+p+"'")
+		}
+	}
+	if len(args) == 1 {
+		// concat requires at least two arguments
+		args = append(args, "''")
+	}
+	return "concat(" + strings.Join(args, ", ") + ")"
+}
+
+func boolLiteral(v bool) string {
+	if v {
+		return "true()"
+	}
+	return "false()"
+}
+
+func numLiteral(v float64) (string, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", fmt.Errorf("unsupported number: %v", v)
+	}
+	if v < 0 {
+		// the engine does not support unary minus
+		return "(0 - " + strconv.FormatFloat(-v, 'f', -1, 64) + ")", nil
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), nil
+}
+
+// ExecuteParams implements query.ParamQuery.
+//
+// Parameters are referenced in the expression as $name. Their values are bound as XPath literals,
+// thus strings with quotes are matched as is, and cannot change the structure of the expression.
+// Strings, booleans and numbers are supported. Expressions with parameters are compiled on each execution.
+func (q *xQuery) ExecuteParams(root nodes.External, params map[string]interface{}) (query.Iterator, error) {
+	if q.tmpl == nil {
+		return q.execute(root, nil)
+	}
+	expr, err := q.tmpl.bind(params)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	q2 := &xQuery{idx: q.idx, exp: exp}
+	return q2.execute(root, nil)
+}
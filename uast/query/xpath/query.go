@@ -1,9 +1,11 @@
 package xpath
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/antchfx/xpath"
 
@@ -15,8 +17,11 @@ import (
 var _ xpath.NodeNavigator = &nodeNavigator{}
 
 // newNavigator creates a new xpath.nodeNavigator for the specified html.node.
-func newNavigator(root nodes.External) *nodeNavigator {
-	n := &node{n: root, typ: rootNode}
+func newNavigator(root nodes.External, opt *Options) *nodeNavigator {
+	if opt == nil {
+		opt = &Options{}
+	}
+	n := &node{n: root, typ: rootNode, opt: opt}
 	return &nodeNavigator{root: n, cur: n, attri: -1}
 }
 
@@ -37,6 +42,8 @@ const (
 type attr struct {
 	key string
 	val string
+	// null is set for attributes projected from null values; their string value is empty
+	null bool
 }
 
 type node struct {
@@ -45,18 +52,111 @@ type node struct {
 	n    nodes.External
 	kind nodes.Kind
 	obj  nodes.ExternalObject
+	opt  *Options
 
 	tag    [2]string
 	attrs  []attr
 	sub    []*node
 	par    *node
 	parInd int // index in parent's sub array
+
+	// attributes and children are projected lazily and shared by all navigator copies
+	attrsOnce sync.Once
+	subOnce   sync.Once
+}
+
+// attributes returns the projected attributes of the node, loading them on the first call.
+func (nd *node) attributes() []attr {
+	nd.attrsOnce.Do(func() {
+		if nd.attrs == nil && nd.typ != rootNode {
+			nd.loadAttributes()
+		}
+	})
+	return nd.attrs
+}
+
+// children returns the projected children of the node, loading them on the first call.
+func (nd *node) children() []*node {
+	nd.subOnce.Do(func() {
+		if nd.sub != nil {
+			return
+		}
+		switch nd.typ {
+		case rootNode:
+			// the same node, but without the root type
+			n := toNode(nd.n, "", nd.opt)
+			n.par = nd
+			nd.sub = []*node{n}
+		case objectNode:
+			if nd.obj != nil {
+				nd.loadChildren()
+			}
+		}
+	})
+	return nd.sub
+}
+
+// path returns a path from the root of the tree to the node.
+func (nd *node) path() nodes.Path {
+	var rev nodes.Path
+	for n := nd; n.par != nil; n = n.par {
+		p := n.par
+		switch p.typ {
+		case fieldNode:
+			if p.kind == nodes.KindArray {
+				rev = append(rev, nodes.IndexElem(n.parInd))
+			}
+			// otherwise, it's a field wrapper of this node
+		case objectNode:
+			if n.typ == fieldNode {
+				rev = append(rev, nodes.FieldElem(n.tag[1]))
+			} else {
+				// only the token is projected without the field wrapper
+				rev = append(rev, nodes.FieldElem(uast.KeyToken))
+			}
+		}
+	}
+	out := make(nodes.Path, 0, len(rev))
+	for i := len(rev) - 1; i >= 0; i-- {
+		out = append(out, rev[i])
+	}
+	return out
+}
+
+// cancelCheckInterval is the number of navigator moves between two checks of the context.
+const cancelCheckInterval = 1024
+
+// cancelled is a panic value used to abort the query execution when the context is cancelled.
+type cancelled struct {
+	err error
+}
+
+// execControl is shared by all copies of the navigator used in a single query execution.
+type execControl struct {
+	ctx   context.Context
+	moves int
+}
+
+// tick counts navigator moves and aborts the execution if the context is cancelled.
+// Since the context is only checked periodically, the overhead is negligible.
+func (c *execControl) tick() {
+	if c == nil {
+		return
+	}
+	c.moves++
+	if c.moves%cancelCheckInterval != 0 {
+		return
+	}
+	if err := c.ctx.Err(); err != nil {
+		panic(cancelled{err: err})
+	}
 }
 
 // nodeNavigator is for navigating JSON document.
 type nodeNavigator struct {
 	root, cur *node
 	attri     int
+	ctl       *execControl
 }
 
 func (a *nodeNavigator) Current() nodes.External {
@@ -99,6 +199,10 @@ func (a *nodeNavigator) Value() string {
 	}
 	switch a.cur.typ {
 	case valueNode:
+		if a.cur.n == nil {
+			// null value
+			return ""
+		}
 		return nodes.ToString(a.cur.n.Value())
 	}
 	return ""
@@ -115,6 +219,12 @@ func (a *nodeNavigator) MoveToRoot() {
 }
 
 func (a *nodeNavigator) MoveToParent() bool {
+	a.ctl.tick()
+	if a.attri >= 0 {
+		// the parent of an attribute is the element that owns it
+		a.attri = -1
+		return true
+	}
 	n := a.cur.par
 	if n == nil {
 		return false
@@ -124,26 +234,39 @@ func (a *nodeNavigator) MoveToParent() bool {
 }
 
 func (x *nodeNavigator) MoveToNextAttribute() bool {
-	if x.cur.attrs == nil && x.cur.obj != nil {
-		x.cur.loadAttributes()
-	}
-	if x.attri+1 < len(x.cur.attrs) {
+	x.ctl.tick()
+	if x.attri+1 < len(x.cur.attributes()) {
 		x.attri++
 		return true
 	}
 	return false
 }
 
+// kindAttr is a synthetic attribute that exposes the kind of the node (object, array, string, etc).
+//
+// If an object has its own value field with the same name, the field takes precedence.
+const kindAttr = "kind"
+
 func (nd *node) loadAttributes() {
 	nd.attrs = []attr{} // indicate that attributes are loaded even if node has none
 	add := func(k, v string) {
 		nd.attrs = append(nd.attrs, attr{key: k, val: v})
 	}
-	for _, k := range nd.obj.Keys() {
+	addNull := func(k string) {
+		nd.attrs = append(nd.attrs, attr{key: k, null: true})
+	}
+	if nd.obj == nil {
+		add(kindAttr, kindName(nd.kind))
+		return
+	}
+	if v, ok := nd.obj.ValueAt(kindAttr); !ok || nodes.KindOf(v).In(nodes.KindsComposite) {
+		add(kindAttr, kindName(nd.kind))
+	}
+	for _, k := range nodes.SortedKeys(nd.obj) {
 		v, _ := nd.obj.ValueAt(k)
 		switch sub := v.(type) {
 		case nil:
-			add(k, "")
+			addNull(k)
 			continue
 		case nodes.ExternalArray:
 			// project all array elements that are value to attributes
@@ -159,7 +282,7 @@ func (nd *node) loadAttributes() {
 			for i := 0; i < sz; i++ {
 				vn := sub.ValueAt(i)
 				if vn == nil {
-					add(k, "")
+					addNull(k)
 					continue
 				}
 				kind := vn.Kind()
@@ -205,10 +328,15 @@ func (nd *node) loadAttributes() {
 	}
 }
 
+// kindName returns a lowercase name of the node kind, as exposed by the kind attribute.
+func kindName(k nodes.Kind) string {
+	return strings.ToLower(k.String())
+}
+
 func (nd *node) loadChildren() {
 	// project fields
 	obj := nd.obj
-	keys := obj.Keys()
+	keys := nodes.SortedKeys(obj)
 	nd.sub = make([]*node, 0, len(keys))
 	for _, k := range keys {
 		v, ok := obj.ValueAt(k)
@@ -218,9 +346,9 @@ func (nd *node) loadChildren() {
 		var vn *node
 		switch k {
 		case uast.KeyToken:
-			vn = toNode(v, "")
+			vn = toNode(v, "", nd.opt)
 		default:
-			vn = toNode(v, k)
+			vn = toNode(v, k, nd.opt)
 		}
 		vn.par = nd
 		vn.parInd = len(nd.sub)
@@ -228,11 +356,24 @@ func (nd *node) loadChildren() {
 	}
 }
 
-func toNode(n nodes.External, field string) *node {
+// toNode projects an external node to the XML-like tree used by the navigator.
+//
+// Null values are projected as nodes without any content: a null field becomes
+// an empty field element (<field/>) and a null array element becomes an empty
+// text node. This makes null fields distinguishable from empty strings, which
+// always have a text child, so they can be found with "//field[not(node())]".
+func toNode(n nodes.External, field string, opt *Options) *node {
 	if n == nil || n.Kind() == nodes.KindNil {
-		n = nodes.String("") // TODO
+		if field != "" {
+			return &node{
+				kind: nodes.KindNil,
+				typ:  fieldNode, tag: [2]string{"", field},
+				sub: []*node{},
+			}
+		}
+		return &node{kind: nodes.KindNil, typ: valueNode}
 	}
-	nd := &node{n: n, kind: n.Kind()}
+	nd := &node{n: n, kind: n.Kind(), opt: opt}
 
 	wrap := func(nd *node) *node {
 		if field == "" {
@@ -250,11 +391,9 @@ func toNode(n nodes.External, field string) *node {
 	}
 
 	switch nd.kind {
-	case nodes.KindNil:
-		return nil // TODO
 	case nodes.KindObject:
 		if typ := uast.TypeOf(n); typ != "" {
-			if i := strings.Index(typ, ":"); i >= 0 {
+			if i := strings.Index(typ, ":"); i >= 0 && !opt.NoTypePrefix {
 				nd.tag = [2]string{typ[:i], typ[i+1:]}
 			} else {
 				nd.tag = [2]string{"", typ}
@@ -278,7 +417,7 @@ func toNode(n nodes.External, field string) *node {
 		f.sub = make([]*node, 0, sz)
 		for i := 0; i < sz; i++ {
 			v := arr.ValueAt(i)
-			s := toNode(v, "")
+			s := toNode(v, "", opt)
 			s.par = f
 			s.parInd = i
 			f.sub = append(f.sub, s)
@@ -292,47 +431,28 @@ func toNode(n nodes.External, field string) *node {
 }
 
 func (a *nodeNavigator) MoveToChild() bool {
+	a.ctl.tick()
+	if a.attri >= 0 {
+		return false
+	}
 	switch a.cur.typ {
-	case rootNode:
-		// return the same node, but without the root type
-		n := toNode(a.cur.n, "")
-		if n == nil {
-			return false
-		}
-		n.par = a.cur
-		a.cur = n
-		return true
-	case objectNode:
-		// node is an object, children are wrapped into a tag with the name = field
-		if a.cur.obj == nil {
-			return false
-		}
-		cur := a.cur
-		if cur.sub == nil {
-			cur.loadChildren()
-		}
-		if len(cur.sub) == 0 {
+	case rootNode, objectNode, fieldNode:
+		// object children are wrapped into a tag with the name = field
+		sub := a.cur.children()
+		if len(sub) == 0 || sub[0] == nil {
 			return false
 		}
-		a.cur = cur.sub[0]
-		return true
-	case fieldNode:
-		if len(a.cur.sub) == 0 {
-			return false
-		}
-		n := a.cur.sub[0]
-		if n == nil {
-			return false
-		}
-		a.cur = n
+		a.cur = sub[0]
 		return true
 	}
 	return false
 }
 
+// isSub checks if the current node is an element that has siblings. Attributes have no siblings.
 func (a *nodeNavigator) isSub() bool {
-	return a.cur.par != nil && a.cur.parInd < len(a.cur.par.sub)
+	return a.attri < 0 && a.cur.par != nil && a.cur.parInd < len(a.cur.par.sub)
 }
+
 func (a *nodeNavigator) MoveToFirst() bool {
 	if a.isSub() {
 		par := a.cur.par
@@ -344,6 +464,7 @@ func (a *nodeNavigator) MoveToFirst() bool {
 }
 
 func (a *nodeNavigator) MoveToNext() bool {
+	a.ctl.tick()
 	if a.isSub() {
 		par := a.cur.par
 		if i := a.cur.parInd + 1; i < len(par.sub) {
@@ -355,6 +476,7 @@ func (a *nodeNavigator) MoveToNext() bool {
 }
 
 func (a *nodeNavigator) MoveToPrevious() bool {
+	a.ctl.tick()
 	if a.isSub() {
 		par := a.cur.par
 		if i := a.cur.parInd - 1; i >= 0 && i < len(par.sub) {
@@ -1,10 +1,12 @@
 package xpath
 
 import (
+	"context"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
 
+	"github.com/antchfx/xpath"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bblfsh/sdk/v3/uast"
@@ -22,6 +24,81 @@ func mustNode(o interface{}) nodes.Node {
 	return n
 }
 
+func TestNoTypePrefix(t *testing.T) {
+	var root = nodes.Array{
+		mustNode(uast.Identifier{Name: "Foo"}),
+		nodes.Object{
+			uast.KeyType:  nodes.String("go:Ident"),
+			uast.KeyToken: nodes.String("A"),
+		},
+	}
+
+	idx := New()
+
+	it, err := idx.Execute(root, "//go:Ident")
+	require.NoError(t, err)
+	expect(t, it, root[1])
+
+	it, err = idx.Execute(root, "//*[local-name()='Ident']")
+	require.NoError(t, err)
+	expect(t, it, root[1])
+
+	it, err = idx.Execute(root, "//*[local-name()='go:Ident']")
+	require.NoError(t, err)
+	expect(t, it)
+
+	idx = NewWithOptions(Options{NoTypePrefix: true})
+
+	it, err = idx.Execute(root, "//go:Ident")
+	require.NoError(t, err)
+	expect(t, it)
+
+	it, err = idx.Execute(root, "//Ident")
+	require.NoError(t, err)
+	expect(t, it)
+
+	it, err = idx.Execute(root, "//*[local-name()='go:Ident']")
+	require.NoError(t, err)
+	expect(t, it, root[1])
+
+	it, err = idx.Execute(root, "//*[name()='uast:Identifier']/Name")
+	require.NoError(t, err)
+	expect(t, it, nodes.String("Foo"))
+}
+
+func TestSharedNavigator(t *testing.T) {
+	root := readUAST(t, filepath.Join(dataDir, "large.go.sem.uast"))
+	exp, err := xpath.Compile("//uast:Alias[/File]//uast:Identifier")
+	require.NoError(t, err)
+
+	// navigator copies share the projected tree; run them concurrently to catch races
+	nav := newNavigator(root, nil)
+	const workers = 4
+	counts := make(chan int, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			it := exp.Select(nav.Copy())
+			n := 0
+			for it.MoveNext() {
+				n++
+			}
+			counts <- n
+		}()
+	}
+	first := <-counts
+	require.NotZero(t, first)
+	for i := 1; i < workers; i++ {
+		require.Equal(t, first, <-counts)
+	}
+
+	// the projection must be reused by subsequent queries on the same navigator
+	sub := nav.root.children()
+	require.Len(t, sub, 1)
+	it := exp.Select(nav.Copy())
+	require.True(t, it.MoveNext())
+	require.True(t, sub[0] == nav.root.children()[0])
+}
+
 func TestFilter(t *testing.T) {
 	var root = nodes.Array{
 		mustNode(uast.Identifier{
@@ -90,6 +167,24 @@ func TestFilter(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestCount(t *testing.T) {
+	root := readUAST(t, filepath.Join(dataDir, "large.go.sem.uast"))
+
+	n, err := Count(root, "//uast:Identifier")
+	require.NoError(t, err)
+	require.Equal(t, 2292, n)
+
+	n, err = Count(root, "//uast:Missing")
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	_, err = Count(root, "//uast:Identifier[")
+	require.Error(t, err)
+
+	_, err = Count(root, "count(//uast:Identifier)")
+	require.Error(t, err)
+}
+
 func TestFilterObject(t *testing.T) {
 	b := nodes.Object{
 		uast.KeyType: nodes.String("B"),
@@ -235,6 +330,305 @@ func TestFilterObject(t *testing.T) {
 	}
 }
 
+func TestFilterNull(t *testing.T) {
+	empty := nodes.String("")
+	arr := nodes.Array{nil, nodes.String("a")}
+	var root = nodes.Object{
+		uast.KeyType: nodes.String("A"),
+		"null":       nil,
+		"empty":      empty,
+		"arr":        arr,
+	}
+
+	idx := New()
+
+	queries := []struct {
+		name string
+		qu   string
+		exp  []nodes.Node
+	}{
+		{
+			name: "null field", qu: "/A/null",
+			exp: []nodes.Node{nil},
+		},
+		{
+			name: "null field no children", qu: "/A/*[not(node())]",
+			exp: []nodes.Node{nil},
+		},
+		{
+			name: "empty string has text", qu: "/A/empty[text() = '']",
+			exp: []nodes.Node{empty},
+		},
+		{
+			name: "null has no text", qu: "/A/null[text()]",
+			exp: nil,
+		},
+		{
+			name: "null attr", qu: "//A[@null = '']",
+			exp: []nodes.Node{root},
+		},
+		{
+			name: "null array elem", qu: "count(/A/arr/text())",
+			exp: []nodes.Node{nodes.Int(2)},
+		},
+		{
+			name: "null attr value", qu: "/A/@null",
+			exp: []nodes.Node{nil},
+		},
+		{
+			name: "null array elem attr", qu: "/A/@arr",
+			exp: []nodes.Node{nil, nodes.String("a")},
+		},
+		{
+			name: "empty string attr", qu: "/A/@empty",
+			exp: []nodes.Node{empty},
+		},
+	}
+
+	for _, c := range queries {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			it, err := idx.Execute(root, c.qu)
+			require.NoError(t, err)
+			var out []nodes.Node
+			for it.Next() {
+				n, _ := it.Node().(nodes.Node)
+				out = append(out, n)
+			}
+			require.Equal(t, c.exp, out)
+		})
+	}
+}
+
+func TestFilterKind(t *testing.T) {
+	obj := nodes.Object{
+		uast.KeyType: nodes.String("B"),
+	}
+	arr := nodes.Array{nodes.String("a")}
+	str := nodes.String("v")
+	num := nodes.Int(1)
+	typed := nodes.Object{
+		uast.KeyType: nodes.String("C"),
+		"kind":       nodes.String("custom"),
+	}
+	var root = nodes.Object{
+		uast.KeyType: nodes.String("A"),
+		"arr":        arr,
+		"null":       nil,
+		"num":        num,
+		"obj":        obj,
+		"str":        str,
+		"typed":      typed,
+	}
+
+	idx := New()
+
+	queries := []struct {
+		name string
+		qu   string
+		exp  []nodes.Node
+	}{
+		{
+			name: "object field", qu: "/A/*[@kind='object']",
+			exp: []nodes.Node{obj, typed},
+		},
+		{
+			name: "array", qu: "//*[@kind='array']",
+			exp: []nodes.Node{arr},
+		},
+		{
+			name: "value", qu: "/A/str[@kind='string']",
+			exp: []nodes.Node{str},
+		},
+		{
+			name: "int value", qu: "/A/*[@kind='int']",
+			exp: []nodes.Node{num},
+		},
+		{
+			name: "nil", qu: "/A/*[@kind='nil']",
+			exp: []nodes.Node{nil},
+		},
+		{
+			name: "own field", qu: "//C[@kind='custom']",
+			exp: []nodes.Node{typed},
+		},
+	}
+
+	for _, c := range queries {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			it, err := idx.Execute(root, c.qu)
+			require.NoError(t, err)
+			var out []nodes.Node
+			for it.Next() {
+				n, _ := it.Node().(nodes.Node)
+				out = append(out, n)
+			}
+			require.Equal(t, c.exp, out)
+		})
+	}
+}
+
+func TestAxes(t *testing.T) {
+	ident := func(name string) nodes.Object {
+		return nodes.Object{uast.KeyType: nodes.String("Ident"), "Name": nodes.String(name)}
+	}
+	x, y, z := ident("x"), ident("y"), ident("z")
+	fa := nodes.Object{
+		uast.KeyType: nodes.String("Func"),
+		"Name":       nodes.String("a"),
+		"Body":       nodes.Array{x, y, z},
+	}
+	fb := nodes.Object{
+		uast.KeyType: nodes.String("Func"),
+		"Name":       nodes.String("b"),
+		"Result":     ident("r"),
+	}
+	root := nodes.Object{
+		uast.KeyType: nodes.String("File"),
+		"Decls":      nodes.Array{fa, fb},
+	}
+
+	idx := New()
+	queries := []struct {
+		name string
+		qu   string
+		exp  []nodes.Node
+	}{
+		{
+			name: "ancestor", qu: "//Ident[@Name='y']/ancestor::Func",
+			exp: []nodes.Node{fa},
+		},
+		{
+			name: "ancestor from field", qu: "//Ident[@Name='r']/ancestor::*[@Name]",
+			exp: []nodes.Node{fb},
+		},
+		{
+			name: "ancestor from attribute", qu: "//@Name[.='y']/ancestor::Func",
+			exp: []nodes.Node{fa},
+		},
+		{
+			name: "attribute parent", qu: "//@Name[.='y']/..",
+			exp: []nodes.Node{y},
+		},
+		{
+			name: "descendant", qu: "/File/descendant::Ident",
+			exp: []nodes.Node{x, y, z, fb["Result"]},
+		},
+		{
+			name: "descendant from field", qu: "//Func[@Name='a']/Body/descendant::Ident",
+			exp: []nodes.Node{x, y, z},
+		},
+		{
+			name: "following sibling", qu: "//Ident[@Name='x']/following-sibling::*",
+			exp: []nodes.Node{y, z},
+		},
+		{
+			// reverse axes are returned in the axis order
+			name: "preceding sibling", qu: "//Ident[@Name='z']/preceding-sibling::*",
+			exp: []nodes.Node{y, x},
+		},
+		{
+			name: "following sibling object", qu: "//Func[@Name='a']/following-sibling::Func",
+			exp: []nodes.Node{fb},
+		},
+		{
+			name: "no sibling across fields", qu: "//Ident[@Name='r']/following-sibling::*",
+			exp: nil,
+		},
+		{
+			name: "sibling fields", qu: "//Func[@Name='b']/Name/following-sibling::Result/Ident",
+			exp: []nodes.Node{fb["Result"]},
+		},
+		{
+			name: "following", qu: "//Ident[@Name='z']/following::Ident",
+			exp: []nodes.Node{fb["Result"]},
+		},
+		{
+			name: "preceding", qu: "//Ident[@Name='r']/preceding::Ident",
+			exp: []nodes.Node{x, y, z},
+		},
+		{
+			name: "position", qu: "//Func[@Name='a']/Body/Ident[2]",
+			exp: []nodes.Node{y},
+		},
+	}
+	for _, c := range queries {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			it, err := idx.Execute(root, c.qu)
+			require.NoError(t, err)
+			expect(t, it, c.exp...)
+		})
+	}
+}
+
+func TestFilterAttributes(t *testing.T) {
+	ident := nodes.Object{
+		uast.KeyType:  nodes.String("Ident"),
+		uast.KeyToken: nodes.String("A"),
+		uast.KeyRoles: uast.RoleList(role.Identifier, role.Name),
+	}
+	lit := nodes.Object{
+		uast.KeyType:  nodes.String("Lit"),
+		uast.KeyToken: nodes.String("1"),
+	}
+	var root = nodes.Array{ident, lit}
+
+	idx := New()
+
+	type match struct {
+		typ  query.MatchType
+		node nodes.Node
+	}
+	queries := []struct {
+		name string
+		qu   string
+		exp  []match
+	}{
+		{
+			name: "element", qu: "//Ident",
+			exp: []match{{query.MatchNode, ident}},
+		},
+		{
+			name: "roles", qu: "//Ident/@role",
+			exp: []match{
+				{query.MatchAttribute, nodes.String(role.Identifier.String())},
+				{query.MatchAttribute, nodes.String(role.Name.String())},
+			},
+		},
+		{
+			name: "tokens", qu: "//*/@token",
+			exp: []match{
+				{query.MatchAttribute, nodes.String("A")},
+				{query.MatchAttribute, nodes.String("1")},
+			},
+		},
+		{
+			name: "text", qu: "//Lit/text()",
+			exp: []match{{query.MatchText, nodes.String("1")}},
+		},
+		{
+			name: "value", qu: "count(//*/@token)",
+			exp: []match{{query.MatchValue, nodes.Int(2)}},
+		},
+	}
+
+	for _, c := range queries {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			it, err := idx.Execute(root, c.qu)
+			require.NoError(t, err)
+			var out []match
+			for it.Next() {
+				n, _ := it.Node().(nodes.Node)
+				out = append(out, match{typ: query.MatchTypeOf(it), node: n})
+			}
+			require.Equal(t, c.exp, out)
+		})
+	}
+}
+
 func expect(t testing.TB, it query.Iterator, exp ...nodes.Node) {
 	var out []nodes.Node
 	for it.Next() {
@@ -301,3 +695,272 @@ func BenchmarkXPathPrepare(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkXPathPredicate(b *testing.B) {
+	root := readUAST(b, filepath.Join(dataDir, "large.go.sem.uast"))
+
+	idx := New()
+	// absolute paths in the predicate force the navigator to walk the tree from the root for each candidate
+	q, err := idx.Prepare("//uast:FunctionGroup[count(/*//uast:Alias) > 0]//uast:Identifier")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		it, err := q.Execute(root)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n := 0
+		for it.Next() {
+			_ = it.Node()
+			n++
+		}
+		if n == 0 {
+			b.Fatal("no nodes")
+		}
+	}
+}
+
+func TestGroupResults(t *testing.T) {
+	ident := func(name string, roles ...role.Role) nodes.Object {
+		n := mustNode(uast.Identifier{Name: name}).(nodes.Object)
+		if len(roles) != 0 {
+			n[uast.KeyRoles] = uast.RoleList(roles...)
+		}
+		return n
+	}
+	a := ident("a", role.Name, role.Function)
+	b := ident("b", role.Name)
+	c := ident("c")
+	tok := nodes.Object{
+		uast.KeyType:  nodes.String("go:Ident"),
+		uast.KeyToken: nodes.String("d"),
+		uast.KeyRoles: uast.RoleList(role.Function, role.Function),
+	}
+	root := nodes.Array{a, tok, b, nodes.Object{"x": c}}
+
+	it, err := New().Execute(root, "//*[@Name or @token]")
+	require.NoError(t, err)
+	var list []nodes.External
+	for it.Next() {
+		list = append(list, it.Node())
+	}
+	require.Len(t, list, 4)
+
+	require.Equal(t, map[string][]nodes.External{
+		"uast:Identifier": {a, b, c},
+		"go:Ident":        {tok},
+	}, query.GroupByType(query.NewSliceIterator(list)))
+
+	it, err = New().Execute(root, "//*[@Name or @token]")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]nodes.External{
+		role.Name.String():     {a, b},
+		role.Function.String(): {a, tok},
+		"":                     {c},
+	}, query.GroupByRole(it))
+}
+
+func TestMatchPath(t *testing.T) {
+	root := nodes.Array{
+		nodes.Object{
+			uast.KeyType:  nodes.String("Func"),
+			uast.KeyToken: nodes.String("f"),
+			"Name":        nodes.Object{uast.KeyType: nodes.String("Ident"), "Name": nodes.String("f")},
+			"Params": nodes.Array{
+				nodes.Object{uast.KeyType: nodes.String("Ident"), "Name": nodes.String("a")},
+				nodes.Array{nodes.Object{uast.KeyType: nodes.String("Ident"), "Name": nodes.String("b")}},
+			},
+		},
+	}
+	idx := New()
+	for _, c := range []struct {
+		query string
+		paths []string
+	}{
+		{"/", []string{""}},
+		{"//Ident", []string{"[0].Name", "[0].Params[0]", "[0].Params[1][0]"}},
+		{"//Params", []string{"[0].Params"}},
+		{"//Func/text()", []string{"[0].@token"}},
+		{"//Ident/@Name", []string{"[0].Name", "[0].Params[0]", "[0].Params[1][0]"}},
+	} {
+		it, err := idx.Execute(root, c.query)
+		require.NoError(t, err)
+		var paths []string
+		for it.Next() {
+			p, ok := query.PathOf(it)
+			require.True(t, ok)
+			paths = append(paths, p.String())
+			if query.MatchTypeOf(it) == query.MatchAttribute {
+				continue
+			}
+			n, ok := p.Resolve(root)
+			require.True(t, ok, "%q", p)
+			require.True(t, nodes.Equal(it.Node(), n), "%q", p)
+		}
+		require.Equal(t, c.paths, paths, "%q", c.query)
+	}
+
+	// value matches have no path
+	it, err := idx.Execute(root, "count(//Ident)")
+	require.NoError(t, err)
+	_, ok := query.PathOf(it)
+	require.False(t, ok)
+}
+
+func TestMatchPathLarge(t *testing.T) {
+	root := readUAST(t, filepath.Join(dataDir, "large.go.sem.uast"))
+
+	it, err := New().Execute(root, "//*")
+	require.NoError(t, err)
+	cnt := 0
+	for it.Next() {
+		p, ok := query.PathOf(it)
+		require.True(t, ok)
+		n, ok := p.Resolve(root)
+		require.True(t, ok, "%q", p)
+		exp := it.Node()
+		if nodes.KindOf(exp).In(nodes.KindsComposite) {
+			require.True(t, nodes.Same(exp, n), "%q", p)
+		} else {
+			require.True(t, nodes.Equal(exp, n), "%q", p)
+		}
+		cnt++
+	}
+	require.True(t, cnt > 0)
+}
+
+// cancelAfterCtx is a context that is cancelled after a given number of checks.
+type cancelAfterCtx struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterCtx) Err() error {
+	if c.checks <= 0 {
+		return context.Canceled
+	}
+	c.checks--
+	return nil
+}
+
+func TestExecuteCtx(t *testing.T) {
+	root := readUAST(t, filepath.Join(dataDir, "large.go.sem.uast"))
+
+	q, err := New().Prepare("//uast:Identifier")
+	require.NoError(t, err)
+
+	_, ok := q.(query.ContextQuery)
+	require.True(t, ok)
+
+	// not cancelled
+	it, err := query.ExecuteCtx(context.Background(), q, root)
+	require.NoError(t, err)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	require.NoError(t, query.IteratorErr(it))
+	require.Equal(t, 2292, n)
+
+	// already cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = query.ExecuteCtx(ctx, q, root)
+	require.Equal(t, context.Canceled, err)
+
+	// queries that cannot be cancelled only check the context before the execution
+	_, err = query.ExecuteCtx(ctx, plainQuery{q}, root)
+	require.Equal(t, context.Canceled, err)
+	it, err = query.ExecuteCtx(context.Background(), plainQuery{q}, root)
+	require.NoError(t, err)
+	require.Equal(t, 2292, query.Count(it))
+
+	// cancelled during the iteration
+	it, err = query.ExecuteCtx(&cancelAfterCtx{Context: context.Background(), checks: 2}, q, root)
+	require.NoError(t, err)
+	n = 0
+	for it.Next() {
+		n++
+	}
+	require.Equal(t, context.Canceled, query.IteratorErr(it))
+	require.True(t, n < 2292, "%d", n)
+	require.False(t, it.Next())
+
+	// cancelled during the evaluation of a value
+	q, err = New().Prepare("count(//uast:Identifier)")
+	require.NoError(t, err)
+	_, err = query.ExecuteCtx(&cancelAfterCtx{Context: context.Background(), checks: 2}, q, root)
+	require.Equal(t, context.Canceled, err)
+}
+
+// plainQuery hides optional interfaces of the query.
+type plainQuery struct {
+	q query.Query
+}
+
+func (q plainQuery) Execute(root nodes.External) (query.Iterator, error) {
+	return q.q.Execute(root)
+}
+
+func TestExecuteParams(t *testing.T) {
+	const tricky = `it's "quoted"`
+	tok := func(s string) nodes.Object {
+		return nodes.Object{uast.KeyType: nodes.String("Ident"), uast.KeyToken: nodes.String(s)}
+	}
+	root := nodes.Array{
+		tok(tricky),
+		tok("it's"),
+		tok(`"quoted"`),
+		tok("' or '1'='1"),
+		tok("$name"),
+		nodes.Object{uast.KeyType: nodes.String("Num"), "Value": nodes.Int(-3)},
+	}
+
+	q, err := New().Prepare("//Ident[@token=$name]")
+	require.NoError(t, err)
+	pq, ok := q.(query.ParamQuery)
+	require.True(t, ok)
+
+	for _, name := range []string{tricky, "it's", `"quoted"`, "' or '1'='1", "$name"} {
+		it, err := pq.ExecuteParams(root, map[string]interface{}{"name": name})
+		require.NoError(t, err)
+		got := query.AllNodes(it)
+		require.Equal(t, []nodes.External{tok(name)}, got, "%q", name)
+	}
+
+	// injection attempts are matched literally
+	it, err := pq.ExecuteParams(root, map[string]interface{}{"name": "x' or '1'='1"})
+	require.NoError(t, err)
+	require.Empty(t, query.AllNodes(it))
+
+	// parameters must be bound
+	_, err = q.Execute(root)
+	require.Error(t, err)
+	_, err = pq.ExecuteParams(root, nil)
+	require.Error(t, err)
+	_, err = pq.ExecuteParams(root, map[string]interface{}{"name": []string{"a"}})
+	require.Error(t, err)
+
+	// variables in string literals are not parameters
+	it, err = New().Execute(root, "//Ident[@token='$name']")
+	require.NoError(t, err)
+	require.Len(t, query.AllNodes(it), 1)
+
+	// numbers and booleans
+	q, err = New().Prepare("//Num[@Value=$v and $ok]")
+	require.NoError(t, err)
+	it, err = q.(query.ParamQuery).ExecuteParams(root, map[string]interface{}{"v": -3, "ok": true})
+	require.NoError(t, err)
+	require.Len(t, query.AllNodes(it), 1)
+	it, err = q.(query.ParamQuery).ExecuteParams(root, map[string]interface{}{"v": nodes.Int(-3), "ok": false})
+	require.NoError(t, err)
+	require.Empty(t, query.AllNodes(it))
+
+	// syntax errors are reported by Prepare
+	_, err = New().Prepare("//Ident[@token=$name")
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package xpath
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/antchfx/xpath"
@@ -9,17 +10,63 @@ import (
 	"github.com/bblfsh/sdk/v3/uast/query"
 )
 
+// Options configures the projection of the UAST to the XML-like tree used by XPath queries.
+type Options struct {
+	// NoTypePrefix disables splitting of node types into a prefix and a local name.
+	//
+	// By default, a type like "go:Ident" is exposed as an element with "go" prefix and "Ident" local name,
+	// thus it can be matched with "//go:Ident" or "//*[local-name()='Ident']". If this option is set,
+	// the full type is used as a local name without a prefix. Since XPath parses "go:Ident" in the query
+	// as a prefixed name, such elements can only be matched by name functions, for example
+	// "//*[local-name()='go:Ident']", and prefix-based queries will no longer match.
+	NoTypePrefix bool
+}
+
+// New creates a new XPath query engine with default options.
 func New() query.Interface {
 	return &index{}
 }
 
-type index struct{}
+// NewWithOptions is like New, but allows to specify projection options.
+func NewWithOptions(opt Options) query.Interface {
+	return &index{opt: opt}
+}
 
-func (t *index) newNavigator(n nodes.External) xpath.NodeNavigator {
-	return newNavigator(n)
+// Count compiles the XPath expression and returns the number of nodes it matches in the tree.
+//
+// Matches are counted without retaining them. An error is returned if the expression is invalid or if it
+// evaluates to a single computed value (e.g. "count(//Ident)") instead of selecting nodes.
+func Count(root nodes.External, expr string) (int, error) {
+	it, err := New().Execute(root, expr)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for it.Next() {
+		if n == 0 && query.MatchTypeOf(it) == query.MatchValue {
+			return 0, fmt.Errorf("expression evaluates to a value, not a node set: %q", expr)
+		}
+		n++
+	}
+	return n, nil
+}
+
+type index struct {
+	opt Options
+}
+
+func (t *index) newNavigator(n nodes.External) *nodeNavigator {
+	return newNavigator(n, &t.opt)
 }
 
 func (t *index) Prepare(query string) (query.Query, error) {
+	if tmpl := parseTemplate(query); tmpl != nil {
+		// parameters are bound on execution; check the syntax only
+		if err := tmpl.compileEmpty(); err != nil {
+			return nil, err
+		}
+		return &xQuery{idx: t, tmpl: tmpl}, nil
+	}
 	exp, err := xpath.Compile(query)
 	if err != nil {
 		return nil, err
@@ -35,22 +82,46 @@ func (t *index) Execute(root nodes.External, query string) (query.Iterator, erro
 	return q.Execute(root)
 }
 
+var _ query.ContextQuery = (*xQuery)(nil)
+
 type xQuery struct {
 	idx *index
 	exp *xpath.Expr
+	// tmpl is set instead of exp if the query has parameters
+	tmpl *template
+}
+
+func (q *xQuery) Execute(root nodes.External) (query.Iterator, error) {
+	return q.execute(root, nil)
+}
+
+// ExecuteCtx implements query.ContextQuery.
+func (q *xQuery) ExecuteCtx(ctx context.Context, root nodes.External) (query.Iterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return q.execute(root, &execControl{ctx: ctx})
 }
 
-func (q *xQuery) Execute(root nodes.External) (_ query.Iterator, gerr error) {
+func (q *xQuery) execute(root nodes.External, ctl *execControl) (_ query.Iterator, gerr error) {
+	if q.tmpl != nil {
+		return nil, q.tmpl.unboundError()
+	}
 	// This workaround should be temporary. xpath library is not
 	// managing panics correctly (it should output a nice error instead)
 	// TODO(ncordon): fix the xpath library instead of recovering from the panic
 	defer func() {
 		if r := recover(); r != nil {
+			if c, ok := r.(cancelled); ok {
+				gerr = c.err
+				return
+			}
 			gerr = fmt.Errorf("Error executing the xPath query, maybe wrong syntax? \nRecovered from %v", r)
 		}
 	}()
 
 	nav := q.idx.newNavigator(root)
+	nav.ctl = ctl
 	val := q.exp.Evaluate(nav)
 
 	if it, ok := val.(*xpath.NodeIterator); ok {
@@ -80,6 +151,13 @@ func (q *xQuery) Execute(root nodes.External) (_ query.Iterator, gerr error) {
 	return &valIterator{val: v}, nil
 }
 
+var (
+	_ query.MatchIterator = (*valIterator)(nil)
+	_ query.MatchIterator = (*iterator)(nil)
+	_ query.PathIterator  = (*iterator)(nil)
+	_ query.ErrorIterator = (*iterator)(nil)
+)
+
 type valIterator struct {
 	state int
 	val   nodes.Value
@@ -103,15 +181,16 @@ func (it *valIterator) Node() nodes.External {
 	return nil
 }
 
-type iterator struct {
-	it *xpath.NodeIterator
+func (it *valIterator) MatchType() query.MatchType {
+	return query.MatchValue
 }
 
-func (it *iterator) Next() bool {
-	return it.it.MoveNext()
+type iterator struct {
+	it  *xpath.NodeIterator
+	err error
 }
 
-func (it *iterator) Node() nodes.External {
+func (it *iterator) current() *nodeNavigator {
 	c := it.it.Current()
 	if c == nil {
 		return nil
@@ -120,5 +199,65 @@ func (it *iterator) Node() nodes.External {
 	if nav.cur == nil {
 		return nil
 	}
+	return nav
+}
+
+// MatchType implements query.MatchIterator.
+func (it *iterator) MatchType() query.MatchType {
+	nav := it.current()
+	switch {
+	case nav == nil:
+		return query.MatchNode
+	case nav.attri >= 0:
+		return query.MatchAttribute
+	case nav.cur.typ == valueNode:
+		return query.MatchText
+	}
+	return query.MatchNode
+}
+
+func (it *iterator) Next() (next bool) {
+	if it.err != nil {
+		return false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			c, ok := r.(cancelled)
+			if !ok {
+				panic(r)
+			}
+			it.err, next = c.err, false
+		}
+	}()
+	return it.it.MoveNext()
+}
+
+// Err implements query.ErrorIterator.
+func (it *iterator) Err() error {
+	return it.err
+}
+
+// Node returns the current node. For attribute matches, it returns the attribute value as nodes.String,
+// or nil if the attribute was projected from a null value.
+func (it *iterator) Node() nodes.External {
+	nav := it.current()
+	if nav == nil {
+		return nil
+	}
+	if nav.attri >= 0 {
+		if nav.cur.attrs[nav.attri].null {
+			return nil
+		}
+		return nodes.String(nav.Value())
+	}
 	return nav.cur.n
 }
+
+// Path implements query.PathIterator.
+func (it *iterator) Path() nodes.Path {
+	nav := it.current()
+	if nav == nil {
+		return nil
+	}
+	return nav.cur.path()
+}
@@ -0,0 +1,329 @@
+package uast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// PositionCheck is a set of validation rules applied by RepairPositions.
+type PositionCheck int
+
+const (
+	// CheckNegative reports positions with negative offset, line or column values.
+	CheckNegative = PositionCheck(1 << iota)
+	// CheckOrder reports end positions that precede the start position of the same node.
+	CheckOrder
+	// CheckBounds reports offsets that point past the end of the source.
+	// Only applied if RepairOptions.Source is set.
+	CheckBounds
+	// CheckLines reports positions where the line number disagrees with the one computed from the offset.
+	// Only applied if RepairOptions.Source is set.
+	CheckLines
+
+	// DefaultPositionChecks is a set of checks used if none are specified.
+	DefaultPositionChecks = CheckNegative | CheckOrder | CheckBounds | CheckLines
+)
+
+// String returns a name of the check.
+func (c PositionCheck) String() string {
+	var names []string
+	for _, v := range []struct {
+		c    PositionCheck
+		name string
+	}{
+		{CheckNegative, "negative"},
+		{CheckOrder, "order"},
+		{CheckBounds, "bounds"},
+		{CheckLines, "lines"},
+	} {
+		if c&v.c != 0 {
+			names = append(names, v.name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("PositionCheck(%d)", int(c))
+	}
+	return strings.Join(names, "|")
+}
+
+// RepairStrategy defines how RepairPositions handles invalid positions.
+type RepairStrategy int
+
+const (
+	// RepairClamp adjusts invalid position values to the closest valid ones.
+	RepairClamp = RepairStrategy(iota)
+	// RepairDrop removes invalid positions from the node.
+	RepairDrop
+)
+
+// RepairOptions controls the behavior of RepairPositions.
+type RepairOptions struct {
+	// Checks is a set of rules to apply. If zero, DefaultPositionChecks is used.
+	Checks PositionCheck
+	// Strategy is used to repair invalid positions.
+	Strategy RepairStrategy
+	// Source is an optional content of the source file. Bounds and lines checks are only
+	// applied if it is set.
+	Source string
+	// LineTolerance is a maximal allowed difference between the line of the position and
+	// the line computed from its offset.
+	LineTolerance int
+}
+
+// PositionRepair describes a single change made by RepairPositions.
+type PositionRepair struct {
+	Path    nodes.Path    // path to the node that owns the position
+	Key     string        // position key, for example KeyStart
+	Check   PositionCheck // a rule that the position violated
+	Dropped bool          // the position was removed instead of being clamped
+	Reason  string        // human-readable description of the problem
+}
+
+// String formats the repair for logging.
+func (r PositionRepair) String() string {
+	action := "clamped"
+	if r.Dropped {
+		action = "dropped"
+	}
+	return fmt.Sprintf("%s.%s.%s: %s (%s)", r.Path, KeyPos, r.Key, r.Reason, action)
+}
+
+// RepairPositions validates all positional information in the tree and repairs invalid positions
+// according to the options. It returns a new tree and a list of repairs that were made.
+//
+// The input tree is not modified; unchanged subtrees are shared with the returned tree.
+func RepairPositions(root nodes.Node, opts RepairOptions) (nodes.Node, []PositionRepair) {
+	if opts.Checks == 0 {
+		opts.Checks = DefaultPositionChecks
+	}
+	r := &posRepairer{opts: opts}
+	if opts.Source != "" {
+		r.lines = []int{0}
+		for i := 0; i < len(opts.Source); i++ {
+			if opts.Source[i] == '\n' {
+				r.lines = append(r.lines, i+1)
+			}
+		}
+	}
+	out, _ := r.node(root, nil)
+	return out, r.repairs
+}
+
+type posRepairer struct {
+	opts    RepairOptions
+	lines   []int // offsets of line starts; nil if there is no source
+	repairs []PositionRepair
+}
+
+// node repairs positions in a subtree. It returns false if the subtree was not changed.
+func (r *posRepairer) node(n nodes.Node, path nodes.Path) (nodes.Node, bool) {
+	switch n := n.(type) {
+	case nodes.Object:
+		keys := n.Keys()
+		var out nodes.Object
+		for _, k := range keys {
+			var (
+				v       nodes.Node
+				changed bool
+			)
+			if k == KeyPos {
+				v, changed = r.positions(n[k], path)
+			} else {
+				v, changed = r.node(n[k], append(path, nodes.FieldElem(k)))
+			}
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = n.CloneObject()
+			}
+			out[k] = v
+		}
+		if out == nil {
+			return n, false
+		}
+		return out, true
+	case nodes.Array:
+		var out nodes.Array
+		for i, v := range n {
+			v, changed := r.node(v, append(path, nodes.IndexElem(i)))
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = n.CloneList()
+			}
+			out[i] = v
+		}
+		if out == nil {
+			return n, false
+		}
+		return out, true
+	}
+	return n, false
+}
+
+func (r *posRepairer) report(path nodes.Path, key string, check PositionCheck, reason string, args ...interface{}) {
+	var p nodes.Path
+	if len(path) != 0 {
+		p = append(nodes.Path{}, path...)
+	}
+	r.repairs = append(r.repairs, PositionRepair{
+		Path:    p,
+		Key:     key,
+		Check:   check,
+		Dropped: r.opts.Strategy == RepairDrop,
+		Reason:  fmt.Sprintf(reason, args...),
+	})
+}
+
+// positions repairs a single positions object of the node.
+func (r *posRepairer) positions(n nodes.Node, path nodes.Path) (nodes.Node, bool) {
+	obj, ok := n.(nodes.Object)
+	if !ok || len(obj) == 0 {
+		return n, false
+	}
+	var out nodes.Object
+	set := func(k string, v nodes.Node) {
+		if out == nil {
+			out = obj.CloneObject()
+		}
+		if v == nil {
+			delete(out, k)
+		} else {
+			out[k] = v
+		}
+	}
+	for _, k := range obj.Keys() {
+		p, ok := obj[k].(nodes.Object)
+		if !ok || TypeOf(p) != TypePosition {
+			continue
+		}
+		p2, changed := r.position(p, path, k)
+		if !changed {
+			continue
+		}
+		if p2 == nil {
+			set(k, nil)
+		} else {
+			set(k, p2)
+		}
+	}
+	if r.opts.Checks&CheckOrder != 0 {
+		cur := obj
+		if out != nil {
+			cur = out
+		}
+		start, _ := cur[KeyStart].(nodes.Object)
+		end, _ := cur[KeyEnd].(nodes.Object)
+		if start != nil && end != nil && posLess(end, start) {
+			r.report(path, KeyEnd, CheckOrder, "end position precedes the start")
+			if r.opts.Strategy == RepairDrop {
+				set(KeyEnd, nil)
+			} else {
+				set(KeyEnd, start.CloneObject())
+			}
+		}
+	}
+	if out == nil {
+		return n, false
+	}
+	return out, true
+}
+
+// position validates and repairs a single position. It returns a nil object if the position should be dropped.
+func (r *posRepairer) position(p nodes.Object, path nodes.Path, key string) (nodes.Object, bool) {
+	drop := r.opts.Strategy == RepairDrop
+	var out nodes.Object
+	set := func(k string, v int64) {
+		if out == nil {
+			out = p.CloneObject()
+		}
+		out[k] = nodes.Uint(v)
+	}
+	if r.opts.Checks&CheckNegative != 0 {
+		for _, k := range []string{KeyPosOff, KeyPosLine, KeyPosCol} {
+			v, ok := posField(p, k)
+			if !ok || v >= 0 {
+				continue
+			}
+			r.report(path, key, CheckNegative, "negative %s: %d", k, v)
+			if drop {
+				return nil, true
+			}
+			set(k, 0)
+		}
+	}
+	if r.lines == nil {
+		return out, out != nil
+	}
+	cur := p
+	if out != nil {
+		cur = out
+	}
+	off, hasOff := posField(cur, KeyPosOff)
+	if !hasOff || off < 0 {
+		return out, out != nil
+	}
+	if r.opts.Checks&CheckBounds != 0 && off > int64(len(r.opts.Source)) {
+		r.report(path, key, CheckBounds, "offset %d is out of source bounds (%d)", off, len(r.opts.Source))
+		if drop {
+			return nil, true
+		}
+		off = int64(len(r.opts.Source))
+		set(KeyPosOff, off)
+	}
+	if r.opts.Checks&CheckLines != 0 {
+		line, ok := posField(cur, KeyPosLine)
+		if ok && line > 0 {
+			i := sort.SearchInts(r.lines, int(off)+1) - 1
+			exp := int64(i + 1)
+			if d := line - exp; d > int64(r.opts.LineTolerance) || -d > int64(r.opts.LineTolerance) {
+				r.report(path, key, CheckLines, "line %d disagrees with offset %d (line %d)", line, off, exp)
+				if drop {
+					return nil, true
+				}
+				set(KeyPosLine, exp)
+				set(KeyPosCol, off-int64(r.lines[i])+1)
+			}
+		}
+	}
+	return out, out != nil
+}
+
+// posField returns an integer field of the position object.
+func posField(p nodes.Object, key string) (int64, bool) {
+	switch v := p[key].(type) {
+	case nodes.Int:
+		return int64(v), true
+	case nodes.Uint:
+		return int64(v), true
+	case nodes.Float:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// posLess reports whether the position p1 is strictly less than p2. It compares offsets if both positions
+// have them, and falls back to line-column pairs otherwise.
+func posLess(p1, p2 nodes.Object) bool {
+	o1, ok1 := posField(p1, KeyPosOff)
+	o2, ok2 := posField(p2, KeyPosOff)
+	if ok1 && ok2 && (o1 != 0 || o2 != 0) {
+		return o1 < o2
+	}
+	l1, _ := posField(p1, KeyPosLine)
+	l2, _ := posField(p2, KeyPosLine)
+	if l1 == 0 || l2 == 0 {
+		return false
+	}
+	if l1 != l2 {
+		return l1 < l2
+	}
+	c1, _ := posField(p1, KeyPosCol)
+	c2, _ := posField(p2, KeyPosCol)
+	return c1 < c2
+}
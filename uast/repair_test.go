@@ -0,0 +1,177 @@
+package uast
+
+import (
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/stretchr/testify/require"
+)
+
+func rawPos(off, line, col int64) nodes.Object {
+	return nodes.Object{
+		KeyType:    nodes.String(TypePosition),
+		KeyPosOff:  nodes.Int(off),
+		KeyPosLine: nodes.Int(line),
+		KeyPosCol:  nodes.Int(col),
+	}
+}
+
+func rawNode(start, end nodes.Object) nodes.Object {
+	pos := nodes.Object{KeyType: nodes.String(TypePositions)}
+	if start != nil {
+		pos[KeyStart] = start
+	}
+	if end != nil {
+		pos[KeyEnd] = end
+	}
+	return nodes.Object{
+		KeyType: nodes.String("Ident"),
+		KeyPos:  pos,
+	}
+}
+
+func TestRepairPositions(t *testing.T) {
+	const src = "package a\n\nvar b = 1\n"
+
+	valid := nodes.Object{
+		KeyType: nodes.String("File"),
+		"body": nodes.Array{
+			rawNode(rawPos(15, 3, 5), rawPos(16, 3, 6)),
+		},
+	}
+
+	cases := []struct {
+		name    string
+		in      nodes.Object
+		opts    RepairOptions
+		exp     nodes.Object
+		repairs []PositionRepair
+	}{
+		{
+			name: "valid",
+			in:   valid,
+			opts: RepairOptions{Source: src},
+			exp:  valid,
+		},
+		{
+			name: "negative clamp",
+			in:   rawNode(rawPos(-1, 1, 1), nil),
+			exp:  rawNode(Position{Offset: 0, Line: 1, Col: 1}.ToObject(), nil),
+			repairs: []PositionRepair{
+				{Key: KeyStart, Check: CheckNegative, Reason: "negative offset: -1"},
+			},
+		},
+		{
+			name: "negative drop",
+			in:   rawNode(rawPos(0, -1, 1), rawPos(3, 1, 4)),
+			opts: RepairOptions{Strategy: RepairDrop},
+			exp:  rawNode(nil, rawPos(3, 1, 4)),
+			repairs: []PositionRepair{
+				{Key: KeyStart, Check: CheckNegative, Dropped: true, Reason: "negative line: -1"},
+			},
+		},
+		{
+			name: "order clamp",
+			in:   rawNode(rawPos(5, 1, 6), rawPos(2, 1, 3)),
+			exp:  rawNode(rawPos(5, 1, 6), rawPos(5, 1, 6)),
+			repairs: []PositionRepair{
+				{Key: KeyEnd, Check: CheckOrder, Reason: "end position precedes the start"},
+			},
+		},
+		{
+			name: "order drop",
+			in:   rawNode(rawPos(0, 2, 6), rawPos(0, 1, 3)),
+			opts: RepairOptions{Strategy: RepairDrop},
+			exp:  rawNode(rawPos(0, 2, 6), nil),
+			repairs: []PositionRepair{
+				{Key: KeyEnd, Check: CheckOrder, Dropped: true, Reason: "end position precedes the start"},
+			},
+		},
+		{
+			name: "order disabled",
+			in:   rawNode(rawPos(5, 1, 6), rawPos(2, 1, 3)),
+			opts: RepairOptions{Checks: CheckNegative},
+			exp:  rawNode(rawPos(5, 1, 6), rawPos(2, 1, 3)),
+		},
+		{
+			name: "bounds clamp",
+			in:   rawNode(rawPos(15, 3, 5), rawPos(100, 3, 86)),
+			opts: RepairOptions{Source: src, Checks: CheckBounds},
+			exp: rawNode(rawPos(15, 3, 5), nodes.Object{
+				KeyType:    nodes.String(TypePosition),
+				KeyPosOff:  nodes.Uint(len(src)),
+				KeyPosLine: nodes.Int(3),
+				KeyPosCol:  nodes.Int(86),
+			}),
+			repairs: []PositionRepair{
+				{Key: KeyEnd, Check: CheckBounds, Reason: "offset 100 is out of source bounds (21)"},
+			},
+		},
+		{
+			name: "bounds drop",
+			in:   rawNode(rawPos(15, 3, 5), rawPos(100, 3, 86)),
+			opts: RepairOptions{Source: src, Strategy: RepairDrop},
+			exp:  rawNode(rawPos(15, 3, 5), nil),
+			repairs: []PositionRepair{
+				{Key: KeyEnd, Check: CheckBounds, Dropped: true, Reason: "offset 100 is out of source bounds (21)"},
+			},
+		},
+		{
+			name: "lines clamp",
+			in:   rawNode(rawPos(15, 1, 16), nil),
+			opts: RepairOptions{Source: src},
+			exp:  rawNode(Position{Offset: 15, Line: 3, Col: 5}.ToObject(), nil),
+			repairs: []PositionRepair{
+				{Key: KeyStart, Check: CheckLines, Reason: "line 1 disagrees with offset 15 (line 3)"},
+			},
+		},
+		{
+			name: "lines tolerance",
+			in:   rawNode(rawPos(15, 2, 5), nil),
+			opts: RepairOptions{Source: src, LineTolerance: 1},
+			exp:  rawNode(rawPos(15, 2, 5), nil),
+		},
+		{
+			name: "lines drop",
+			in:   rawNode(rawPos(15, 1, 16), rawPos(16, 3, 6)),
+			opts: RepairOptions{Source: src, Strategy: RepairDrop},
+			exp:  rawNode(nil, rawPos(16, 3, 6)),
+			repairs: []PositionRepair{
+				{Key: KeyStart, Check: CheckLines, Dropped: true, Reason: "line 1 disagrees with offset 15 (line 3)"},
+			},
+		},
+		{
+			name: "lines without source",
+			in:   rawNode(rawPos(15, 1, 16), nil),
+			exp:  rawNode(rawPos(15, 1, 16), nil),
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			orig := c.in.CloneObject()
+			out, repairs := RepairPositions(c.in, c.opts)
+			require.Equal(t, c.repairs, repairs)
+			require.True(t, nodes.Equal(c.exp, out), "%v", out)
+			require.True(t, nodes.Equal(orig, c.in), "input was modified")
+			if len(repairs) == 0 {
+				require.True(t, nodes.Same(c.in, out))
+			}
+		})
+	}
+}
+
+func TestRepairPositionsPath(t *testing.T) {
+	root := nodes.Object{
+		KeyType: nodes.String("File"),
+		"body": nodes.Array{
+			rawNode(rawPos(0, 1, 1), nil),
+			rawNode(rawPos(5, 1, 6), rawPos(2, 1, 3)),
+		},
+	}
+	_, repairs := RepairPositions(root, RepairOptions{})
+	require.Len(t, repairs, 1)
+	r := repairs[0]
+	require.Equal(t, nodes.Path{nodes.FieldElem("body"), nodes.IndexElem(1)}, r.Path)
+	require.Equal(t, ".body[1].@pos.end: end position precedes the start (clamped)", r.String())
+}
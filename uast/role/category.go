@@ -0,0 +1,171 @@
+package role
+
+import "sort"
+
+// Category is a coarse group of related roles. It is useful to build faceted views over
+// nodes annotated with roles, for example to group all callable constructs together.
+type Category string
+
+const (
+	// CategoryNone is returned for roles that are not assigned to any category (e.g. Invalid).
+	CategoryNone Category = ""
+
+	// CategoryIdentifier groups roles of identifiers and names.
+	CategoryIdentifier Category = "Identifier"
+
+	// CategoryOperator groups roles of operators and operator properties.
+	CategoryOperator Category = "Operator"
+
+	// CategoryExpression groups roles of generic expressions and statements.
+	CategoryExpression Category = "Expression"
+
+	// CategoryStructure groups roles of structural nodes: files, blocks, scopes, etc.
+	CategoryStructure Category = "Structure"
+
+	// CategoryModule groups roles related to packages, modules and imports.
+	CategoryModule Category = "Module"
+
+	// CategoryDeclaration groups roles of declarations and their properties.
+	CategoryDeclaration Category = "Declaration"
+
+	// CategoryCallable groups roles of functions, calls and their arguments.
+	CategoryCallable Category = "Callable"
+
+	// CategoryControlFlow groups roles of conditionals, loops, jumps and exceptions.
+	CategoryControlFlow Category = "ControlFlow"
+
+	// CategoryLiteral groups roles of literals and primitive values.
+	CategoryLiteral Category = "Literal"
+
+	// CategoryComment groups roles of comments, documentation and whitespace.
+	CategoryComment Category = "Comment"
+)
+
+// categoryTable is the single source of truth for role categories.
+// Each role must be listed exactly once.
+var categoryTable = map[Category][]Role{
+	CategoryIdentifier: {
+		Identifier, Qualified, Name, Alias, Pathname, This,
+	},
+	CategoryOperator: {
+		Operator, Binary, Unary, Left, Right, Infix, Postfix,
+		Bitwise, Boolean, Unsigned, LeftShift, RightShift, Or, Xor, And,
+		Equal, Not, LessThan, LessThanOrEqual, GreaterThan, GreaterThanOrEqual,
+		Identical, Contains, Increment, Decrement, Negative, Positive,
+		Dereference, TakeAddress, Add, Substract, Multiply, Divide, Modulo,
+		Arithmetic, Relational,
+	},
+	CategoryExpression: {
+		Expression, Statement, Assignment, Value, Noop,
+	},
+	CategoryStructure: {
+		File, Body, Block, Scope, Incomplete, Unannotated,
+	},
+	CategoryModule: {
+		Package, Import, Module, Subpackage, Friend, World,
+	},
+	CategoryDeclaration: {
+		Declaration, Variable, Type, Enumeration, Visibility, Annotation, Anonymous,
+		Implements, Base, Subtype, Instance,
+	},
+	CategoryCallable: {
+		Function, Receiver, Argument, ArgsList, Call, Callee, Positional, Return,
+	},
+	CategoryControlFlow: {
+		If, Condition, Then, Else, Switch, Case, Default,
+		For, Initialization, Update, Iterator, While, DoWhile,
+		Break, Continue, Goto, Try, Catch, Finally, Throw, Assert,
+	},
+	CategoryLiteral: {
+		Literal, Byte, ByteString, Character, List, Map, Null, Number, Regexp,
+		Set, String, Tuple, Entry, Key, Primitive,
+	},
+	CategoryComment: {
+		Comment, Documentation, Whitespace,
+	},
+}
+
+var roleCategory = make(map[Role]Category)
+
+func init() {
+	for c, roles := range categoryTable {
+		for _, r := range roles {
+			if c2, ok := roleCategory[r]; ok {
+				panic("role " + r.String() + " is assigned to multiple categories: " + string(c) + ", " + string(c2))
+			}
+			roleCategory[r] = c
+		}
+	}
+}
+
+// Categories returns all known role categories, sorted by name.
+func Categories() []Category {
+	out := make([]Category, 0, len(categoryTable))
+	for c := range categoryTable {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// CategoryOf returns a category of the role. It returns CategoryNone for invalid or unknown roles.
+func CategoryOf(r Role) Category {
+	return roleCategory[r]
+}
+
+// RolesInCategory returns all roles that belong to a given category, ordered by role value.
+func RolesInCategory(c Category) []Role {
+	roles := categoryTable[c]
+	if len(roles) == 0 {
+		return nil
+	}
+	out := make([]Role, len(roles))
+	copy(out, roles)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// categoryPrecedence is the order in which PrimaryCategory picks a category for roles spanning multiple categories.
+// More specific categories go first, generic ones like expressions and structural nodes go last.
+var categoryPrecedence = []Category{
+	CategoryComment,
+	CategoryModule,
+	CategoryDeclaration,
+	CategoryControlFlow,
+	CategoryCallable,
+	CategoryLiteral,
+	CategoryOperator,
+	CategoryIdentifier,
+	CategoryExpression,
+	CategoryStructure,
+}
+
+// PrimaryCategory returns a single category that describes a node with a given set of roles.
+//
+// If the roles belong to multiple categories, the first one in the following order wins: Comment, Module,
+// Declaration, ControlFlow, Callable, Literal, Operator, Identifier, Expression, Structure. For example,
+// a node with Function and Declaration roles is a declaration, and a node with Call and Expression roles
+// is a callable. It returns CategoryNone if none of the roles have a category.
+func PrimaryCategory(roles ...Role) Category {
+	best := len(categoryPrecedence)
+	for _, r := range roles {
+		c := CategoryOf(r)
+		if c == CategoryNone {
+			continue
+		}
+		for i := 0; i < best; i++ {
+			if categoryPrecedence[i] == c {
+				best = i
+				break
+			}
+		}
+	}
+	if best == len(categoryPrecedence) {
+		return CategoryNone
+	}
+	return categoryPrecedence[best]
+}
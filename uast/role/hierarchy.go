@@ -0,0 +1,99 @@
+package role
+
+// parentTable is the single source of truth for the "is-a" relationship between roles.
+// Each entry maps a specific role to its direct, more generic parents.
+//
+// Only roles that are always a more specific form of the parent are listed here.
+// Roles that are merely used together with another role (e.g. String with Literal or Type)
+// must not be listed.
+var parentTable = map[Role][]Role{
+	Qualified: {Identifier},
+
+	Arithmetic: {Operator},
+	Relational: {Operator},
+	Bitwise:    {Operator},
+
+	Add:       {Arithmetic},
+	Substract: {Arithmetic},
+	Multiply:  {Arithmetic},
+	Divide:    {Arithmetic},
+	Modulo:    {Arithmetic},
+	Increment: {Arithmetic},
+	Decrement: {Arithmetic},
+
+	LeftShift:  {Bitwise},
+	RightShift: {Bitwise},
+
+	Equal:              {Relational},
+	Identical:          {Relational},
+	LessThan:           {Relational},
+	LessThanOrEqual:    {Relational},
+	GreaterThan:        {Relational},
+	GreaterThanOrEqual: {Relational},
+	Contains:           {Relational},
+
+	Call:    {Expression},
+	Literal: {Expression},
+
+	Return:   {Statement},
+	Break:    {Statement},
+	Continue: {Statement},
+	Goto:     {Statement},
+
+	Subpackage:  {Package},
+	Subtype:     {Type},
+	Enumeration: {Type},
+	DoWhile:     {While},
+}
+
+// roleAncestors is a transitive closure of parentTable.
+var roleAncestors = make(map[Role]map[Role]struct{})
+
+func init() {
+	var visit func(r Role, path []Role) map[Role]struct{}
+	visit = func(r Role, path []Role) map[Role]struct{} {
+		if m, ok := roleAncestors[r]; ok {
+			return m
+		}
+		for _, p := range path {
+			if p == r {
+				panic("cycle in role hierarchy at role " + r.String())
+			}
+		}
+		path = append(path, r)
+		m := make(map[Role]struct{})
+		for _, p := range parentTable[r] {
+			m[p] = struct{}{}
+			for a := range visit(p, path) {
+				m[a] = struct{}{}
+			}
+		}
+		roleAncestors[r] = m
+		return m
+	}
+	for r := range parentTable {
+		visit(r, nil)
+	}
+}
+
+// Parents returns direct parents of the role in the role hierarchy.
+// It returns nil if the role is not a more specific form of any other role.
+func Parents(r Role) []Role {
+	parents := parentTable[r]
+	if len(parents) == 0 {
+		return nil
+	}
+	out := make([]Role, len(parents))
+	copy(out, parents)
+	return out
+}
+
+// IsA checks if the role is the same as the ancestor role, or is a more specific form of it,
+// either directly or transitively.
+func IsA(r, ancestor Role) bool {
+	if r == ancestor {
+		return true
+	}
+	_, ok := roleAncestors[r][ancestor]
+	return ok
+}
@@ -16,3 +16,46 @@ func TestRoleValid(t *testing.T) {
 	require.False(t, (Invalid).Valid())
 	require.False(t, Role(-1).Valid())
 }
+
+func TestRoleCategories(t *testing.T) {
+	for r := Invalid + 1; r.Valid(); r++ {
+		c := CategoryOf(r)
+		require.NotEqual(t, CategoryNone, c, "role %v has no category", r)
+		require.Contains(t, RolesInCategory(c), r)
+	}
+	require.Equal(t, CategoryNone, CategoryOf(Invalid))
+	require.Equal(t, CategoryCallable, CategoryOf(Function))
+	require.Nil(t, RolesInCategory(CategoryNone))
+	require.Len(t, Categories(), len(categoryTable))
+	require.Len(t, categoryPrecedence, len(categoryTable))
+
+	require.Equal(t, CategoryNone, PrimaryCategory())
+	require.Equal(t, CategoryNone, PrimaryCategory(Invalid))
+	require.Equal(t, CategoryDeclaration, PrimaryCategory(Function, Declaration))
+	require.Equal(t, CategoryCallable, PrimaryCategory(Expression, Call))
+	require.Equal(t, CategoryLiteral, PrimaryCategory(Expression, Literal, String))
+}
+
+func TestRoleHierarchy(t *testing.T) {
+	require.Equal(t, []Role{Relational}, Parents(LessThan))
+	require.Nil(t, Parents(Operator))
+
+	require.True(t, IsA(Operator, Operator))
+	require.True(t, IsA(LessThan, Relational))
+	require.True(t, IsA(LessThan, Operator))
+	require.True(t, IsA(LeftShift, Operator))
+	require.True(t, IsA(Call, Expression))
+
+	require.False(t, IsA(Operator, LessThan))
+	require.False(t, IsA(LessThan, Arithmetic))
+	require.False(t, IsA(Function, Declaration))
+	require.False(t, IsA(String, Literal))
+
+	for r := range parentTable {
+		require.True(t, r.Valid(), "invalid role in hierarchy: %v", r)
+		for _, p := range parentTable[r] {
+			require.True(t, p.Valid(), "invalid parent role for %v: %v", r, p)
+			require.NotEqual(t, r, p)
+		}
+	}
+}
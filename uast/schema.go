@@ -0,0 +1,95 @@
+package uast
+
+import (
+	"encoding/json"
+
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// JSONSchemaID is a JSON Schema dialect used by JSONSchema.
+const JSONSchemaID = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema returns a JSON Schema document that describes the JSON encoding of UAST nodes.
+//
+// The schema is generated from the same key constants and role definitions used by this package,
+// thus it always matches the current UAST format.
+func JSONSchema() []byte {
+	type M = map[string]interface{}
+	ref := func(name string) M {
+		return M{"$ref": "#/definitions/" + name}
+	}
+
+	var (
+		names  = []string{}
+		idList = []int{}
+		ids    = M{}
+	)
+	for r := role.Role(1); r.Valid(); r++ {
+		names = append(names, r.String())
+		idList = append(idList, int(r))
+		ids[r.String()] = int(r)
+	}
+
+	uint32Type := M{"type": "integer", "minimum": 0, "maximum": 1<<32 - 1}
+	schema := M{
+		"$schema": JSONSchemaID,
+		"title":   "UAST node",
+		"$ref":    "#/definitions/node",
+		"definitions": M{
+			"node": M{
+				"anyOf": []interface{}{
+					ref("object"),
+					M{"type": "array", "items": ref("node")},
+					ref("value"),
+				},
+			},
+			"value": M{
+				"type": []string{"string", "number", "boolean", "null"},
+			},
+			"object": M{
+				"type": "object",
+				"properties": M{
+					KeyType:  M{"type": "string"},
+					KeyToken: ref("value"),
+					KeyRoles: ref("roles"),
+					KeyPos:   ref("positions"),
+				},
+				"additionalProperties": ref("node"),
+			},
+			// roles are accepted both as names and as numeric IDs, the same way as in uastjson
+			"roles": M{
+				"type": "array",
+				"items": M{
+					"anyOf": []interface{}{
+						M{"type": "string", "enum": names},
+						M{"type": "integer", "enum": idList},
+					},
+				},
+				"x-roleIds": ids,
+			},
+			"positions": M{
+				"type": "object",
+				"properties": M{
+					KeyType: M{"const": TypePositions},
+				},
+				"additionalProperties": ref("position"),
+			},
+			"position": M{
+				"type": "object",
+				"properties": M{
+					KeyType:    M{"const": TypePosition},
+					KeyPosOff:  uint32Type,
+					KeyPosLine: uint32Type,
+					KeyPosCol:  uint32Type,
+				},
+				"required":             []string{KeyType},
+				"additionalProperties": false,
+			},
+		},
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
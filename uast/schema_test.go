@@ -0,0 +1,59 @@
+package uast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast/role"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Schema      string `json:"$schema"`
+		Definitions struct {
+			Object struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"object"`
+			Roles struct {
+				Items struct {
+					AnyOf []struct {
+						Type string        `json:"type"`
+						Enum []interface{} `json:"enum"`
+					} `json:"anyOf"`
+				} `json:"items"`
+				IDs map[string]int `json:"x-roleIds"`
+			} `json:"roles"`
+			Position struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"position"`
+		} `json:"definitions"`
+	}
+	err := json.Unmarshal(JSONSchema(), &schema)
+	require.NoError(t, err)
+	require.Equal(t, JSONSchemaID, schema.Schema)
+
+	for _, k := range []string{KeyType, KeyToken, KeyRoles, KeyPos} {
+		require.Contains(t, schema.Definitions.Object.Properties, k)
+	}
+	for _, k := range []string{KeyType, KeyPosOff, KeyPosLine, KeyPosCol} {
+		require.Contains(t, schema.Definitions.Position.Properties, k)
+	}
+
+	roles := schema.Definitions.Roles
+	require.Len(t, roles.Items.AnyOf, 2)
+	names, ids := roles.Items.AnyOf[0], roles.Items.AnyOf[1]
+	require.Equal(t, "string", names.Type)
+	require.Equal(t, "integer", ids.Type)
+	require.Equal(t, len(names.Enum), len(roles.IDs))
+	require.Equal(t, len(names.Enum), len(ids.Enum))
+	require.Equal(t, role.Identifier.String(), names.Enum[0])
+	for i, v := range names.Enum {
+		name := v.(string)
+		r := role.FromString(name)
+		require.True(t, r.Valid(), name)
+		require.Equal(t, int(r), roles.IDs[name])
+		require.Equal(t, float64(r), ids.Enum[i])
+	}
+	require.NotContains(t, roles.IDs, role.Invalid.String())
+}
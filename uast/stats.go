@@ -0,0 +1,79 @@
+package uast
+
+import (
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// TreeStats is a set of metrics collected for a tree. See Stats.
+type TreeStats struct {
+	Nodes       int // total number of nodes, including nil nodes
+	MaxDepth    int // maximal depth of the tree; a single root node has a depth of 1
+	Objects     int // number of object nodes
+	Arrays      int // number of array nodes
+	Values      int // number of value nodes, including nil nodes
+	ApproxBytes int // approximate size of the encoded tree in bytes
+}
+
+// Approximate size of the encoding overhead for specific nodes. The values are based on the binary protobuf
+// encoding used by the protocol, without taking string deduplication into account.
+const (
+	approxNodeBytes  = 2 // node tag and length
+	approxFieldBytes = 2 // key tag and length
+	approxNumBytes   = 8 // integer and floating point values
+	approxBoolBytes  = 1
+)
+
+// Stats collects metrics for a given tree in a single traversal.
+func Stats(root nodes.External) TreeStats {
+	var st TreeStats
+	if root == nil {
+		return st
+	}
+	st.collect(root, 1)
+	return st
+}
+
+func (st *TreeStats) collect(n nodes.External, depth int) {
+	st.Nodes++
+	if depth > st.MaxDepth {
+		st.MaxDepth = depth
+	}
+	st.ApproxBytes += approxNodeBytes
+	switch nodes.KindOf(n) {
+	case nodes.KindObject:
+		st.Objects++
+		obj, ok := n.(nodes.ExternalObject)
+		if !ok {
+			return
+		}
+		for _, k := range obj.Keys() {
+			st.ApproxBytes += approxFieldBytes + len(k)
+			v, _ := obj.ValueAt(k)
+			st.collect(v, depth+1)
+		}
+		return
+	case nodes.KindArray:
+		st.Arrays++
+		arr, ok := n.(nodes.ExternalArray)
+		if !ok {
+			return
+		}
+		sz := arr.Size()
+		for i := 0; i < sz; i++ {
+			st.collect(arr.ValueAt(i), depth+1)
+		}
+		return
+	}
+	st.Values++
+	if n == nil {
+		return
+	}
+	switch v := n.Value().(type) {
+	case nodes.String:
+		st.ApproxBytes += len(v)
+	case nodes.Int, nodes.Uint, nodes.Float:
+		st.ApproxBytes += approxNumBytes
+	case nodes.Bool:
+		st.ApproxBytes += approxBoolBytes
+	}
+}
@@ -0,0 +1,37 @@
+package uast
+
+import (
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	root := nodes.Object{
+		KeyType: nodes.String("File"),
+		"body": nodes.Array{
+			nodes.Object{
+				KeyType: nodes.String("Ident"),
+				"Name":  nodes.String("a"),
+			},
+			nil,
+		},
+		"n": nodes.Int(1),
+	}
+	st := Stats(root)
+	require.Equal(t, TreeStats{
+		Nodes:    8,
+		MaxDepth: 4,
+		Objects:  2,
+		Arrays:   1,
+		Values:   5,
+		// nodes + fields + strings + int
+		ApproxBytes: 8*approxNodeBytes + (5*approxFieldBytes + 19) + 10 + approxNumBytes,
+	}, st)
+
+	require.Equal(t, TreeStats{}, Stats(nil))
+	require.Equal(t, TreeStats{
+		Nodes: 1, MaxDepth: 1, Values: 1, ApproxBytes: approxNodeBytes + 1,
+	}, Stats(nodes.String("a")))
+}
@@ -0,0 +1,58 @@
+package uast
+
+import (
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// TokenIndex is an index of nodes by their token (see KeyToken).
+//
+// The index stores references to original nodes, thus the tree must not be modified while the index is in use.
+type TokenIndex struct {
+	byToken map[string][]nodes.External
+}
+
+// NewTokenIndex builds an index of all nodes with a token in a given tree. The tree is traversed only once.
+func NewTokenIndex(root nodes.External) *TokenIndex {
+	idx := &TokenIndex{byToken: make(map[string][]nodes.External)}
+	nodes.WalkPreOrderExt(root, func(n nodes.External) bool {
+		obj, ok := n.(nodes.ExternalObject)
+		if !ok {
+			return true
+		}
+		v, ok := obj.ValueAt(KeyToken)
+		if !ok || v == nil {
+			return true
+		}
+		tok := tokenOfExt(v)
+		if tok != "" {
+			idx.byToken[tok] = append(idx.byToken[tok], n)
+		}
+		return true
+	})
+	return idx
+}
+
+func tokenOfExt(n nodes.External) string {
+	switch v := n.Value().(type) {
+	case nil:
+		return ""
+	case nodes.String:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Lookup returns all nodes with a given token, in pre-order. It returns nil if there are no such nodes.
+//
+// The returned slice must not be modified.
+func (idx *TokenIndex) Lookup(token string) []nodes.External {
+	return idx.byToken[token]
+}
+
+// Len returns the number of distinct tokens in the index.
+func (idx *TokenIndex) Len() int {
+	return len(idx.byToken)
+}
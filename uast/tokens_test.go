@@ -0,0 +1,64 @@
+package uast
+
+import (
+	"testing"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIndex(t *testing.T) {
+	foo1 := nodes.Object{
+		KeyType:  nodes.String("Ident"),
+		KeyToken: nodes.String("foo"),
+		KeyPos: Positions{
+			KeyStart: {Offset: 1, Line: 1, Col: 2},
+		}.ToObject(),
+	}
+	foo2 := nodes.Object{
+		KeyType:  nodes.String("Ident"),
+		KeyToken: nodes.String("foo"),
+		KeyPos: Positions{
+			KeyStart: {Offset: 10, Line: 2, Col: 3},
+		}.ToObject(),
+	}
+	bar := nodes.Object{
+		KeyType:  nodes.String("Ident"),
+		KeyToken: nodes.String("bar"),
+	}
+	num := nodes.Object{
+		KeyType:  nodes.String("Num"),
+		KeyToken: nodes.Int(42),
+	}
+	root := nodes.Object{
+		KeyType: nodes.String("File"),
+		"a":     foo1,
+		"b": nodes.Array{
+			bar,
+			nodes.Object{
+				KeyType: nodes.String("Call"),
+				"x":     foo2,
+				"y":     num,
+			},
+		},
+	}
+
+	idx := NewTokenIndex(root)
+	require.Equal(t, 3, idx.Len())
+
+	list := idx.Lookup("foo")
+	require.Len(t, list, 2)
+	require.True(t, list[0].SameAs(foo1))
+	require.True(t, list[1].SameAs(foo2))
+	require.Equal(t, uint32(10), PositionsOf(list[1].(nodes.Node)).Start().Offset)
+
+	list = idx.Lookup("bar")
+	require.Len(t, list, 1)
+	require.True(t, list[0].SameAs(bar))
+
+	list = idx.Lookup("42")
+	require.Len(t, list, 1)
+	require.True(t, list[0].SameAs(num))
+
+	require.Nil(t, idx.Lookup("baz"))
+}
@@ -0,0 +1,63 @@
+package transformer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ Transformer = requireAnnotated{}
+
+// RequireAnnotated is a transformation that checks that every typed object in the tree has at least one role.
+// It is intended to be the last step of the annotation pipeline, to make sure the driver mappings do not miss
+// any native types.
+//
+// Types listed in the allowlist are not checked. Types from the UAST namespace (see uast.NS), values and arrays are
+// never checked. If any unannotated types are found, ErrUnannotatedTypes is returned with a sorted list of them.
+//
+// The transformation never modifies the tree.
+func RequireAnnotated(allowlist []string) Transformer {
+	allow := make(map[string]struct{}, len(allowlist))
+	for _, typ := range allowlist {
+		allow[typ] = struct{}{}
+	}
+	return requireAnnotated{allow: allow}
+}
+
+type requireAnnotated struct {
+	allow map[string]struct{}
+}
+
+// Do implements Transformer.
+func (t requireAnnotated) Do(root nodes.Node) (nodes.Node, error) {
+	seen := make(map[string]struct{})
+	var types []string
+	nodes.WalkPreOrder(root, func(n nodes.Node) bool {
+		obj, ok := n.(nodes.Object)
+		if !ok {
+			return true
+		}
+		typ := uast.TypeOf(obj)
+		if typ == "" || strings.HasPrefix(typ, uast.NS+":") {
+			return true
+		}
+		if _, ok := t.allow[typ]; ok {
+			return true
+		}
+		if arr, ok := obj[uast.KeyRoles].(nodes.Array); ok && len(arr) != 0 {
+			return true
+		}
+		if _, ok := seen[typ]; !ok {
+			seen[typ] = struct{}{}
+			types = append(types, typ)
+		}
+		return true
+	})
+	if len(types) != 0 {
+		sort.Strings(types)
+		return nil, ErrUnannotatedTypes.New(types)
+	}
+	return root, nil
+}
@@ -24,6 +24,65 @@ func SavePosLineCol(varLine, varCol string) Op {
 	})
 }
 
+var _ ObjectOp = opInheritPos{}
+
+// InheritPos is an object operation that copies positions of the node stored in a named variable to the constructed
+// object. It is useful for synthetic nodes that have no counterpart in the native AST.
+//
+// Construction is a no-op if the variable is not an object or has no positions. When checking the object, the
+// positions field is accepted and ignored. The operation only handles the positions field, thus it should be
+// combined with other operations using JoinObj.
+func InheritPos(vr string) ObjectOp {
+	return opInheritPos{vr: vr}
+}
+
+type opInheritPos struct {
+	vr string
+}
+
+func (opInheritPos) Kinds() nodes.Kind {
+	return nodes.KindObject
+}
+
+func (opInheritPos) Fields() (FieldDescs, bool) {
+	desc := NewFieldDescs(1)
+	desc.Set(uast.KeyPos, FieldDesc{Optional: true})
+	return desc, true
+}
+
+func (op opInheritPos) Check(st *State, n nodes.Node) (bool, error) {
+	return checkObj(op, st, n)
+}
+
+func (op opInheritPos) CheckObj(st *State, n nodes.Object) (bool, error) {
+	return true, nil
+}
+
+func (op opInheritPos) Construct(st *State, n nodes.Node) (nodes.Node, error) {
+	return constructObj(op, st, n)
+}
+
+func (op opInheritPos) ConstructObj(st *State, n nodes.Object) (nodes.Object, error) {
+	v, err := st.MustGetVar(op.vr)
+	if err != nil {
+		return nil, err
+	}
+	src, ok := v.(nodes.Object)
+	if !ok {
+		return n, nil
+	}
+	pos, ok := src[uast.KeyPos].(nodes.Object)
+	if !ok || len(pos) == 0 {
+		return n, nil
+	}
+	if n == nil {
+		n = make(nodes.Object)
+	}
+	// positions can be shared, since transformations never modify the tree in-place (see Transformer)
+	n[uast.KeyPos] = pos
+	return n, nil
+}
+
 // Roles makes an operation that will check/construct a list of roles.
 func Roles(roles ...role.Role) ArrayOp {
 	arr := make([]Op, 0, len(roles))
@@ -43,6 +102,65 @@ func AppendRoles(old ArrayOp, roles ...role.Role) ArrayOp {
 	return Roles(roles...)
 }
 
+var _ ObjectSel = hasRole{}
+
+// HasRole is a check-only operation that verifies that the object already has a given role in its roles field.
+// Objects without the roles field never match.
+//
+// It can be used as a selector in Check or CheckObj to refine nodes annotated by a previous transformation pass.
+func HasRole(r role.Role) ObjectSel {
+	return hasRole{name: nodes.String(r.String())}
+}
+
+// HasRoleOrSub is like HasRole, but also matches objects that have a more specific role,
+// according to the role hierarchy (see role.IsA).
+func HasRoleOrSub(r role.Role) ObjectSel {
+	return hasRole{name: nodes.String(r.String()), role: r, sub: true}
+}
+
+type hasRole struct {
+	name nodes.String
+	role role.Role
+	sub  bool
+}
+
+func (hasRole) Kinds() nodes.Kind {
+	return nodes.KindObject
+}
+
+func (op hasRole) Fields() (FieldDescs, bool) {
+	desc := NewFieldDescs(1)
+	desc.Set(uast.KeyRoles, FieldDesc{Optional: false})
+	return desc, false
+}
+
+func (op hasRole) CheckObj(st *State, n nodes.Object) (bool, error) {
+	arr, ok := n[uast.KeyRoles].(nodes.Array)
+	if !ok {
+		return false, nil
+	}
+	for _, v := range arr {
+		if v == op.name {
+			return true, nil
+		}
+		if !op.sub {
+			continue
+		}
+		if s, ok := v.(nodes.String); ok && role.IsA(role.FromString(string(s)), op.role) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (op hasRole) Check(st *State, n nodes.Node) (bool, error) {
+	o, ok := n.(nodes.Object)
+	if !ok {
+		return false, nil
+	}
+	return op.CheckObj(st, o)
+}
+
 // RolesField will create a roles field that appends provided roles to existing ones.
 // In case no roles are provided, it will save existing roles, if any.
 func RolesField(vr string, roles ...role.Role) Field {
@@ -374,10 +492,10 @@ func AnnotateTypeCustom(typ string, fields ObjMapping, rop ArrayOp, roles ...rol
 		fields = MapObj(Obj{}, Obj{})
 	}
 	ast, norm := fields.ObjMapping()
-	return MapObj(
+	return newAnnotation(MapObj(
 		ASTObjectLeft(typ, ast),
 		ASTObjectRight(typ, norm, rop, roles...),
-	)
+	), fields, rop != nil, roles...)
 }
 
 // AnnotateTypeCustomMap is like AnnotateTypeCustom, but allows to specify additional roles for each type.
@@ -386,10 +504,14 @@ func AnnotateTypeCustomMap(typ string, fields ObjMapping, fnc RolesByType, rop A
 		fields = MapObj(Obj{}, Obj{})
 	}
 	ast, norm := fields.ObjMapping()
-	return MapObj(
+	if fnc != nil {
+		roles = append(append([]role.Role{}, roles...), fnc(typ)...)
+		fnc = nil
+	}
+	return newAnnotation(MapObj(
 		ASTObjectLeft(typ, ast),
 		ASTObjectRightCustom(typ, norm, fnc, rop, roles...),
-	)
+	), fields, rop != nil, roles...)
 }
 
 // AnnotateType is a helper to assign roles to specific fields. All fields are assumed to be optional and should be objects.
@@ -412,18 +534,21 @@ func StringToRolesMap(m map[string][]role.Role) map[nodes.Value]ArrayOp {
 // Since rules are applied depth-first, this operation will work properly only in a separate mapping step.
 // In other cases it will apply itself before parent node appends field roles.
 func AnnotateIfNoRoles(typ string, roles ...role.Role) Mapping {
-	return MapObj(
-		CheckObj(
-			HasFields{
-				uast.KeyRoles: false,
-			},
+	return annotation{
+		m: MapObj(
+			CheckObj(
+				HasFields{
+					uast.KeyRoles: false,
+				},
+				Part("_", Obj{
+					uast.KeyType: String(typ),
+				}),
+			),
 			Part("_", Obj{
-				uast.KeyType: String(typ),
+				uast.KeyType:  String(typ),
+				uast.KeyRoles: Roles(roles...),
 			}),
 		),
-		Part("_", Obj{
-			uast.KeyType:  String(typ),
-			uast.KeyRoles: Roles(roles...),
-		}),
-	)
+		roles: roles,
+	}
 }
@@ -0,0 +1,34 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// TagCategory is an irreversible transformation that stores a coarse category of each object with roles
+// in a given field. The category is derived from the roles with role.PrimaryCategory, which also documents
+// the precedence for roles spanning multiple categories.
+//
+// The category allows to pre-filter nodes with a cheap string comparison before running expensive queries.
+// Objects without roles, or with roles that have no category, are left unchanged. Existing values of the
+// field are overwritten.
+func TagCategory(key string) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		roles := uast.AnnotatedRoles(n)
+		if len(roles) == 0 {
+			return n, false, nil
+		}
+		c := role.PrimaryCategory(roles...)
+		if c == role.CategoryNone {
+			return n, false, nil
+		}
+		v := nodes.String(c)
+		if old, ok := n[key]; ok && old == v {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		n[key] = v
+		return n, true, nil
+	})
+}
@@ -0,0 +1,99 @@
+package transformer
+
+import (
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// shebang is a prefix of the interpreter directive on the first line of a script.
+const shebang = "#!"
+
+// CommentStyle describes a native comment type for NormalizeComments.
+type CommentStyle struct {
+	// Block is set for block comments, like "/* */". Line comments are assumed otherwise.
+	Block bool
+	// Doc is set if all comments of this type are documentation comments.
+	Doc bool
+	// Start and End are tokens that open and close the comment, e.g. "/*" and "*/", or "//" for line comments.
+	Start, End string
+}
+
+// CommentOptions configures NormalizeComments.
+type CommentOptions struct {
+	// Types maps native comment types to their style.
+	Types map[string]CommentStyle
+	// DocPrefixes is a list of tokens that mark documentation comments, e.g. "/**" or "///".
+	DocPrefixes []string
+	// Shebang enables normalization of comments that start with "#!" as line comments. Only comments that
+	// start at the beginning of the file are considered shebang lines, and they are never considered documentation.
+	Shebang bool
+	// StripMarkers removes the comment tokens, the surrounding whitespace and the common indentation from the token
+	// field of comment nodes, the same way as CommentText does.
+	StripMarkers bool
+}
+
+// NormalizeComments is an irreversible transformation that annotates native comment nodes with role.Comment,
+// and with role.Documentation for documentation comments. It also sets the uast.KeyCommentBlock field to distinguish
+// block comments from line comments.
+//
+// If StripMarkers is set, the comment tokens are removed from the uast.KeyToken field. Positional information
+// is never changed, thus multi-line block comments keep their full range, including the tokens.
+func NormalizeComments(opts CommentOptions) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		style, ok := opts.Types[uast.TypeOf(n)]
+		if !ok {
+			return n, false, nil
+		}
+		tok, _ := n[uast.KeyToken].(nodes.String)
+		text := string(tok)
+
+		isShebang := opts.Shebang && strings.HasPrefix(text, shebang) && atFileStart(n)
+		if isShebang {
+			style = CommentStyle{Start: shebang}
+		}
+		start, doc := style.Start, style.Doc
+		// neither a shebang nor an empty block comment like "/**/" is a documentation comment
+		if !doc && !isShebang && !(style.Block && text == style.Start+style.End) {
+			for _, pref := range opts.DocPrefixes {
+				if strings.HasPrefix(text, pref) {
+					doc = true
+					if len(pref) > len(start) {
+						start = pref
+					}
+					break
+				}
+			}
+		}
+
+		n = n.CloneObject()
+		roles := []role.Role{role.Comment}
+		if doc {
+			roles = append(roles, role.Documentation)
+		}
+		n[uast.KeyRoles] = appendRoles(n[uast.KeyRoles], roles)
+		n[uast.KeyCommentBlock] = nodes.Bool(style.Block)
+		if opts.StripMarkers && tok != "" {
+			c := commentElems{StartToken: start, EndToken: style.End}
+			if c.Split(text) {
+				n[uast.KeyToken] = nodes.String(c.Text)
+			}
+		}
+		return n, true, nil
+	})
+}
+
+// atFileStart checks if the node starts at the first line of the file.
+// It returns false if the node has no positional information.
+func atFileStart(n nodes.Object) bool {
+	p := uast.PositionsOf(n).Start()
+	if p == nil {
+		return false
+	}
+	if p.HasLineCol() {
+		return p.Line == 1
+	}
+	return p.HasOffset() && p.Offset == 0
+}
@@ -0,0 +1,86 @@
+package transformer
+
+import (
+	"strconv"
+
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// ErrRoleNotAllowed is returned by CheckRoleTypeConstraints when a role is assigned to a node of a type
+// that is not allowed for this role.
+var ErrRoleNotAllowed = errors.NewKind("%s: role %v is not allowed on type %q")
+
+var _ Transformer = RoleTypeConstraints{}
+
+// RoleTypeConstraints is a check-only transformation that verifies that roles are only assigned to nodes
+// of specific types. Roles that are not listed in the map are allowed on any type.
+//
+// It should run after all annotation passes. The transformation never modifies the tree.
+// All violations are reported at once as a MultiError, each including the path of the node.
+type RoleTypeConstraints map[role.Role][]string
+
+// CheckRoleTypeConstraints creates a transformation that verifies that roles are only assigned to
+// the listed node types. See RoleTypeConstraints for details.
+//
+// The check is opt-in and should be added to the list of transformations by the driver explicitly.
+func CheckRoleTypeConstraints(table map[role.Role][]string) RoleTypeConstraints {
+	return RoleTypeConstraints(table)
+}
+
+// Do implements Transformer.
+func (c RoleTypeConstraints) Do(root nodes.Node) (nodes.Node, error) {
+	if len(c) == 0 {
+		return root, nil
+	}
+	allowed := make(map[role.Role]map[string]struct{}, len(c))
+	for r, types := range c {
+		m := make(map[string]struct{}, len(types))
+		for _, typ := range types {
+			m[typ] = struct{}{}
+		}
+		allowed[r] = m
+	}
+	var errs []error
+	var walk func(path string, n nodes.Node)
+	walk = func(path string, n nodes.Node) {
+		switch n := n.(type) {
+		case nodes.Object:
+			typ := uast.TypeOf(n)
+			roles, _ := n[uast.KeyRoles].(nodes.Array)
+			for _, v := range roles {
+				s, ok := v.(nodes.String)
+				if !ok {
+					continue
+				}
+				r := role.FromString(string(s))
+				types, ok := allowed[r]
+				if !ok {
+					continue
+				}
+				if _, ok = types[typ]; !ok {
+					p := path
+					if p == "" {
+						p = "<root>"
+					}
+					errs = append(errs, ErrRoleNotAllowed.New(p, r, typ))
+				}
+			}
+			for _, k := range n.Keys() {
+				walk(path+"."+k, n[k])
+			}
+		case nodes.Array:
+			for i, v := range n {
+				walk(path+"["+strconv.Itoa(i)+"]", v)
+			}
+		}
+	}
+	walk("", root)
+	if err := NewMultiError(errs...); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
@@ -2,7 +2,10 @@ package transformer
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
 )
 
 // Quote uses strconv.Quote/Unquote to wrap provided string value.
@@ -17,3 +20,142 @@ func Quote(op Op) Op {
 		return strconv.Quote(s), nil
 	})
 }
+
+// StringToInt converts a string value to an integer and passes it to sub-operation. On construction, the integer
+// created by the sub-operation is converted back to a string.
+func StringToInt(op Op) Op {
+	return Cast(nodes.KindString, nodes.KindInt, op)
+}
+
+// IntToString converts an integer value to a string and passes it to sub-operation. On construction, the string
+// created by the sub-operation is converted back to an integer.
+func IntToString(op Op) Op {
+	return Cast(nodes.KindInt, nodes.KindString, op)
+}
+
+// Cast converts a value of one kind to another and passes it to sub-operation. On construction, the value created
+// by the sub-operation is converted back to the original kind.
+//
+// Only value kinds are supported. Values of other kinds are skipped during the check. A value that cannot be
+// converted (like a non-numeric string) fails the transformation with ErrInvalidConversion.
+func Cast(from, to nodes.Kind, op Op) Op {
+	if !isCastKind(from) || !isCastKind(to) {
+		panic(fmt.Errorf("cannot cast from %v to %v", from, to))
+	}
+	conv := func(from, to nodes.Kind) ValueFunc {
+		return func(v nodes.Value) (nodes.Value, error) {
+			if k := nodes.KindOf(v); k != from {
+				return nil, ErrUnexpectedType.New(zeroValue(from), v)
+			}
+			return castValue(v, to)
+		}
+	}
+	return valueConvKind(op, from, conv(from, to), conv(to, from))
+}
+
+func isCastKind(k nodes.Kind) bool {
+	switch k {
+	case nodes.KindString, nodes.KindInt, nodes.KindUint, nodes.KindFloat, nodes.KindBool:
+		return true
+	}
+	return false
+}
+
+func zeroValue(k nodes.Kind) nodes.Value {
+	switch k {
+	case nodes.KindString:
+		return nodes.String("")
+	case nodes.KindInt:
+		return nodes.Int(0)
+	case nodes.KindUint:
+		return nodes.Uint(0)
+	case nodes.KindFloat:
+		return nodes.Float(0)
+	case nodes.KindBool:
+		return nodes.Bool(false)
+	}
+	return nil
+}
+
+// castValue converts a value to a given kind.
+func castValue(v nodes.Value, to nodes.Kind) (nodes.Value, error) {
+	if nodes.KindOf(v) == to {
+		return v, nil
+	}
+	bad := func() (nodes.Value, error) {
+		return nil, ErrInvalidConversion.New(fmt.Sprint(v), v, to)
+	}
+	switch to {
+	case nodes.KindString:
+		switch v := v.(type) {
+		case nodes.Int:
+			return nodes.String(strconv.FormatInt(int64(v), 10)), nil
+		case nodes.Uint:
+			return nodes.String(strconv.FormatUint(uint64(v), 10)), nil
+		case nodes.Float:
+			return nodes.String(strconv.FormatFloat(float64(v), 'g', -1, 64)), nil
+		case nodes.Bool:
+			return nodes.String(strconv.FormatBool(bool(v))), nil
+		}
+	case nodes.KindInt:
+		switch v := v.(type) {
+		case nodes.String:
+			i, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return bad()
+			}
+			return nodes.Int(i), nil
+		case nodes.Uint:
+			if v > math.MaxInt64 {
+				return bad()
+			}
+			return nodes.Int(v), nil
+		case nodes.Float:
+			if float64(v) != math.Trunc(float64(v)) || v < math.MinInt64 || v >= math.MaxInt64 {
+				return bad()
+			}
+			return nodes.Int(v), nil
+		}
+	case nodes.KindUint:
+		switch v := v.(type) {
+		case nodes.String:
+			i, err := strconv.ParseUint(string(v), 10, 64)
+			if err != nil {
+				return bad()
+			}
+			return nodes.Uint(i), nil
+		case nodes.Int:
+			if v < 0 {
+				return bad()
+			}
+			return nodes.Uint(v), nil
+		case nodes.Float:
+			if float64(v) != math.Trunc(float64(v)) || v < 0 || v >= math.MaxUint64 {
+				return bad()
+			}
+			return nodes.Uint(v), nil
+		}
+	case nodes.KindFloat:
+		switch v := v.(type) {
+		case nodes.String:
+			f, err := strconv.ParseFloat(string(v), 64)
+			if err != nil {
+				return bad()
+			}
+			return nodes.Float(f), nil
+		case nodes.Int:
+			return nodes.Float(v), nil
+		case nodes.Uint:
+			return nodes.Float(v), nil
+		}
+	case nodes.KindBool:
+		if v, ok := v.(nodes.String); ok {
+			b, err := strconv.ParseBool(string(v))
+			if err != nil {
+				return bad()
+			}
+			return nodes.Bool(b), nil
+		}
+	}
+	return bad()
+}
@@ -0,0 +1,72 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// Equality checks if two nodes should be considered equal.
+type Equality func(a, b nodes.Node) bool
+
+// StructuralEqual creates an equality that compares nodes by value, including positional information.
+func StructuralEqual() Equality {
+	return nodes.NodeEqual
+}
+
+// EqualWith creates an equality that compares nodes by value, relaxed by given options.
+// See nodes.IgnoreFields and nodes.FloatTolerance.
+func EqualWith(opts ...nodes.EqualOption) Equality {
+	return nodes.NewComparer(opts...).Equal
+}
+
+// EqualIgnoring creates an equality that compares nodes by value, but skips object fields with given names
+// on all levels of the tree.
+func EqualIgnoring(fields ...string) Equality {
+	return EqualWith(nodes.IgnoreFields(fields...))
+}
+
+// EqualIgnoringPositions creates an equality that compares nodes by value, but skips positional information.
+// See uast.KeyPos.
+func EqualIgnoringPositions() Equality {
+	return EqualIgnoring(uast.KeyPos)
+}
+
+// DedupArray is an irreversible transformation that removes duplicate elements of an array stored in a given field
+// of each object. An element is removed if it is equal to one of the preceding elements, thus only the first
+// occurrence is preserved.
+//
+// If the equality is nil, EqualIgnoringPositions is used, thus elements that differ only in positions are
+// considered duplicates. Use StructuralEqual to keep them. Fields that are not arrays are ignored.
+func DedupArray(field string, by Equality) TransformObjFunc {
+	if by == nil {
+		by = EqualIgnoringPositions()
+	}
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		arr, ok := n[field].(nodes.Array)
+		if !ok || len(arr) < 2 {
+			return n, false, nil
+		}
+		var out nodes.Array
+	loop:
+		for i, e := range arr {
+			for _, prev := range arr[:i] {
+				if by(prev, e) {
+					if out == nil {
+						out = make(nodes.Array, i, len(arr)-1)
+						copy(out, arr[:i])
+					}
+					continue loop
+				}
+			}
+			if out != nil {
+				out = append(out, e)
+			}
+		}
+		if out == nil {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		n[field] = out
+		return n, true, nil
+	})
+}
@@ -0,0 +1,61 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// EmptyFieldsOptions controls which fields are removed by DropEmptyFields.
+type EmptyFieldsOptions struct {
+	// KeepArrays disables removal of fields with empty arrays.
+	KeepArrays bool
+	// KeepObjects disables removal of fields with empty objects.
+	KeepObjects bool
+	// KeepNulls disables removal of fields with null values.
+	KeepNulls bool
+	// Keep is a list of field names that are never removed.
+	Keep []string
+}
+
+// DropEmptyFields is an irreversible transformation that removes object fields with empty arrays, empty objects
+// and null values. Options allow to choose which values are considered empty and which fields must be preserved.
+//
+// The tree is processed bottom-up, thus objects that become empty after removing all their fields are removed
+// from the parent as well. Empty arrays and objects that are not stored in object fields are left untouched.
+func DropEmptyFields(opt EmptyFieldsOptions) TransformObjFunc {
+	keep := make(map[string]struct{}, len(opt.Keep))
+	for _, k := range opt.Keep {
+		keep[k] = struct{}{}
+	}
+	isEmpty := func(k string, v nodes.Node) bool {
+		if _, ok := keep[k]; ok {
+			return false
+		}
+		switch v := v.(type) {
+		case nil:
+			return !opt.KeepNulls
+		case nodes.Array:
+			return len(v) == 0 && !opt.KeepArrays
+		case nodes.Object:
+			return len(v) == 0 && !opt.KeepObjects
+		}
+		return false
+	}
+	return TransformObjFunc(func(obj nodes.Object) (nodes.Object, bool, error) {
+		n := 0
+		for k, v := range obj {
+			if isEmpty(k, v) {
+				n++
+			}
+		}
+		if n == 0 {
+			return obj, false, nil
+		}
+		out := make(nodes.Object, len(obj)-n)
+		for k, v := range obj {
+			if !isEmpty(k, v) {
+				out[k] = v
+			}
+		}
+		return out, true, nil
+	})
+}
@@ -42,6 +42,9 @@ var (
 	ErrUnexpectedValue = errors.NewKind("unexpected value: %v")
 	// ErrUnexpectedType is returned in both Check and Construct when unexpected type is received as an argument or variable.
 	ErrUnexpectedType = errors.NewKind("unexpected type: exp %T vs got %T")
+	// ErrInvalidConversion is returned when Cast cannot convert a value to a different kind, for example when a string
+	// does not contain a valid number.
+	ErrInvalidConversion = errors.NewKind("cannot convert %q (%T) to %v")
 	// ErrAmbiguousValue is returned when Lookup map contains the same value mapped to different keys.
 	ErrAmbiguousValue = errors.NewKind("map has ambiguous value %v")
 	// ErrUnusedField is returned when a transformation is not defined as partial, but does not process a specific key
@@ -54,6 +57,15 @@ var (
 	ErrDuplicateField = errors.NewKind("duplicate field: %v")
 	// ErrUndefinedField is returned when trying to create an object with a field that is not defined in the type spec.
 	ErrUndefinedField = errors.NewKind("undefined field: %v")
+	// ErrConstructOnly is returned when an operation that can only construct nodes, like OpFunc, is used to check them.
+	ErrConstructOnly = errors.NewKind("operation can only be used for construction: %T")
+	// ErrNegativePosition is returned by ShiftLineColOpt when a line or column becomes negative after the shift.
+	ErrNegativePosition = errors.NewKind("%s is negative after the shift: %d")
+	// ErrRuleConflict is returned by RuleSet when the same node is matched by multiple rules and the set
+	// is configured to report conflicts.
+	ErrRuleConflict = errors.NewKind("node is matched by rules %q and %q")
+	// ErrUnannotatedTypes is returned by RequireAnnotated when the tree contains objects of types that have no roles.
+	ErrUnannotatedTypes = errors.NewKind("types without roles: %q")
 
 	errAnd     = errors.NewKind("op %d (%T)")
 	errKey     = errors.NewKind("key %q")
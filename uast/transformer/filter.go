@@ -0,0 +1,204 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var (
+	_ Transformer = keepTypes{}
+	_ Transformer = dropTypes{}
+)
+
+// typeSet converts a list of types to a set.
+func typeSet(types []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(types))
+	for _, typ := range types {
+		m[typ] = struct{}{}
+	}
+	return m
+}
+
+// KeepTypes is an irreversible transformation that reduces the tree to objects of given types and their ancestors.
+// Objects of given types are kept with all their fields. Ancestors are kept with their type, token, roles and
+// positions, but only the fields that lead to the kept objects. Array elements that do not lead to the kept objects
+// are removed as well.
+//
+// The transformation returns nil if the tree contains no objects of given types.
+func KeepTypes(types []string) Transformer {
+	return keepTypes{types: typeSet(types)}
+}
+
+type keepTypes struct {
+	types map[string]struct{}
+}
+
+// Do implements Transformer.
+func (t keepTypes) Do(root nodes.Node) (nodes.Node, error) {
+	out, _ := t.keep(root)
+	return out, nil
+}
+
+// keep returns a pruned copy of the node and reports if it leads to any of the kept objects.
+func (t keepTypes) keep(n nodes.Node) (nodes.Node, bool) {
+	switch n := n.(type) {
+	case nodes.Object:
+		if _, ok := t.types[uast.TypeOf(n)]; ok {
+			return n, true
+		}
+		var (
+			out   = make(nodes.Object)
+			found bool
+		)
+		for k, v := range n {
+			switch k {
+			case uast.KeyType, uast.KeyToken, uast.KeyRoles, uast.KeyPos:
+				out[k] = v
+				continue
+			}
+			if nv, ok := t.keep(v); ok {
+				out[k] = nv
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		return out, true
+	case nodes.Array:
+		var out nodes.Array
+		for _, v := range n {
+			if nv, ok := t.keep(v); ok {
+				out = append(out, nv)
+			}
+		}
+		if len(out) == 0 {
+			return nil, false
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// DropTypesOptions controls the behavior of DropTypes.
+type DropTypesOptions struct {
+	// Reparent enables moving the descendants of removed objects to their parent.
+	// By default, removed objects are dropped together with their subtree.
+	Reparent bool
+}
+
+// DropTypes is an irreversible transformation that removes objects of given types from the tree.
+//
+// By default, the whole subtree of the removed object is dropped. Fields that contained removed objects
+// are removed, and so are the array elements.
+//
+// If the reparenting is enabled, the top-most descendant objects of the removed one are moved to its place.
+// Arrays elements are replaced with the list of such descendants and fields are set to a single node if only
+// one descendant survived, or to an array of them otherwise. As in KeepSemantic, descendants are ordered by
+// the start offset if all of them have positional information, otherwise by the field name of the removed object.
+// Values stored in fields of the removed object are dropped.
+//
+// If the root is removed, the transformation returns an array of surviving nodes, a single node if there
+// is only one of them, or nil if there are none.
+func DropTypes(types []string, opt DropTypesOptions) Transformer {
+	return dropTypes{types: typeSet(types), reparent: opt.Reparent}
+}
+
+type dropTypes struct {
+	types    map[string]struct{}
+	reparent bool
+}
+
+// Do implements Transformer.
+func (t dropTypes) Do(root nodes.Node) (nodes.Node, error) {
+	list := t.drop(root)
+	switch len(list) {
+	case 0:
+		return nil, nil
+	case 1:
+		return list[0], nil
+	}
+	return nodes.Array(list), nil
+}
+
+// drop returns a list of nodes that replace a given node. Unchanged nodes are returned as-is.
+func (t dropTypes) drop(n nodes.Node) []nodes.Node {
+	switch n := n.(type) {
+	case nodes.Object:
+		_, remove := t.types[uast.TypeOf(n)]
+		if remove && !t.reparent {
+			return nil
+		}
+		var out nodes.Object
+		for _, k := range n.Keys() {
+			if k == uast.KeyPos {
+				continue
+			}
+			v := n[k]
+			list := t.drop(v)
+			if len(list) == 1 && nodes.Same(list[0], v) {
+				continue
+			}
+			if out == nil {
+				out = n.CloneObject()
+			}
+			switch len(list) {
+			case 0:
+				delete(out, k)
+			case 1:
+				out[k] = list[0]
+			default:
+				out[k] = nodes.Array(list)
+			}
+		}
+		if out == nil {
+			out = n
+		}
+		if remove {
+			return objectChildren(out)
+		}
+		return []nodes.Node{out}
+	case nodes.Array:
+		var (
+			out     = make(nodes.Array, 0, len(n))
+			changed bool
+		)
+		for _, v := range n {
+			list := t.drop(v)
+			if len(list) != 1 || !nodes.Same(list[0], v) {
+				changed = true
+			}
+			out = append(out, list...)
+		}
+		if !changed {
+			return []nodes.Node{n}
+		}
+		return []nodes.Node{out}
+	}
+	return []nodes.Node{n}
+}
+
+// objectChildren returns the top-most descendant objects of a given object.
+func objectChildren(obj nodes.Object) []nodes.Node {
+	var out []nodes.Node
+	var collect func(n nodes.Node)
+	collect = func(n nodes.Node) {
+		switch n := n.(type) {
+		case nodes.Object:
+			out = append(out, n)
+		case nodes.Array:
+			for _, v := range n {
+				collect(v)
+			}
+		}
+	}
+	for _, k := range obj.Keys() {
+		switch k {
+		case uast.KeyType, uast.KeyToken, uast.KeyRoles, uast.KeyPos:
+			continue
+		}
+		collect(obj[k])
+	}
+	sortByStart(out)
+	return out
+}
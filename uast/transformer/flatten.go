@@ -0,0 +1,58 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// FlattenArray is an irreversible transformation that flattens one level of nested arrays in a given field
+// of each object. It is useful for native ASTs that group statements or declarations into nested lists.
+//
+// For example, [[a, b], [c]] becomes [a, b, c]. Elements that are not arrays are kept as-is, thus
+// [[a, b], c] becomes [a, b, c] as well. Fields that are not arrays are ignored. See FlattenArrayStrict
+// for a variant that returns an error instead.
+func FlattenArray(field string) TransformObjFunc {
+	return flattenArray(field, false)
+}
+
+// FlattenArrayStrict is like FlattenArray, but returns an error if the field is not an array of arrays.
+func FlattenArrayStrict(field string) TransformObjFunc {
+	return flattenArray(field, true)
+}
+
+func flattenArray(field string, strict bool) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		v, ok := n[field]
+		if !ok {
+			return n, false, nil
+		}
+		arr, ok := v.(nodes.Array)
+		if !ok {
+			if strict {
+				return n, false, ErrExpectedList.New(v)
+			}
+			return n, false, nil
+		}
+		var (
+			out     = make(nodes.Array, 0, len(arr))
+			changed bool
+		)
+		for _, e := range arr {
+			sub, ok := e.(nodes.Array)
+			if !ok {
+				if strict {
+					return n, false, ErrExpectedList.New(e)
+				}
+				out = append(out, e)
+				continue
+			}
+			changed = true
+			out = append(out, sub...)
+		}
+		if !changed {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		n[field] = out
+		return n, true, nil
+	})
+}
@@ -0,0 +1,54 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ Transformer = assignIDs{}
+
+// AssignIDsOptions controls the behavior of AssignIDsOpt.
+type AssignIDsOptions struct {
+	// Overwrite allows to replace existing values of the ID field.
+	Overwrite bool
+}
+
+// AssignIDs is an irreversible transformation that stores a sequential integer ID in a given field of each
+// object in the tree. IDs are assigned in pre-order, starting from zero for the root, and the fields of each
+// object are visited in the sorted order. Thus, identical trees always receive identical IDs.
+//
+// Objects stored in positional information fields (see uast.KeyPos) are not numbered. If the same object is
+// stored in multiple places in the tree, each occurrence receives a separate ID.
+//
+// Existing values of the field are preserved, but the object still consumes its ID in the sequence.
+// See AssignIDsOpt to overwrite them.
+func AssignIDs(key string) Transformer {
+	return AssignIDsOpt(key, AssignIDsOptions{})
+}
+
+// AssignIDsOpt is like AssignIDs, but allows to overwrite existing IDs.
+func AssignIDsOpt(key string, opt AssignIDsOptions) Transformer {
+	return assignIDs{key: key, opt: opt}
+}
+
+type assignIDs struct {
+	key string
+	opt AssignIDsOptions
+}
+
+// Do implements Transformer.
+func (t assignIDs) Do(root nodes.Node) (nodes.Node, error) {
+	var id int64
+	return rebuildPreOrder(root, func(n nodes.Object) (nodes.Object, rebuildFunc) {
+		cur := nodes.Int(id)
+		id++
+		if old, ok := n[t.key]; ok && (!t.opt.Overwrite || nodes.NodeEqual(old, cur)) {
+			return nil, nil
+		}
+		out := n.CloneObject()
+		out[t.key] = cur
+		return out, nil
+	}, func(k string) bool {
+		return k == uast.KeyPos || k == t.key
+	}), nil
+}
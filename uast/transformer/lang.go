@@ -0,0 +1,31 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ Transformer = setLanguage("")
+
+// SetLanguage is an irreversible transformation that stores the source language in the root object of the tree
+// (see uast.KeyLang). Drivers can add it to the pipeline with their own language. It returns ErrExpectedObject
+// if the root is not an object.
+func SetLanguage(lang string) Transformer {
+	return setLanguage(lang)
+}
+
+type setLanguage string
+
+// Do implements Transformer.
+func (t setLanguage) Do(root nodes.Node) (nodes.Node, error) {
+	if root == nil {
+		return nil, nil
+	}
+	obj, ok := root.(nodes.Object)
+	if !ok {
+		return nil, ErrExpectedObject.New(root)
+	}
+	obj = obj.CloneObject()
+	obj[uast.KeyLang] = nodes.String(t)
+	return obj, nil
+}
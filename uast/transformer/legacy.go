@@ -1,13 +1,13 @@
 package transformer
 
 import (
+	"sort"
+
 	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
 	"github.com/bblfsh/sdk/v3/uast/role"
 )
 
-const dedupCloneObj = false
-
 var _ Transformer = ResponseMetadata{}
 
 // ResponseMetadata is a transformation that is applied to the root of AST tree to trim any metadata that might be there.
@@ -117,7 +117,19 @@ func (n ObjectToNode) Mapping() Mapping {
 }
 
 // RolesDedup is an irreversible transformation that removes duplicate roles from AST nodes.
+//
+// The order of roles is preserved: only the first occurrence of each role is kept. See RolesDedupSorted
+// for a variant that sorts roles.
 func RolesDedup() TransformFunc {
+	return rolesDedup(false)
+}
+
+// RolesDedupSorted is like RolesDedup, but also sorts roles by their numeric value.
+func RolesDedupSorted() TransformFunc {
+	return rolesDedup(true)
+}
+
+func rolesDedup(sorted bool) TransformFunc {
 	return TransformFunc(func(n nodes.Node) (nodes.Node, bool, error) {
 		obj, ok := n.(nodes.Object)
 		if !ok {
@@ -136,13 +148,16 @@ func RolesDedup() TransformFunc {
 			m[r] = struct{}{}
 			out = append(out, r)
 		}
-		if len(out) == len(roles) {
-			return n, false, nil
+		changed := len(out) != len(roles)
+		if sorted && !sort.SliceIsSorted(out, func(i, j int) bool { return out[i] < out[j] }) {
+			sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+			changed = true
 		}
-		if dedupCloneObj {
-			obj = obj.CloneObject()
+		if !changed {
+			return n, false, nil
 		}
+		obj = obj.CloneObject()
 		obj[uast.KeyRoles] = uast.RoleList(out...)
-		return obj, dedupCloneObj, nil
+		return obj, true, nil
 	})
 }
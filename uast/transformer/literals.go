@@ -0,0 +1,172 @@
+package transformer
+
+import (
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// CoalesceLiterals is an irreversible transformation that merges adjacent literal fragments of a given type into
+// a single node. It is useful for languages that split a single literal into parts, for example because of
+// implicit string concatenation.
+//
+// Fragments are merged only if they are consecutive elements of the same array and the end offset of each
+// fragment is equal to the start offset of the next one. The merged node is a copy of the first fragment with
+// a concatenated token and a position range that covers all fragments.
+func CoalesceLiterals(typ string) TransformFunc {
+	return TransformFunc(func(n nodes.Node) (nodes.Node, bool, error) {
+		arr, ok := n.(nodes.Array)
+		if !ok || len(arr) < 2 {
+			return n, false, nil
+		}
+		var (
+			out     nodes.Array
+			changed bool
+		)
+		for i := 0; i < len(arr); {
+			j := i + 1
+			for j < len(arr) && isLiteralFragment(typ, arr[j-1]) && isLiteralFragment(typ, arr[j]) &&
+				fragmentsAdjacent(arr[j-1].(nodes.Object), arr[j].(nodes.Object)) {
+				j++
+			}
+			if j-i == 1 {
+				out = append(out, arr[i])
+				i = j
+				continue
+			}
+			changed = true
+			out = append(out, mergeFragments(arr[i:j]))
+			i = j
+		}
+		if !changed {
+			return n, false, nil
+		}
+		return out, true, nil
+	})
+}
+
+// isLiteralFragment checks if the node is an object of a given type that has a token.
+func isLiteralFragment(typ string, n nodes.Node) bool {
+	obj, ok := n.(nodes.Object)
+	if !ok || uast.TypeOf(obj) != typ {
+		return false
+	}
+	_, ok = obj[uast.KeyToken].(nodes.Value)
+	return ok
+}
+
+// fragmentsAdjacent checks if the second node starts exactly at the offset where the first one ends.
+func fragmentsAdjacent(n1, n2 nodes.Object) bool {
+	end := uast.PositionsOf(n1).End()
+	start := uast.PositionsOf(n2).Start()
+	if end == nil || start == nil || !end.HasOffset() || !start.HasOffset() {
+		return false
+	}
+	return end.Offset == start.Offset
+}
+
+// mergeFragments merges adjacent literal fragments into a single node.
+func mergeFragments(arr nodes.Array) nodes.Object {
+	first, last := arr[0].(nodes.Object), arr[len(arr)-1].(nodes.Object)
+	var buf strings.Builder
+	for _, n := range arr {
+		buf.WriteString(uast.TokenOf(n))
+	}
+	out := first.CloneObject()
+	out[uast.KeyToken] = nodes.String(buf.String())
+	out[uast.KeyPos] = uast.Positions{
+		uast.KeyStart: *uast.PositionsOf(first).Start(),
+		uast.KeyEnd:   *uast.PositionsOf(last).End(),
+	}.ToObject()
+	return out
+}
+
+// KeywordLiteral is a canonical form of a keyword literal.
+type KeywordLiteral int
+
+const (
+	// KeywordFalse is a false boolean literal.
+	KeywordFalse KeywordLiteral = iota + 1
+	// KeywordTrue is a true boolean literal.
+	KeywordTrue
+	// KeywordNull is a null literal (null, nil, None, etc).
+	KeywordNull
+)
+
+// KeywordLiterals is a table of keyword literals used by NormalizeKeywordLiterals.
+// It maps a native node type to a map from the node token to a canonical literal.
+//
+// The table is defined per driver, since the same token can be a keyword in one language
+// and a regular identifier in another.
+type KeywordLiterals map[string]map[string]KeywordLiteral
+
+// NormalizeKeywordLiterals is an irreversible transformation that rewrites native keyword literals to a canonical
+// UAST shape. Nodes are matched by their type and token using the table. Tokens are case-sensitive, see
+// NormalizeKeywordLiteralsFold for a case-insensitive variant.
+//
+// Boolean literals are converted to uast:Bool nodes with Literal and Boolean roles. UAST has no type for null
+// literals, thus they keep the native type and token, but get Literal and Null roles. In both cases the position
+// of the native node is preserved and all other fields are dropped.
+func NormalizeKeywordLiterals(table KeywordLiterals) TransformObjFunc {
+	return normalizeKeywords(table, false)
+}
+
+// NormalizeKeywordLiteralsFold is like NormalizeKeywordLiterals, but matches tokens case-insensitively.
+// It is useful for languages with case-insensitive keywords.
+func NormalizeKeywordLiteralsFold(table KeywordLiterals) TransformObjFunc {
+	return normalizeKeywords(table, true)
+}
+
+func normalizeKeywords(table KeywordLiterals, fold bool) TransformObjFunc {
+	if fold {
+		folded := make(KeywordLiterals, len(table))
+		for typ, toks := range table {
+			m := make(map[string]KeywordLiteral, len(toks))
+			for tok, kw := range toks {
+				m[strings.ToLower(tok)] = kw
+			}
+			folded[typ] = m
+		}
+		table = folded
+	}
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		toks, ok := table[uast.TypeOf(n)]
+		if !ok {
+			return n, false, nil
+		}
+		tok, ok := n[uast.KeyToken].(nodes.String)
+		if !ok {
+			return n, false, nil
+		}
+		if fold {
+			tok = nodes.String(strings.ToLower(string(tok)))
+		}
+		kw, ok := toks[string(tok)]
+		if !ok {
+			return n, false, nil
+		}
+		var out nodes.Object
+		switch kw {
+		case KeywordTrue, KeywordFalse:
+			out = nodes.Object{
+				uast.KeyType:  nodes.String(uast.TypeOf(uast.Bool{})),
+				uast.KeyRoles: uast.RoleList(role.Literal, role.Boolean),
+				"Value":       nodes.Bool(kw == KeywordTrue),
+			}
+		case KeywordNull:
+			out = nodes.Object{
+				uast.KeyType:  n[uast.KeyType],
+				uast.KeyToken: n[uast.KeyToken],
+				uast.KeyRoles: uast.RoleList(role.Literal, role.Null),
+			}
+		default:
+			return n, false, ErrUnexpectedValue.New(kw)
+		}
+		if pos, ok := n[uast.KeyPos]; ok {
+			out[uast.KeyPos] = pos
+		}
+		return out, true, nil
+	})
+}
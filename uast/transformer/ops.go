@@ -121,6 +121,34 @@ func (op opVar) Construct(st *State, n nodes.Node) (nodes.Node, error) {
 	return val, nil
 }
 
+var _ Op = OpFunc(nil)
+
+// OpFunc is a custom operation that constructs a node from the variables bound in the transformation state.
+// The function can read variables with State.GetVar or State.MustGetVar, for example to build a qualified
+// name from multiple variables.
+//
+// The operation can only be used for construction, and returns ErrConstructOnly when used to check nodes.
+// To make the mapping reversible, use AnyNode on the other side or provide a corresponding check operation.
+type OpFunc func(st *State) (nodes.Node, error)
+
+// Kinds implements Op.
+func (OpFunc) Kinds() nodes.Kind {
+	return nodes.KindsAny
+}
+
+// Check implements Op. It always returns ErrConstructOnly.
+func (op OpFunc) Check(st *State, n nodes.Node) (bool, error) {
+	return false, ErrConstructOnly.New(op)
+}
+
+// Construct implements Op.
+func (op OpFunc) Construct(st *State, n nodes.Node) (nodes.Node, error) {
+	if err := noNode(n); err != nil {
+		return nil, err
+	}
+	return op(st)
+}
+
 // AnyNode matches any node and throws it away. Reversal will create a node with create op.
 //
 // This operation should not be used thoughtlessly. Each field that is dropped this way
@@ -466,6 +494,10 @@ func constructObj(op ObjectOp, st *State, n nodes.Node) (nodes.Node, error) {
 
 // Part defines a partial transformation of an object.
 // All unused fields will be stored into variable with a specified name.
+//
+// On construction, all fields stored in the variable are copied to the new object as-is. It is an error if the
+// child operation sets a field with the same name as one of the stored fields. When used on both sides of Map,
+// the destination Part is bound to the variable of the source Part, thus names on both sides may differ.
 func Part(vr string, o ObjectOp) ObjectOp {
 	used, ok := o.Fields()
 	if !ok {
@@ -544,6 +576,64 @@ func (op *opPartialObj) ConstructObj(st *State, obj nodes.Object) (nodes.Object,
 	return obj, nil
 }
 
+// partVar returns the name of a variable used by the top-level partial operation, if any.
+func partVar(op Op) (string, bool) {
+	switch op := op.(type) {
+	case *opPartialObj:
+		return op.vr, true
+	case *opObjJoin:
+		if op.partial != nil {
+			return partVar(op.partial)
+		}
+	case *opCheck:
+		return partVar(op.op)
+	case *opCheckObj:
+		return partVar(op.op)
+	}
+	return "", false
+}
+
+// renamePart changes the variable name of the top-level partial operation.
+// Operations without a top-level partial are returned unchanged. Object operations stay object operations.
+func renamePart(op Op, vr string) Op {
+	switch op := op.(type) {
+	case *opPartialObj:
+		if op.vr == vr {
+			return op
+		}
+		return &opPartialObj{vr: vr, used: op.used, op: op.op}
+	case *opObjJoin:
+		if op.partial == nil {
+			return op
+		}
+		j := *op
+		j.partial = renamePart(op.partial, vr).(ObjectOp)
+		return &j
+	case *opCheck:
+		c := *op
+		c.op = renamePart(op.op, vr)
+		return &c
+	case *opCheckObj:
+		c := *op
+		c.op = renamePart(op.op, vr).(ObjectOp)
+		return &c
+	}
+	return op
+}
+
+// bindPart makes sure that the top-level partial operation in dst uses the same variable as the one in src.
+func bindPart(src, dst Op) Op {
+	svr, ok := partVar(src)
+	if !ok {
+		return dst
+	}
+	dvr, ok := partVar(dst)
+	if !ok || dvr == svr {
+		return dst
+	}
+	return renamePart(dst, svr)
+}
+
 // JoinObj will execute all object operations on a specific object in a sequence.
 func JoinObj(ops ...ObjectOp) ObjectOp {
 	if len(ops) == 0 {
@@ -667,6 +667,61 @@ var opsCases = []struct {
 		},
 		exp: arrObjVal("v2", un.String(`a"b`)),
 	},
+	{
+		name: "string to int",
+		inp:  arrObjStr("v", "42"),
+		src: Obj{
+			"v": StringToInt(Var("x")),
+		},
+		dst: Obj{
+			"v2": Var("x"),
+		},
+		exp: arrObjInt("v2", 42),
+	},
+	{
+		name: "string to int (malformed)",
+		inp:  arrObjStr("v", "4x2"),
+		src: Obj{
+			"v": StringToInt(Var("x")),
+		},
+		dst: Obj{
+			"v2": Var("x"),
+		},
+		err: ErrInvalidConversion,
+	},
+	{
+		name: "int to string",
+		inp:  arrObjInt("v", -7),
+		src: Obj{
+			"v": IntToString(Var("x")),
+		},
+		dst: Obj{
+			"v2": Var("x"),
+		},
+		exp: arrObjStr("v2", "-7"),
+	},
+	{
+		name: "cast",
+		inp:  arrObjVal("v", un.Float(3)),
+		src: Obj{
+			"v": Cast(un.KindFloat, un.KindUint, Var("x")),
+		},
+		dst: Obj{
+			"v2": Var("x"),
+		},
+		exp: arrObjVal("v2", un.Uint(3)),
+	},
+	{
+		name: "cast (not integral)",
+		inp:  arrObjVal("v", un.Float(3.5)),
+		src: Obj{
+			"v": Cast(un.KindFloat, un.KindInt, Var("x")),
+		},
+		dst: Obj{
+			"v2": Var("x"),
+		},
+		err: ErrInvalidConversion,
+	},
 	{
 		name: "semantic",
 		inp: func() un.Node {
@@ -0,0 +1,147 @@
+package transformer
+
+import (
+	"sort"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+var (
+	_ Transformer = annotateByField{}
+	_ RolesLister = annotateByField{}
+)
+
+// AnnotateByParentField is an irreversible transformation that assigns roles to objects based on the name
+// of the field of the parent object they are stored in. For example, a table that maps "body" to role.Body
+// annotates all objects stored in "body" fields, including objects in arrays stored in those fields.
+//
+// If the same object is stored in multiple fields of the tree, it receives roles from all of them, and the
+// annotated object is still shared by all those fields.
+func AnnotateByParentField(table map[string]role.Role) Transformer {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return annotateByField{table: table, keys: keys}
+}
+
+type annotateByField struct {
+	table map[string]role.Role
+	keys  []string // sorted keys of the table
+}
+
+// ListRoles implements RolesLister.
+func (t annotateByField) ListRoles() ([]role.Role, bool) {
+	out := make([]role.Role, 0, len(t.keys))
+	for _, k := range t.keys {
+		out = append(out, t.table[k])
+	}
+	return out, false
+}
+
+// Do implements Transformer.
+func (t annotateByField) Do(root nodes.Node) (nodes.Node, error) {
+	// collect roles for each object by its identity first, since objects may be shared
+	roles := make(map[nodes.Comparable][]role.Role)
+	nodes.WalkPreOrder(root, func(n nodes.Node) bool {
+		obj, ok := n.(nodes.Object)
+		if !ok {
+			return true
+		}
+		for _, k := range t.keys {
+			v, ok := obj[k]
+			if !ok {
+				continue
+			}
+			r := t.table[k]
+			eachObjectIn(v, func(o nodes.Object) {
+				key := nodes.UniqueKey(o)
+				roles[key] = append(roles[key], r)
+			})
+		}
+		return true
+	})
+	if len(roles) == 0 {
+		return root, nil
+	}
+	// the order of fields in the tree traversal is not defined
+	for _, rs := range roles {
+		sort.Slice(rs, func(i, j int) bool {
+			return rs[i] < rs[j]
+		})
+	}
+	a := &fieldRolesApplier{roles: roles, done: make(map[nodes.Comparable]nodes.Node)}
+	out, _ := a.apply(root)
+	return out, nil
+}
+
+// eachObjectIn calls fnc for the object, or for each object in the array, including nested arrays.
+func eachObjectIn(n nodes.Node, fnc func(o nodes.Object)) {
+	switch n := n.(type) {
+	case nodes.Object:
+		fnc(n)
+	case nodes.Array:
+		for _, v := range n {
+			eachObjectIn(v, fnc)
+		}
+	}
+}
+
+// fieldRolesApplier adds collected roles to objects, preserving object sharing.
+type fieldRolesApplier struct {
+	roles map[nodes.Comparable][]role.Role
+	// done maps the original composite nodes to the updated ones
+	done map[nodes.Comparable]nodes.Node
+}
+
+func (a *fieldRolesApplier) apply(n nodes.Node) (nodes.Node, bool) {
+	switch n := n.(type) {
+	case nodes.Object:
+		key := nodes.UniqueKey(n)
+		if out, ok := a.done[key]; ok {
+			return out, true
+		}
+		var out nodes.Object
+		for k, v := range n {
+			nv, changed := a.apply(v)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = n.CloneObject()
+			}
+			out[k] = nv
+		}
+		if rs := a.roles[key]; len(rs) != 0 {
+			if out == nil {
+				out = n.CloneObject()
+			}
+			out[uast.KeyRoles] = appendRoles(out[uast.KeyRoles], rs)
+		}
+		if out == nil {
+			return n, false
+		}
+		a.done[key] = out
+		return out, true
+	case nodes.Array:
+		var out nodes.Array
+		for i, v := range n {
+			nv, changed := a.apply(v)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = n.CloneList()
+			}
+			out[i] = nv
+		}
+		if out == nil {
+			return n, false
+		}
+		return out, true
+	}
+	return n, false
+}
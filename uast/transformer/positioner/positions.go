@@ -15,8 +15,6 @@ import (
 
 var _ transformer.CodeTransformer = Positioner{}
 
-const cloneObj = false
-
 var (
 	errNoUnicodeIndex = errors.New("unicode index is disabled")
 )
@@ -61,13 +59,11 @@ func (t Positioner) OnCode(code string) transformer.Transformer {
 		if err := t.method(idx, pos); err != nil {
 			return o, false, err
 		}
-		if cloneObj {
-			o = o.CloneObject()
-		}
+		o = o.CloneObject()
 		for k, v := range pos.ToObject() {
 			o[k] = v
 		}
-		return o, cloneObj, nil
+		return o, true, nil
 	})
 }
 
@@ -393,7 +389,7 @@ func (idx *Index) toUnicodeOffset(offset int, isUTF16 bool) (int, error) {
 	})
 	s := idx.spans[i-1]
 	if isUTF16 {
-		return s.firstUTF16Ind + (offset-s.byteOff)/s.runeSize16, nil
+		return s.firstUTF16Ind + s.runeSize16*((offset-s.byteOff)/s.runeSize8), nil
 	}
 	return s.firstRuneInd + (offset-s.byteOff)/s.runeSize8, nil
 }
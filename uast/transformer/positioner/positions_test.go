@@ -284,3 +284,71 @@ a4`
 		})
 	}
 }
+
+func TestRemapOffsets(t *testing.T) {
+	// 2-byte runes (including a combining character), a surrogate pair, and ASCII
+	const source = "ё\u00e9\u0301😀x\nb"
+	offsets := map[Unit][]int{
+		UnitByte:  {0, 2, 4, 6, 10, 11, 12, 13},
+		UnitRune:  {0, 1, 2, 3, 4, 5, 6, 7},
+		UnitUTF16: {0, 1, 2, 3, 5, 6, 7, 8},
+	}
+	// columns are measured in the same unit as offsets
+	lines := []int{1, 1, 1, 1, 1, 1, 2, 2}
+	cols := map[Unit][]int{
+		UnitByte:  {1, 3, 5, 7, 11, 12, 1, 2},
+		UnitRune:  {1, 2, 3, 4, 5, 6, 1, 2},
+		UnitUTF16: {1, 2, 3, 4, 6, 7, 1, 2},
+	}
+	tree := func(u Unit) nodes.Object {
+		var arr, full nodes.Array
+		for i, off := range offsets[u] {
+			arr = append(arr, offset(off))
+			full = append(full, fullPos(off, lines[i], cols[u][i]))
+		}
+		root := nodes.Object{"positions": arr, "full": full}
+		SetOffsetUnit(root, u)
+		return root
+	}
+	units := []Unit{UnitByte, UnitRune, UnitUTF16}
+	for _, from := range units {
+		for _, to := range units {
+			from, to := from, to
+			t.Run(from.String()+"-"+to.String(), func(t *testing.T) {
+				out, err := RemapOffsets(from, to, []byte(source)).Do(tree(from))
+				require.NoError(t, err)
+				require.Equal(t, tree(to), out)
+
+				u, err := OffsetUnitOf(out)
+				require.NoError(t, err)
+				require.Equal(t, to, u)
+			})
+		}
+	}
+
+	_, err := RemapOffsets(UnitRune, UnitByte, []byte(source)).Do(tree(UnitUTF16))
+	require.Error(t, err)
+}
+
+func TestPositionerImmutable(t *testing.T) {
+	const source = "ё\u00e9\u0301😀x\nb"
+	inp := nodes.Object{
+		"a": offset(2),
+		"b": nodes.Array{offset(3), offset(5)},
+	}
+	SetOffsetUnit(inp, UnitUTF16)
+	orig := inp.Clone()
+	tr := FromUTF16Offset().OnCode(source)
+
+	out1, err := tr.Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, orig, inp)
+
+	out2, err := tr.Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, out1, out2)
+
+	_, err = RemapOffsets(UnitUTF16, UnitRune, []byte(source)).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, orig, inp)
+}
@@ -99,28 +99,20 @@ type tokenFromSource struct {
 
 // Do implements transformer.Transformer. See TokenFromSource.
 func (t *tokenFromSource) Do(root nodes.Node) (nodes.Node, error) {
-	var last error
-	nodes.WalkPreOrder(root, func(node nodes.Node) bool {
-		if last != nil {
-			return false
-		}
+	return transformer.TransformFunc(func(node nodes.Node) (nodes.Node, bool, error) {
 		obj, ok := t.filterObj(node)
 		if !ok {
-			// skip node, but recurse to children
-			return true
+			return node, false, nil
 		}
 		token, ok, err := t.tokenFromPos(obj)
-		if err != nil {
-			last = err
-			return false
-		} else if !ok {
-			return true // recurse
+		if err != nil || !ok {
+			return node, false, err
 		}
 		// it won't be nil, since we require both token and pos fields to exist
+		obj = obj.CloneObject()
 		obj[t.tokenKey] = nodes.String(token)
-		return true
-	})
-	return root, last
+		return obj, true, nil
+	}).Do(root)
 }
 
 // VerifyToken check that node's token matches its positional information.
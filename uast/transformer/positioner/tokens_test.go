@@ -154,10 +154,13 @@ func TestTokenFromSource(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			root := c.ast
+			orig := root.Clone()
 			tr := c.conf.OnCode(c.source)
-			got, err := tr.Do(root.Clone())
+			got, err := tr.Do(root)
 			require.NoError(t, err)
 			require.Equal(t, c.exp, got)
+			// input must not be modified
+			require.Equal(t, orig, root)
 		})
 	}
 }
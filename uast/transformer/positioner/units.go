@@ -0,0 +1,178 @@
+package positioner
+
+import (
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/transformer"
+)
+
+// KeyOffsetUnit is a field of the root object that stores the unit of position offsets in the tree.
+//
+// Trees without this field are assumed to use byte offsets.
+const KeyOffsetUnit = "@offset_unit"
+
+// Unit is a unit of position offsets.
+type Unit int
+
+const (
+	// UnitByte is an offset in bytes of the UTF-8 source.
+	UnitByte Unit = iota
+	// UnitRune is an offset in Unicode code points.
+	UnitRune
+	// UnitUTF16 is an offset in UTF-16 code units.
+	UnitUTF16
+)
+
+var unitNames = []string{
+	UnitByte:  "byte",
+	UnitRune:  "rune",
+	UnitUTF16: "utf16",
+}
+
+func (u Unit) String() string {
+	if u < 0 || int(u) >= len(unitNames) {
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+	return unitNames[u]
+}
+
+// ParseUnit parses the name of the offset unit, as returned by Unit.String.
+func ParseUnit(s string) (Unit, error) {
+	for i, name := range unitNames {
+		if name == s {
+			return Unit(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown offset unit: %q", s)
+}
+
+// SetOffsetUnit tags the root object with the unit of position offsets used in the tree.
+func SetOffsetUnit(root nodes.Object, u Unit) {
+	if u == UnitByte {
+		delete(root, KeyOffsetUnit)
+		return
+	}
+	root[KeyOffsetUnit] = nodes.String(u.String())
+}
+
+// OffsetUnitOf returns the unit of position offsets the tree is tagged with.
+// It returns UnitByte if the tree is not tagged.
+func OffsetUnitOf(root nodes.Node) (Unit, error) {
+	obj, ok := root.(nodes.Object)
+	if !ok {
+		return UnitByte, nil
+	}
+	v, ok := obj[KeyOffsetUnit]
+	if !ok {
+		return UnitByte, nil
+	}
+	s, ok := v.(nodes.String)
+	if !ok {
+		return UnitByte, fmt.Errorf("unexpected offset unit: %v", v)
+	}
+	return ParseUnit(string(s))
+}
+
+var _ transformer.Transformer = offsetRemap{}
+
+// RemapOffsets creates a transformation that converts offsets of all positions in the tree from one unit to another.
+// Columns are measured in the same unit as offsets, thus if the position has a line or a column, both of them are
+// recomputed from the offset. Positions without a line and a column only get a new offset.
+//
+// If the root object is tagged with an offset unit, it must match the source unit, and the tag will be updated.
+func RemapOffsets(from, to Unit, source []byte) transformer.Transformer {
+	return offsetRemap{from: from, to: to, source: source}
+}
+
+type offsetRemap struct {
+	from, to Unit
+	source   []byte
+}
+
+func (t offsetRemap) toByte(idx *Index, off int) (int, error) {
+	switch t.from {
+	case UnitByte:
+		return off, nil
+	case UnitRune:
+		return idx.FromRuneOffset(off)
+	case UnitUTF16:
+		return idx.FromUTF16Offset(off)
+	}
+	return -1, fmt.Errorf("unsupported offset unit: %v", t.from)
+}
+
+func (t offsetRemap) fromByte(idx *Index, off int) (int, error) {
+	switch t.to {
+	case UnitByte:
+		return off, nil
+	case UnitRune:
+		return idx.ToRuneOffset(off)
+	case UnitUTF16:
+		return idx.ToUTF16Offset(off)
+	}
+	return -1, fmt.Errorf("unsupported offset unit: %v", t.to)
+}
+
+// lineCol returns a one-based line and column given a byte offset, with the column measured in the target unit.
+func (t offsetRemap) lineCol(idx *Index, off int) (int, int, error) {
+	switch t.to {
+	case UnitByte:
+		return idx.LineCol(off)
+	case UnitRune:
+		return idx.ToUnicodeLineCol(off)
+	case UnitUTF16:
+		return idx.ToUTF16LineCol(off)
+	}
+	return -1, -1, fmt.Errorf("unsupported offset unit: %v", t.to)
+}
+
+// Do implements transformer.Transformer.
+func (t offsetRemap) Do(root nodes.Node) (nodes.Node, error) {
+	if u, err := OffsetUnitOf(root); err != nil {
+		return nil, err
+	} else if u != t.from {
+		return nil, fmt.Errorf("tree uses %v offsets, expected %v", u, t.from)
+	}
+	if t.from == t.to {
+		return root, nil
+	}
+	idx := NewIndex(t.source, &IndexOptions{Unicode: true})
+	root, err := transformer.TransformObjFunc(func(o nodes.Object) (nodes.Object, bool, error) {
+		pos := uast.AsPosition(o)
+		if pos == nil {
+			return o, false, nil
+		}
+		boff, err := t.toByte(idx, int(pos.Offset))
+		if err != nil {
+			return o, false, err
+		}
+		off, err := t.fromByte(idx, boff)
+		if err != nil {
+			return o, false, err
+		}
+		pos.Offset = uint32(off)
+		if pos.Line != 0 || pos.Col != 0 {
+			line, col, err := t.lineCol(idx, boff)
+			if err != nil {
+				return o, false, err
+			}
+			pos.Line, pos.Col = uint32(line), uint32(col)
+		}
+		o = o.CloneObject()
+		for k, v := range pos.ToObject() {
+			o[k] = v
+		}
+		return o, true, nil
+	}).Do(root)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := root.(nodes.Object); ok {
+		obj = obj.CloneObject()
+		SetOffsetUnit(obj, t.to)
+		root = obj
+	}
+	return root, nil
+}
@@ -0,0 +1,147 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// FillRootPositionFromChildren is an irreversible transformation that sets positions of objects that have none,
+// using positions of their descendants. The start position is set to the minimal start of all children and
+// the end position is set to the maximal end.
+//
+// The transformation runs in a single post-order pass, thus the positions assigned to the nested objects are
+// used to compute positions of their parents. Objects that already have positions and objects without any
+// positioned descendants are left unchanged.
+func FillRootPositionFromChildren() TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		switch uast.TypeOf(n) {
+		case uast.TypePosition, uast.TypePositions:
+			return n, false, nil
+		}
+		if len(uast.PositionsOf(n)) != 0 {
+			return n, false, nil
+		}
+		var start, end *uast.Position
+		for k, v := range n {
+			if k == uast.KeyPos {
+				continue
+			}
+			childrenSpan(v, &start, &end)
+		}
+		if start == nil && end == nil {
+			return n, false, nil
+		}
+		pos := make(uast.Positions, 2)
+		if start != nil {
+			pos[uast.KeyStart] = *start
+		}
+		if end != nil {
+			pos[uast.KeyEnd] = *end
+		}
+		n = n.CloneObject()
+		n[uast.KeyPos] = pos.ToObject()
+		return n, true, nil
+	})
+}
+
+// childrenSpan extends the [start, end] span with positions of a node. Arrays are inspected recursively,
+// while objects are expected to have positions already set by the post-order traversal.
+func childrenSpan(n nodes.Node, start, end **uast.Position) {
+	switch n := n.(type) {
+	case nodes.Array:
+		for _, v := range n {
+			childrenSpan(v, start, end)
+		}
+	case nodes.Object:
+		pos := uast.PositionsOf(n)
+		if s := pos.Start(); s != nil && s.Valid() && (*start == nil || s.Less(**start)) {
+			*start = s
+		}
+		if e := pos.End(); e != nil && e.Valid() && (*end == nil || (*end).Less(*e)) {
+			*end = e
+		}
+	}
+}
+
+// ShiftLineColOptions controls the behavior of ShiftLineColOpt.
+type ShiftLineColOptions struct {
+	// Strict causes the transformation to return ErrNegativePosition if a line or column becomes negative.
+	// By default, such values are clamped to zero.
+	Strict bool
+}
+
+// ToOneBased is an irreversible transformation that converts 0-based lines and columns in all positions
+// to 1-based ones. See ShiftLineCol.
+func ToOneBased() TransformObjFunc {
+	return ShiftLineCol(+1, +1)
+}
+
+// ToZeroBased is an irreversible transformation that converts 1-based lines and columns in all positions
+// to 0-based ones. See ShiftLineCol.
+func ToZeroBased() TransformObjFunc {
+	return ShiftLineCol(-1, -1)
+}
+
+// ShiftLineCol is an irreversible transformation that adds given deltas to lines and columns of all positions
+// in the tree. Offsets are not changed. Values that become negative are clamped to zero.
+//
+// Only the fields that are present in the position object are shifted. Note that a zero line or column of
+// 1-based positions indicates a missing value, while it is a valid value for 0-based positions. Thus,
+// the transformation is not reversible for positions that have no line-column information.
+//
+// See ShiftLineColOpt to fail on negative values instead.
+func ShiftLineCol(lineDelta, colDelta int) TransformObjFunc {
+	return ShiftLineColOpt(lineDelta, colDelta, ShiftLineColOptions{})
+}
+
+// ShiftLineColOpt is like ShiftLineCol, but allows to report negative values as errors.
+func ShiftLineColOpt(lineDelta, colDelta int, opt ShiftLineColOptions) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		if uast.TypeOf(n) != uast.TypePosition || (lineDelta == 0 && colDelta == 0) {
+			return n, false, nil
+		}
+		var out nodes.Object
+		for _, f := range []struct {
+			key   string
+			delta int
+		}{
+			{uast.KeyPosLine, lineDelta},
+			{uast.KeyPosCol, colDelta},
+		} {
+			if f.delta == 0 {
+				continue
+			}
+			var (
+				v   int64
+				typ nodes.Kind
+			)
+			switch val := n[f.key].(type) {
+			case nodes.Int:
+				v, typ = int64(val), nodes.KindInt
+			case nodes.Uint:
+				v, typ = int64(val), nodes.KindUint
+			default:
+				continue
+			}
+			v += int64(f.delta)
+			if v < 0 {
+				if opt.Strict {
+					return nil, false, ErrNegativePosition.New(f.key, v)
+				}
+				v = 0
+			}
+			if out == nil {
+				out = n.CloneObject()
+			}
+			if typ == nodes.KindInt {
+				out[f.key] = nodes.Int(v)
+			} else {
+				out[f.key] = nodes.Uint(v)
+			}
+		}
+		if out == nil {
+			return n, false, nil
+		}
+		return out, true, nil
+	})
+}
@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ Transformer = qualifiedNames{}
+
+// NameSegment describes how objects of a specific type contribute to qualified names.
+type NameSegment struct {
+	// Field is the name of the field that stores the name of the object. The field may contain a string,
+	// an object with a token, or an identifier object with the name stored in the "Name" field.
+	Field string
+	// Leaf disables the use of the name as a prefix for qualified names of nested objects.
+	// It should be set for declarations that do not introduce a named scope, e.g. variables.
+	Leaf bool
+}
+
+// QualifiedNamesOptions controls the behavior of ComputeQualifiedNames.
+type QualifiedNamesOptions struct {
+	// Key is the field name used to store qualified names. uast.KeyQualifiedName is used if not set.
+	Key string
+	// Separator is used to join name segments. A dot is used if not set.
+	Separator string
+	// Types maps object types to the name segments they contribute.
+	Types map[string]NameSegment
+}
+
+// ComputeQualifiedNames is an irreversible transformation that stores a best-effort qualified name on each object
+// of types listed in the options. The name is computed by joining the names of all enclosing objects of listed
+// types with the name of the object itself, e.g. "pkg.Type.method".
+//
+// Objects with a missing or empty name do not receive a qualified name and do not contribute to the names of
+// nested objects. Objects stored in positional information fields (see uast.KeyPos) are never visited.
+func ComputeQualifiedNames(opt QualifiedNamesOptions) Transformer {
+	if opt.Key == "" {
+		opt.Key = uast.KeyQualifiedName
+	}
+	if opt.Separator == "" {
+		opt.Separator = "."
+	}
+	return qualifiedNames{opt: opt}
+}
+
+type qualifiedNames struct {
+	opt QualifiedNamesOptions
+}
+
+// Do implements Transformer.
+func (t qualifiedNames) Do(root nodes.Node) (nodes.Node, error) {
+	return rebuildPreOrder(root, t.annotate(nil), t.skip), nil
+}
+
+func (t qualifiedNames) skip(k string) bool {
+	return k == uast.KeyPos || k == t.opt.Key
+}
+
+// annotate returns a function that stores qualified names on objects. The prefix is a list of name segments
+// of enclosing scopes.
+func (t qualifiedNames) annotate(prefix []string) rebuildFunc {
+	return func(n nodes.Object) (nodes.Object, rebuildFunc) {
+		seg, ok := t.opt.Types[uast.TypeOf(n)]
+		if !ok {
+			return nil, nil
+		}
+		name := segmentName(n[seg.Field])
+		if name == "" {
+			return nil, nil
+		}
+		names := append(prefix[:len(prefix):len(prefix)], name)
+		var out nodes.Object
+		qname := nodes.String(strings.Join(names, t.opt.Separator))
+		if old, ok := n[t.opt.Key]; !ok || !nodes.NodeEqual(old, qname) {
+			out = n.CloneObject()
+			out[t.opt.Key] = qname
+		}
+		if seg.Leaf {
+			return out, nil
+		}
+		return out, t.annotate(names)
+	}
+}
+
+// segmentName returns the name stored in the name field of an object.
+func segmentName(n nodes.Node) string {
+	switch n := n.(type) {
+	case nodes.String:
+		return string(n)
+	case nodes.Object:
+		if tok := uast.TokenOf(n); tok != "" {
+			return tok
+		}
+		if name, ok := n["Name"].(nodes.String); ok {
+			return string(name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/query"
+	"github.com/bblfsh/sdk/v3/uast/query/xpath"
+)
+
+var _ Transformer = (*replaceMatches)(nil)
+
+// ReplaceMatches creates a transformation that runs an XPath query on the tree and replaces each matched node
+// with the result of fn. The expression is compiled once and the function panics if it is invalid.
+//
+// Only objects and arrays can be replaced, since other nodes have no identity in the tree. The transformation
+// returns an error if the query matches values, attributes or returns a computed value.
+//
+// Nested matches are processed bottom-up: inner matches are replaced first, and fn receives the outer node
+// with all replacements already applied to its children.
+func ReplaceMatches(expr string, fn func(nodes.External) (nodes.Node, error)) Transformer {
+	q, err := xpath.New().Prepare(expr)
+	if err != nil {
+		panic(fmt.Errorf("invalid xpath expression %q: %v", expr, err))
+	}
+	return &replaceMatches{q: q, fn: fn}
+}
+
+type replaceMatches struct {
+	q  query.Query
+	fn func(nodes.External) (nodes.Node, error)
+}
+
+// Do implements Transformer.
+func (t *replaceMatches) Do(root nodes.Node) (nodes.Node, error) {
+	if root == nil {
+		return nil, nil
+	}
+	matched := make(map[nodes.Comparable]func(nodes.External) (nodes.Node, error))
+	err := matchNodes(t.q, root, func(key nodes.Comparable) error {
+		matched[key] = t.fn
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	} else if len(matched) == 0 {
+		return root, nil
+	}
+	return replaceMatched(root, matched)
+}
+
+// matchNodes runs the query on the tree and calls fn with the unique key of each matched node.
+// It returns an error if the query matches anything except objects and arrays.
+func matchNodes(q query.Query, root nodes.Node, fn func(key nodes.Comparable) error) error {
+	it, err := q.Execute(root)
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		if typ := query.MatchTypeOf(it); typ != query.MatchNode {
+			return fmt.Errorf("only node matches can be replaced, got match type %d", typ)
+		}
+		switch n := it.Node().(type) {
+		case nodes.Object, nodes.Array:
+			if err := fn(nodes.UniqueKey(n.(nodes.Node))); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("only objects and arrays can be replaced, got %T", n)
+		}
+	}
+	return nil
+}
+
+// replaceMatched rebuilds the subtree, calling the replacement function for each node from the matched set in post-order.
+func replaceMatched(n nodes.Node, matched map[nodes.Comparable]func(nodes.External) (nodes.Node, error)) (nodes.Node, error) {
+	var out nodes.Node
+	switch n := n.(type) {
+	case nodes.Object:
+		if n == nil {
+			return n, nil
+		}
+		m := make(nodes.Object, len(n))
+		for k, v := range n {
+			nv, err := replaceMatched(v, matched)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = nv
+		}
+		out = m
+	case nodes.Array:
+		if n == nil {
+			return n, nil
+		}
+		arr := make(nodes.Array, 0, len(n))
+		for _, v := range n {
+			nv, err := replaceMatched(v, matched)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, nv)
+		}
+		out = arr
+	default:
+		return n, nil
+	}
+	fn, ok := matched[nodes.UniqueKey(n)]
+	if !ok {
+		return out, nil
+	}
+	return fn(out)
+}
@@ -0,0 +1,90 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// KeyNativeType is a default field name used to store the original type of the node
+// by RenameTypesOpt and MapSemanticKeepType.
+const KeyNativeType = "@native-type"
+
+// RenameTypesOptions controls the behavior of RenameTypesOpt.
+type RenameTypesOptions struct {
+	// NativeTypeKey is a field name used to store the original type of renamed nodes, see KeyNativeType.
+	// The original type is not stored if the key is empty.
+	NativeTypeKey string
+}
+
+// RenameTypes is an irreversible transformation that changes the type of objects according to the table.
+// Keys of the table are the old types and values are the new ones.
+//
+// See RenameTypesOpt to keep the original type.
+func RenameTypes(table map[string]string) TransformObjFunc {
+	return RenameTypesOpt(table, RenameTypesOptions{})
+}
+
+// RenameTypesOpt is like RenameTypes, but allows to store the original type of the node.
+func RenameTypesOpt(table map[string]string, opt RenameTypesOptions) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		typ := uast.TypeOf(n)
+		if typ == "" {
+			return n, false, nil
+		}
+		ntyp, ok := table[typ]
+		if !ok || ntyp == typ {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		n[uast.KeyType] = nodes.String(ntyp)
+		if opt.NativeTypeKey != "" {
+			n[opt.NativeTypeKey] = nodes.String(typ)
+		}
+		return n, true, nil
+	})
+}
+
+// RenameFields is an irreversible transformation that renames fields of all objects according to the table.
+// Keys of the table are the old field names and values are the new ones. Values of the fields are not changed,
+// and all fields are renamed at once, thus the table may swap two fields.
+//
+// It panics if two fields are mapped to the same name. The transformation returns ErrDuplicateField if the new
+// name of the field collides with an existing field that is not renamed.
+func RenameFields(table map[string]string) TransformObjFunc {
+	targets := make(map[string]string, len(table))
+	for from, to := range table {
+		if prev, ok := targets[to]; ok {
+			if prev > from {
+				prev, from = from, prev
+			}
+			panic(ErrDuplicateField.New(fmt.Sprintf("%q and %q are renamed to %q", prev, from, to)))
+		}
+		targets[to] = from
+	}
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		changed := false
+		for k := range n {
+			if to, ok := table[k]; ok && to != k {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return n, false, nil
+		}
+		out := make(nodes.Object, len(n))
+		for k, v := range n {
+			if to, ok := table[k]; ok {
+				k = to
+			} else if from, ok := targets[k]; ok {
+				if _, ok := n[from]; ok {
+					return n, false, ErrDuplicateField.New(k)
+				}
+			}
+			out[k] = v
+		}
+		return out, true, nil
+	})
+}
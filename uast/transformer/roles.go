@@ -0,0 +1,111 @@
+package transformer
+
+import (
+	"sort"
+
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// RolesLister is an optional interface implemented by transformers and mappings that can report the roles
+// they assign without running them.
+type RolesLister interface {
+	// ListRoles returns all roles that a transformation may assign. Dynamic is set to true if some roles are
+	// computed at runtime, thus the list might be incomplete.
+	ListRoles() (roles []role.Role, dynamic bool)
+}
+
+// ListRoles returns a sorted set of roles assigned by transformers that implement RolesLister.
+// Transformers that do not implement the interface are ignored.
+//
+// Dynamic is set to true if at least one transformer computes roles at runtime.
+func ListRoles(list ...Transformer) (roles []role.Role, dynamic bool) {
+	var ls []RolesLister
+	for _, t := range list {
+		if l, ok := t.(RolesLister); ok {
+			ls = append(ls, l)
+		}
+	}
+	return mergeRoles(ls)
+}
+
+func mergeRoles(list []RolesLister) ([]role.Role, bool) {
+	var (
+		dynamic bool
+		seen    = make(map[role.Role]struct{})
+		out     []role.Role
+	)
+	for _, l := range list {
+		roles, dyn := l.ListRoles()
+		dynamic = dynamic || dyn
+		for _, r := range roles {
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out, dynamic
+}
+
+var _ RolesLister = annotation{}
+
+// newAnnotation creates an annotation mapping with a set of static roles. Roles reported by fields mapping
+// are included as well, if it implements RolesLister.
+func newAnnotation(m, fields ObjMapping, dynamic bool, roles ...role.Role) annotation {
+	a := annotation{m: m, roles: roles, dynamic: dynamic}
+	if l, ok := fields.(RolesLister); ok {
+		froles, fdyn := l.ListRoles()
+		a.roles = append(append([]role.Role{}, roles...), froles...)
+		a.dynamic = a.dynamic || fdyn
+	}
+	return a
+}
+
+// annotation is an object mapping that assigns a specific set of roles.
+type annotation struct {
+	m       ObjMapping
+	roles   []role.Role
+	dynamic bool
+}
+
+func (m annotation) Mapping() (src, dst Op) {
+	return m.m.Mapping()
+}
+
+func (m annotation) ObjMapping() (src, dst ObjectOp) {
+	return m.m.ObjMapping()
+}
+
+// ListRoles implements RolesLister.
+func (m annotation) ListRoles() ([]role.Role, bool) {
+	return append([]role.Role{}, m.roles...), m.dynamic
+}
+
+var _ RolesLister = FieldRoles{}
+
+// ListRoles implements RolesLister. Fields with a custom operation are reported as dynamic.
+func (f FieldRoles) ListRoles() ([]role.Role, bool) {
+	var (
+		roles   []role.Role
+		dynamic bool
+	)
+	for _, fld := range f {
+		roles = append(roles, fld.Roles...)
+		if fld.Sub != nil {
+			if l, ok := fld.Sub.(RolesLister); ok {
+				sroles, sdyn := l.ListRoles()
+				roles = append(roles, sroles...)
+				dynamic = dynamic || sdyn
+			} else {
+				dynamic = true
+			}
+		} else if fld.Op != nil {
+			dynamic = true
+		}
+	}
+	return roles, dynamic
+}
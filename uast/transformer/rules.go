@@ -0,0 +1,95 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/query"
+	"github.com/bblfsh/sdk/v3/uast/query/xpath"
+)
+
+var _ Transformer = (*RuleSet)(nil)
+
+// Rule is a single rewrite rule of a RuleSet. It replaces each node matched by an XPath expression
+// with the result of the function, see ReplaceMatches.
+type Rule struct {
+	// Name of the rule, used in error messages. The expression is used if the name is not set.
+	Name string
+	// XPath is an expression that selects nodes to replace. It must only match objects and arrays.
+	XPath string
+	// Func returns a replacement for the matched node.
+	Func func(nodes.External) (nodes.Node, error)
+}
+
+// ConflictPolicy defines how RuleSet handles nodes matched by multiple rules.
+type ConflictPolicy int
+
+const (
+	// FirstRuleWins applies only the first rule that matched the node, in order of the rules in the set.
+	FirstRuleWins = ConflictPolicy(iota)
+	// ConflictError causes RuleSet to fail with ErrRuleConflict.
+	ConflictError
+)
+
+// RuleSet is an ordered collection of rewrite rules that are applied to the tree in a single pass.
+//
+// All queries are executed on the input tree, thus rules never match nodes produced by other rules.
+// Nested matches are processed bottom-up, as in ReplaceMatches: if a node matched by one rule contains
+// a node matched by another rule, the function of the outer rule receives the node with the inner
+// replacement already applied. Only a single rule is applied to each node, see ConflictPolicy.
+type RuleSet struct {
+	rules  []compiledRule
+	policy ConflictPolicy
+}
+
+type compiledRule struct {
+	Rule
+	q query.Query
+}
+
+// NewRuleSet compiles XPath expressions of all rules and creates a rule set.
+func NewRuleSet(policy ConflictPolicy, rules ...Rule) (*RuleSet, error) {
+	s := &RuleSet{policy: policy, rules: make([]compiledRule, 0, len(rules))}
+	eng := xpath.New()
+	for _, r := range rules {
+		if r.Name == "" {
+			r.Name = r.XPath
+		}
+		q, err := eng.Prepare(r.XPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xpath expression in rule %q: %v", r.Name, err)
+		}
+		s.rules = append(s.rules, compiledRule{Rule: r, q: q})
+	}
+	return s, nil
+}
+
+// Do implements Transformer.
+func (s *RuleSet) Do(root nodes.Node) (nodes.Node, error) {
+	if root == nil || len(s.rules) == 0 {
+		return root, nil
+	}
+	matched := make(map[nodes.Comparable]func(nodes.External) (nodes.Node, error))
+	// index of the rule that matched the node, used to report conflicts
+	byRule := make(map[nodes.Comparable]int)
+	for i, r := range s.rules {
+		err := matchNodes(r.q, root, func(key nodes.Comparable) error {
+			if j, ok := byRule[key]; ok {
+				if j != i && s.policy == ConflictError {
+					return ErrRuleConflict.New(s.rules[j].Name, r.Name)
+				}
+				return nil
+			}
+			byRule[key] = i
+			matched[key] = r.Func
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(matched) == 0 {
+		return root, nil
+	}
+	return replaceMatched(root, matched)
+}
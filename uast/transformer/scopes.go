@@ -0,0 +1,56 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+var (
+	_ Transformer = markScopes{}
+	_ RolesLister = markScopes{}
+)
+
+// MarkScopes is an irreversible transformation that marks objects of types listed in the table as scope boundaries.
+// Each such object receives a role from the table and a unique scope ID stored in the uast.KeyScopeID field.
+// Usually, the role is role.Scope, but drivers may choose a different one for specific types.
+//
+// Scope IDs are assigned in pre-order, starting from 1 for the first scope, and the fields of each object are visited
+// in the sorted order. Thus, nested scopes always receive larger IDs than the enclosing ones. Objects stored in
+// positional information fields (see uast.KeyPos) are never marked.
+//
+// The transformation is idempotent: existing scope IDs are reassigned in the same way, and roles are not duplicated.
+func MarkScopes(table map[string]role.Role) Transformer {
+	return markScopes{table: table}
+}
+
+type markScopes struct {
+	table map[string]role.Role
+}
+
+// ListRoles implements RolesLister.
+func (t markScopes) ListRoles() ([]role.Role, bool) {
+	roles := make([]role.Role, 0, len(t.table))
+	for _, r := range t.table {
+		roles = append(roles, r)
+	}
+	return roles, false
+}
+
+// Do implements Transformer.
+func (t markScopes) Do(root nodes.Node) (nodes.Node, error) {
+	var id int64
+	return rebuildPreOrder(root, func(n nodes.Object) (nodes.Object, rebuildFunc) {
+		r, ok := t.table[uast.TypeOf(n)]
+		if !ok {
+			return nil, nil
+		}
+		id++
+		out := n.CloneObject()
+		out[uast.KeyRoles] = appendRoles(n[uast.KeyRoles], []role.Role{r})
+		out[uast.KeyScopeID] = nodes.Int(id)
+		return out, nil
+	}, func(k string) bool {
+		return k == uast.KeyPos || k == uast.KeyRoles || k == uast.KeyScopeID
+	}), nil
+}
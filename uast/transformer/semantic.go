@@ -94,6 +94,18 @@ func MapSemanticPos(nativeType string, semType interface{}, pos map[string]strin
 	return MapObj(so, UASTType(semType, do))
 }
 
+// MapSemanticKeepType is like MapSemantic, but stores the native type of the node in a given field of the
+// semantic node. If the key is empty, KeyNativeType is used.
+//
+// The mapping is reversible: the field is removed when converting the semantic node back to the native one.
+func MapSemanticKeepType(nativeType string, semType interface{}, key string, m ObjMapping) ObjMapping {
+	if key == "" {
+		key = KeyNativeType
+	}
+	so, do := MapSemantic(nativeType, semType, m).ObjMapping()
+	return MapObj(so, JoinObj(do, Obj{key: String(nativeType)}))
+}
+
 func CommentText(tokens [2]string, vr string) Op {
 	return &commentUAST{
 		startToken: tokens[0],
@@ -0,0 +1,154 @@
+package transformer
+
+import (
+	"sort"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+var _ Transformer = keepSemantic{}
+
+// KeepSemantic is an irreversible transformation that reduces the tree to a semantic skeleton: it removes
+// all objects that have no roles and moves their descendants with roles to the nearest ancestor with roles.
+// The transformation is aggressive and should only be used for language-agnostic analysis of the tree.
+//
+// Objects with roles are kept with their type, token, positions and all value fields, including arrays that
+// contain no objects. Fields that contained removed objects are replaced with the list of surviving descendants,
+// or with a single node if the field contained an object and only one node survived. Fields with no surviving
+// nodes are removed.
+//
+// Descendants of the removed object keep their relative order. If all of them have positional information,
+// they are ordered by the start offset, otherwise by the field name of the removed object.
+//
+// If the root has no roles, the transformation returns an array of surviving nodes, a single node if there
+// is only one of them, or nil if there are none.
+func KeepSemantic() Transformer {
+	return keepSemantic{}
+}
+
+type keepSemantic struct{}
+
+// Do implements Transformer.
+func (keepSemantic) Do(root nodes.Node) (nodes.Node, error) {
+	if obj, ok := root.(nodes.Object); ok && hasRoles(obj) {
+		return keepSemanticObject(obj), nil
+	}
+	list := keepSemanticNodes(root)
+	switch len(list) {
+	case 0:
+		return nil, nil
+	case 1:
+		return list[0], nil
+	}
+	return nodes.Array(list), nil
+}
+
+// hasRoles checks if an object has a non-empty list of roles.
+func hasRoles(obj nodes.Object) bool {
+	arr, ok := obj[uast.KeyRoles].(nodes.Array)
+	return ok && len(arr) != 0
+}
+
+// keepSemanticObject returns a copy of an object with roles, with all the fields reduced to a skeleton.
+func keepSemanticObject(obj nodes.Object) nodes.Object {
+	out := make(nodes.Object, len(obj))
+	for k, v := range obj {
+		switch k {
+		case uast.KeyType, uast.KeyToken, uast.KeyRoles, uast.KeyPos:
+			out[k] = v
+			continue
+		}
+		switch v := v.(type) {
+		case nodes.Object:
+			if hasRoles(v) {
+				out[k] = keepSemanticObject(v)
+				continue
+			}
+			list := keepSemanticNodes(v)
+			if len(list) == 1 {
+				out[k] = list[0]
+			} else if len(list) != 0 {
+				out[k] = nodes.Array(list)
+			}
+		case nodes.Array:
+			if !hasObjects(v) {
+				out[k] = v
+			} else if list := keepSemanticNodes(v); len(list) != 0 {
+				out[k] = nodes.Array(list)
+			}
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// hasObjects checks if an array contains objects on any level.
+func hasObjects(arr nodes.Array) bool {
+	for _, v := range arr {
+		switch v := v.(type) {
+		case nodes.Object:
+			return true
+		case nodes.Array:
+			if hasObjects(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keepSemanticNodes returns a list of the top-most objects with roles in the subtree.
+func keepSemanticNodes(n nodes.Node) []nodes.Node {
+	switch n := n.(type) {
+	case nodes.Object:
+		if hasRoles(n) {
+			return []nodes.Node{keepSemanticObject(n)}
+		}
+		var out []nodes.Node
+		for _, k := range n.Keys() {
+			if k == uast.KeyPos {
+				continue
+			}
+			out = append(out, keepSemanticNodes(n[k])...)
+		}
+		sortByStart(out)
+		return out
+	case nodes.Array:
+		var out []nodes.Node
+		for _, v := range n {
+			out = append(out, keepSemanticNodes(v)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// sortByStart sorts nodes by the start offset, if all of them have positional information.
+func sortByStart(list []nodes.Node) {
+	if len(list) < 2 {
+		return
+	}
+	offs := make([]uint32, len(list))
+	for i, n := range list {
+		start := uast.PositionsOf(n).Start()
+		if start == nil || !start.Valid() {
+			return
+		}
+		offs[i] = start.Offset
+	}
+	sort.Stable(byOffset{list: list, offs: offs})
+}
+
+type byOffset struct {
+	list []nodes.Node
+	offs []uint32
+}
+
+func (s byOffset) Len() int           { return len(s.list) }
+func (s byOffset) Less(i, j int) bool { return s.offs[i] < s.offs[j] }
+func (s byOffset) Swap(i, j int) {
+	s.list[i], s.list[j] = s.list[j], s.list[i]
+	s.offs[i], s.offs[j] = s.offs[j], s.offs[i]
+}
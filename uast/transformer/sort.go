@@ -0,0 +1,122 @@
+package transformer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// SortKey returns a value that is used to sort array elements. The second return value is false
+// if the element has no sort key.
+type SortKey func(n nodes.Node) (nodes.Value, bool)
+
+// ByField creates a sort key that uses a value of the object field. If multiple names are given, they are used
+// as a path to a field of nested objects. Elements that are not objects, do not have the field, or have a field
+// that is not a value have no sort key.
+func ByField(path ...string) SortKey {
+	return func(n nodes.Node) (nodes.Value, bool) {
+		for _, k := range path {
+			obj, ok := n.(nodes.Object)
+			if !ok {
+				return nil, false
+			}
+			n, ok = obj[k]
+			if !ok {
+				return nil, false
+			}
+		}
+		v, ok := n.(nodes.Value)
+		if !ok || v == nil {
+			return nil, false
+		}
+		return v, true
+	}
+}
+
+// ByToken creates a sort key that uses the token of the node. See uast.KeyToken.
+func ByToken() SortKey {
+	return ByField(uast.KeyToken)
+}
+
+// SortArray is an irreversible transformation that sorts elements of an array stored in a given field of each object.
+// It should only be used for fields where the order of elements is not significant, for example imports.
+//
+// The sort is stable. Values of the same kind are compared naturally, and values of different kinds are ordered
+// by their kind. Elements that have no sort key are placed after all other elements, preserving their relative order.
+// Fields that are not arrays are ignored.
+func SortArray(field string, by SortKey) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		arr, ok := n[field].(nodes.Array)
+		if !ok || len(arr) < 2 {
+			return n, false, nil
+		}
+		type elem struct {
+			key nodes.Value
+			has bool
+			ind int
+		}
+		elems := make([]elem, 0, len(arr))
+		for i, e := range arr {
+			k, has := by(e)
+			elems = append(elems, elem{key: k, has: has, ind: i})
+		}
+		sort.SliceStable(elems, func(i, j int) bool {
+			a, b := elems[i], elems[j]
+			if a.has != b.has {
+				return a.has
+			} else if !a.has {
+				return false
+			}
+			return compareValues(a.key, b.key) < 0
+		})
+		out := make(nodes.Array, 0, len(elems))
+		changed := false
+		for i, e := range elems {
+			if e.ind != i {
+				changed = true
+			}
+			out = append(out, arr[e.ind])
+		}
+		if !changed {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		n[field] = out
+		return n, true, nil
+	})
+}
+
+// compareValues compares two values of the same kind. Values of different kinds are compared by their kind.
+func compareValues(a, b nodes.Value) int {
+	ka, kb := nodes.KindOf(a), nodes.KindOf(b)
+	if ka != kb {
+		if ka < kb {
+			return -1
+		}
+		return 1
+	}
+	switch a := a.(type) {
+	case nodes.String:
+		return strings.Compare(string(a), string(b.(nodes.String)))
+	case nodes.Int:
+		return compareOrdered(a < b.(nodes.Int), a > b.(nodes.Int))
+	case nodes.Uint:
+		return compareOrdered(a < b.(nodes.Uint), a > b.(nodes.Uint))
+	case nodes.Float:
+		return compareOrdered(a < b.(nodes.Float), a > b.(nodes.Float))
+	case nodes.Bool:
+		return compareOrdered(!bool(a) && bool(b.(nodes.Bool)), bool(a) && !bool(b.(nodes.Bool)))
+	}
+	return 0
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"strings"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// SplitRolesOptions controls the behavior of SplitToRolesOpt.
+type SplitRolesOptions struct {
+	// KeepField disables removal of the original field from the object.
+	KeepField bool
+	// Strict causes the transformation to return an error for tokens that are not listed in the roles table,
+	// and for fields that are not strings. By default, they are ignored.
+	Strict bool
+}
+
+// SplitToRoles is an irreversible transformation that splits a string field of each object into tokens and adds
+// the roles mapped to these tokens to the object. The original field is removed. Unknown tokens are ignored.
+//
+// Tokens are separated by sep and trimmed of spaces, empty tokens are skipped. If sep is empty, tokens are split
+// on white space. For example, "public static final" with an empty separator or "public, static" with "," as
+// a separator can be mapped to Visibility and other roles.
+//
+// See SplitToRolesOpt for more options.
+func SplitToRoles(field, sep string, table map[string]role.Role) TransformObjFunc {
+	return SplitToRolesOpt(field, sep, table, SplitRolesOptions{})
+}
+
+// SplitToRolesOpt is like SplitToRoles, but allows to keep the original field and to fail on unknown tokens.
+func SplitToRolesOpt(field, sep string, table map[string]role.Role, opt SplitRolesOptions) TransformObjFunc {
+	return TransformObjFunc(func(n nodes.Object) (nodes.Object, bool, error) {
+		v, ok := n[field]
+		if !ok {
+			return n, false, nil
+		}
+		str, ok := v.(nodes.String)
+		if !ok {
+			if opt.Strict && v != nil {
+				return n, false, ErrUnexpectedType.New(nodes.String(""), v)
+			}
+			return n, false, nil
+		}
+		var tokens []string
+		if sep == "" {
+			tokens = strings.Fields(string(str))
+		} else {
+			tokens = strings.Split(string(str), sep)
+		}
+		var roles []role.Role
+		for _, tok := range tokens {
+			tok = strings.TrimSpace(tok)
+			if tok == "" {
+				continue
+			}
+			r, ok := table[tok]
+			if !ok {
+				if opt.Strict {
+					return n, false, ErrUnhandledValueIn.New(tok, field)
+				}
+				continue
+			}
+			roles = append(roles, r)
+		}
+		if opt.KeepField && len(roles) == 0 {
+			return n, false, nil
+		}
+		n = n.CloneObject()
+		if !opt.KeepField {
+			delete(n, field)
+		}
+		if len(roles) != 0 {
+			n[uast.KeyRoles] = appendRoles(n[uast.KeyRoles], roles)
+		}
+		return n, true, nil
+	})
+}
+
+// appendRoles adds roles to the roles array, skipping roles that are already present.
+func appendRoles(old nodes.Node, roles []role.Role) nodes.Array {
+	arr, _ := old.(nodes.Array)
+	out := make(nodes.Array, len(arr), len(arr)+len(roles))
+	copy(out, arr)
+	seen := make(map[nodes.String]struct{}, len(out)+len(roles))
+	for _, v := range out {
+		if s, ok := v.(nodes.String); ok {
+			seen[s] = struct{}{}
+		}
+	}
+	for _, r := range roles {
+		s := nodes.String(r.String())
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package transformer
+
+import (
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+)
+
+// StripPositions is an irreversible transformation that removes positional information from all objects in the tree.
+// It is useful for comparing the structure of trees produced from different sources.
+func StripPositions() TransformObjFunc {
+	return stripKey(uast.KeyPos)
+}
+
+// StripRoles is an irreversible transformation that removes roles from all objects in the tree.
+func StripRoles() TransformObjFunc {
+	return stripKey(uast.KeyRoles)
+}
+
+// StripTokens is an irreversible transformation that removes tokens from all objects in the tree.
+func StripTokens() TransformObjFunc {
+	return stripKey(uast.KeyToken)
+}
+
+// stripKey removes a given key from all objects in the tree. Other fields are left untouched.
+//
+// Objects that have no such key are not modified, others are copied before removing the key,
+// thus the source tree is never mutated.
+func stripKey(key string) TransformObjFunc {
+	return TransformObjFunc(func(obj nodes.Object) (nodes.Object, bool, error) {
+		if _, ok := obj[key]; !ok {
+			return obj, false, nil
+		}
+		out := make(nodes.Object, len(obj)-1)
+		for k, v := range obj {
+			if k != key {
+				out[k] = v
+			}
+		}
+		return out, true, nil
+	})
+}
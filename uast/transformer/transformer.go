@@ -6,16 +6,31 @@ import (
 
 	"github.com/bblfsh/sdk/v3/uast"
 	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
 )
 
 const optimizeCheck = true
 
 // Transformer is an interface for transformations that operates on AST trees.
 // An implementation is responsible for walking the tree and executing transformation on each AST node.
+//
+// Do must treat the input tree as immutable: nodes that are changed must be copied first, together with all their
+// parents (copy-on-write), as done by TransformFunc. Thus, running the same transformation on the same tree twice
+// gives the same result. All transformations in this package follow this contract. Use DoCopy for transformations
+// that do not.
 type Transformer interface {
 	Do(root nodes.Node) (nodes.Node, error)
 }
 
+// DoCopy runs a transformation on a deep copy of the tree. It is useful for transformations that modify
+// the tree in-place, and should not be required for transformations that follow the Transformer contract.
+func DoCopy(t Transformer, root nodes.Node) (nodes.Node, error) {
+	if root != nil {
+		root = root.Clone()
+	}
+	return t.Do(root)
+}
+
 // CodeTransformer is a special case of Transformer that needs an original source code to operate.
 type CodeTransformer interface {
 	OnCode(code string) Transformer
@@ -107,15 +122,69 @@ func (f TransformObjFunc) Do(n nodes.Node) (nodes.Node, error) {
 	return f.Func().Do(n)
 }
 
+// rebuildFunc is called by rebuildPreOrder for each object in the tree. It returns a modified copy of the object
+// or nil if the object is not changed, and a function that will be called for the nested objects. If the returned
+// function is nil, the current function is used for the nested objects.
+type rebuildFunc func(obj nodes.Object) (nodes.Object, rebuildFunc)
+
+// rebuildPreOrder calls fnc for each object in the tree in pre-order, visiting the fields of each object in the sorted
+// order. Fields for which skip returns true are not visited. The tree is rebuilt in a copy-on-write manner: only the
+// objects and arrays that contain changed nodes are copied, the rest of the tree is shared with the original one.
+func rebuildPreOrder(n nodes.Node, fnc rebuildFunc, skip func(k string) bool) nodes.Node {
+	switch n := n.(type) {
+	case nodes.Object:
+		out, sub := fnc(n)
+		if sub == nil {
+			sub = fnc
+		}
+		for _, k := range n.Keys() {
+			if skip != nil && skip(k) {
+				continue
+			}
+			v := n[k]
+			if nv := rebuildPreOrder(v, sub, skip); !nodes.Same(nv, v) {
+				if out == nil {
+					out = n.CloneObject()
+				}
+				out[k] = nv
+			}
+		}
+		if out == nil {
+			return n
+		}
+		return out
+	case nodes.Array:
+		var out nodes.Array
+		for i, v := range n {
+			nv := rebuildPreOrder(v, fnc, skip)
+			if out == nil && !nodes.Same(nv, v) {
+				out = n.CloneList()
+			}
+			if out != nil {
+				out[i] = nv
+			}
+		}
+		if out == nil {
+			return n
+		}
+		return out
+	}
+	return n
+}
+
 // Map creates a two-way mapping between two transform operations.
 // The first operation will be used to check constraints for each node and store state, while the second one will use
 // the state to construct a new tree.
+//
+// If both operations are partial (see Part), all fields captured by the source Part are carried over to the
+// destination object, even if the Part variables have different names on each side.
 func Map(src, dst Op) Mapping {
-	return mapping{src: src, dst: dst}
+	return mapping{src: src, dst: bindPart(src, dst)}
 }
 
+// MapObj is like Map, but for object operations.
 func MapObj(src, dst ObjectOp) ObjMapping {
-	return objMapping{src: src, dst: dst}
+	return objMapping{src: src, dst: bindPart(src, dst).(ObjectOp)}
 }
 
 func MapPart(vr string, m ObjMapping) ObjMapping {
@@ -229,6 +298,22 @@ func Mappings(maps ...Mapping) Transformer {
 	return mp
 }
 
+// BuildPipeline combines a base list of annotation mappings with additional ones, and builds an annotation pipeline
+// from them. The pipeline applies all the mappings as a single transformation, followed by RolesDedup that removes
+// duplicate roles assigned by multiple mappings.
+//
+// The result can be used as the Annotations stage of the driver (see driver.Transforms). Mappings from the base list
+// are checked before the additional ones.
+func BuildPipeline(base, extra []Mapping) []Transformer {
+	all := make([]Mapping, 0, len(base)+len(extra))
+	all = append(all, base...)
+	all = append(all, extra...)
+	return Transformers([][]Transformer{
+		{Mappings(all...)},
+		{RolesDedup()},
+	}...)
+}
+
 type mappings struct {
 	all []Mapping
 
@@ -240,6 +325,17 @@ type mappings struct {
 	typedAny []Mapping            // mappings for any typed object (operations that does not mention the type)
 }
 
+// ListRoles implements RolesLister. It reports roles of all annotation mappings, see AnnotateType.
+func (m mappings) ListRoles() ([]role.Role, bool) {
+	var list []RolesLister
+	for _, mp := range m.all {
+		if l, ok := mp.(RolesLister); ok {
+			list = append(list, l)
+		}
+	}
+	return mergeRoles(list)
+}
+
 func (m *mappings) index() {
 	precompile := func(m Mapping) Mapping {
 		return Map(m.Mapping())
@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"sort"
+	"strings"
 	"testing"
 
 	u "github.com/bblfsh/sdk/v3/uast"
@@ -17,6 +19,17 @@ func toNode(o interface{}) un.Node {
 	return n
 }
 
+func literalFrag(tok string, start, end uint32) un.Object {
+	return un.Object{
+		u.KeyType:  un.String("Str"),
+		u.KeyToken: un.String(tok),
+		u.KeyPos: u.Positions{
+			u.KeyStart: {Offset: start, Line: 1, Col: start + 1},
+			u.KeyEnd:   {Offset: end, Line: 1, Col: end + 1},
+		}.ToObject(),
+	}
+}
+
 var mappingCases = []struct {
 	name     string
 	skip     bool
@@ -49,6 +62,60 @@ var mappingCases = []struct {
 			TopLevelIsRootNode: true,
 		},
 	},
+	{
+		name: "strip positions",
+		inp: un.Array{
+			un.Object{
+				u.KeyType: un.String("typed"),
+				u.KeyPos: toNode(u.Positions{
+					u.KeyStart: {Offset: 1, Line: 1, Col: 2},
+				}),
+				"sub": un.Object{
+					u.KeyPos: toNode(u.Positions{
+						u.KeyStart: {Offset: 2, Line: 1, Col: 3},
+					}),
+					"k": un.String("v"),
+				},
+			},
+			un.String("val"),
+		},
+		m: StripPositions(),
+		exp: un.Array{
+			un.Object{
+				u.KeyType: un.String("typed"),
+				"sub": un.Object{
+					"k": un.String("v"),
+				},
+			},
+			un.String("val"),
+		},
+	},
+	{
+		name: "strip roles",
+		inp: un.Object{
+			u.KeyType:  un.String("typed"),
+			u.KeyRoles: u.RoleList(1, 2),
+			u.KeyToken: un.String("a"),
+		},
+		m: StripRoles(),
+		exp: un.Object{
+			u.KeyType:  un.String("typed"),
+			u.KeyToken: un.String("a"),
+		},
+	},
+	{
+		name: "strip tokens",
+		inp: un.Object{
+			u.KeyType:  un.String("typed"),
+			u.KeyRoles: u.RoleList(1, 2),
+			u.KeyToken: un.String("a"),
+		},
+		m: StripTokens(),
+		exp: un.Object{
+			u.KeyType:  un.String("typed"),
+			u.KeyRoles: u.RoleList(1, 2),
+		},
+	},
 	{
 		name: "roles dedup",
 		inp: un.Array{
@@ -65,6 +132,36 @@ var mappingCases = []struct {
 			},
 		},
 	},
+	{
+		name: "roles dedup (order)",
+		inp: un.Object{
+			u.KeyRoles: u.RoleList(3, 1, 3, 2, 1),
+		},
+		m: RolesDedup(),
+		exp: un.Object{
+			u.KeyRoles: u.RoleList(3, 1, 2),
+		},
+	},
+	{
+		name: "roles dedup sorted",
+		inp: un.Object{
+			u.KeyRoles: u.RoleList(3, 1, 3, 2, 1),
+		},
+		m: RolesDedupSorted(),
+		exp: un.Object{
+			u.KeyRoles: u.RoleList(1, 2, 3),
+		},
+	},
+	{
+		name: "roles dedup sorted (no duplicates)",
+		inp: un.Object{
+			u.KeyRoles: u.RoleList(2, 1),
+		},
+		m: RolesDedupSorted(),
+		exp: un.Object{
+			u.KeyRoles: u.RoleList(1, 2),
+		},
+	},
 	{
 		name: "typed and generic",
 		inp: un.Array{
@@ -112,6 +209,128 @@ var mappingCases = []struct {
 			},
 		},
 	},
+	{
+		name: "part with different names",
+		inp: un.Object{
+			"pred": un.String("val1"),
+			"k1":   un.String("v1"),
+			"k2":   un.String("v2"),
+		},
+		m: Mappings(
+			Map(
+				Part("src", Obj{
+					"pred": Var("x"),
+				}),
+				Part("dst", Obj{
+					"p": Var("x"),
+				}),
+			),
+		),
+		exp: un.Object{
+			"p":  un.String("val1"),
+			"k1": un.String("v1"),
+			"k2": un.String("v2"),
+		},
+	},
+	{
+		name: "part with different names in join",
+		inp: un.Object{
+			u.KeyType: un.String("typed"),
+			"pred":    un.String("val1"),
+			"k":       un.String("v"),
+		},
+		m: Mappings(
+			MapObj(
+				Part("a", JoinObj(
+					Obj{u.KeyType: String("typed")},
+					Obj{"pred": Var("x")},
+				)),
+				Part("b", JoinObj(
+					Obj{u.KeyType: String("typed")},
+					Obj{"p": Var("x")},
+				)),
+			),
+		),
+		exp: un.Object{
+			u.KeyType: un.String("typed"),
+			"p":       un.String("val1"),
+			"k":       un.String("v"),
+		},
+	},
+	{
+		name: "part with different names in check",
+		inp: un.Object{
+			"pred": un.String("val1"),
+			"k":    un.String("v"),
+		},
+		m: Mappings(
+			Map(
+				Check(Has{"pred": String("val1")}, Part("src", Obj{
+					"pred": Var("x"),
+				})),
+				Check(Has{"p": Any()}, Part("dst", Obj{
+					"p": Var("x"),
+				})),
+			),
+			MapObj(
+				CheckObj(Has{"p": String("val1")}, Part("a", Obj{
+					"p": Var("x"),
+				})),
+				CheckObj(Has{"q": Any()}, Part("b", Obj{
+					"q": Var("x"),
+				})),
+			),
+		),
+		exp: un.Object{
+			"q": un.String("val1"),
+			"k": un.String("v"),
+		},
+	},
+	{
+		name: "part overwrites field",
+		inp: un.Object{
+			"pred": un.String("val1"),
+			"p":    un.String("val2"),
+		},
+		m: Mappings(
+			Map(
+				Part("_", Obj{
+					"pred": Var("x"),
+				}),
+				Part("_", Obj{
+					"p": Var("x"),
+				}),
+			),
+		),
+		err: `construct: trying to overwrite already set field with partial object data: "p": val1 = val2`,
+	},
+	{
+		name: "coalesce literals",
+		inp: un.Object{
+			"parts": un.Array{
+				literalFrag(`"a"`, 0, 3),
+				literalFrag(`"b"`, 3, 6),
+				literalFrag(`"c"`, 6, 9),
+				// not adjacent
+				literalFrag(`"d"`, 10, 13),
+			},
+		},
+		m: CoalesceLiterals("Str"),
+		exp: un.Object{
+			"parts": un.Array{
+				literalFrag(`"a""b""c"`, 0, 9),
+				literalFrag(`"d"`, 10, 13),
+			},
+		},
+	},
+	{
+		name: "coalesce literals (not adjacent)",
+		inp: un.Array{
+			literalFrag(`"a"`, 0, 3),
+			literalFrag(`"b"`, 4, 7),
+		},
+		m: CoalesceLiterals("Str"),
+	},
 	{
 		name: "annotate no roles",
 		inp: un.Array{
@@ -270,6 +489,396 @@ var mappingCases = []struct {
 	},
 }
 
+func TestStripDoesNotModifyInput(t *testing.T) {
+	inp := un.Object{
+		u.KeyType:  un.String("typed"),
+		u.KeyToken: un.String("a"),
+	}
+	exp := inp.CloneObject()
+	out, err := StripTokens().Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, exp, inp)
+	require.Equal(t, un.Object{u.KeyType: un.String("typed")}, out)
+}
+
+func TestMapPartReversible(t *testing.T) {
+	inp := un.Object{
+		u.KeyType: un.String("typed"),
+		"pred":    un.String("val1"),
+		"k1":      un.String("v1"),
+		"k2":      un.Int(2),
+	}
+	for _, names := range [][2]string{
+		{"_", "_"},
+		{"part", "part"},
+		{"src", "dst"},
+	} {
+		names := names
+		t.Run(names[0]+"-"+names[1], func(t *testing.T) {
+			m := Map(
+				Part(names[0], Obj{
+					u.KeyType: String("typed"),
+					"pred":    Var("x"),
+				}),
+				Part(names[1], Obj{
+					u.KeyType: String("typed"),
+					"p":       Var("x"),
+				}),
+			)
+			out, err := Mappings(m).Do(inp.CloneObject())
+			require.NoError(t, err)
+			require.Equal(t, un.Object{
+				u.KeyType: un.String("typed"),
+				"p":       un.String("val1"),
+				"k1":      un.String("v1"),
+				"k2":      un.Int(2),
+			}, out)
+
+			back, err := Mappings(Reverse(m)).Do(out)
+			require.NoError(t, err)
+			require.Equal(t, inp, back)
+		})
+	}
+}
+
+func TestListRoles(t *testing.T) {
+	m := Mappings(
+		AnnotateType("a", nil, role.Identifier, role.Name),
+		AnnotateType("b", FieldRoles{
+			"x": {Roles: role.Roles{role.Call}},
+			"y": {Opt: true, Roles: role.Roles{role.Argument}},
+		}, role.Expression),
+		AnnotateIfNoRoles("c", role.Name, role.Literal),
+		Map(Var("x"), Var("x")),
+	)
+	roles, dynamic := ListRoles(m)
+	require.False(t, dynamic)
+	require.Equal(t, sortedRoles(
+		role.Identifier, role.Name, role.Expression, role.Literal, role.Call, role.Argument,
+	), roles)
+
+	m = Mappings(
+		AnnotateTypeCustom("a", nil, Roles(role.Incomplete), role.Identifier),
+	)
+	roles, dynamic = ListRoles(m, TransformFunc(nil))
+	require.True(t, dynamic)
+	require.Equal(t, []role.Role{role.Identifier}, roles)
+}
+
+func TestHasRole(t *testing.T) {
+	pass1 := Mappings(
+		AnnotateIfNoRoles("a", role.Identifier),
+	)
+	pass2 := Mappings(
+		MapObj(
+			CheckObj(
+				HasRole(role.Identifier),
+				Part("other", Obj{u.KeyRoles: Var("roles")}),
+			),
+			Part("other", Obj{u.KeyRoles: Append(Var("roles"), Roles(role.Name))}),
+		),
+	)
+	inp := un.Array{
+		un.Object{u.KeyType: un.String("a")},
+		un.Object{u.KeyType: un.String("b")},
+		un.Object{
+			u.KeyType:  un.String("b"),
+			u.KeyRoles: u.RoleList(role.Expression, role.Literal),
+		},
+		un.Object{
+			u.KeyType:  un.String("c"),
+			u.KeyRoles: u.RoleList(role.Literal, role.Identifier),
+		},
+	}
+	exp := un.Array{
+		un.Object{
+			u.KeyType:  un.String("a"),
+			u.KeyRoles: u.RoleList(role.Identifier, role.Name),
+		},
+		un.Object{u.KeyType: un.String("b")},
+		un.Object{
+			u.KeyType:  un.String("b"),
+			u.KeyRoles: u.RoleList(role.Expression, role.Literal),
+		},
+		un.Object{
+			u.KeyType:  un.String("c"),
+			u.KeyRoles: u.RoleList(role.Literal, role.Identifier, role.Name),
+		},
+	}
+	out, err := pass1.Do(inp)
+	require.NoError(t, err)
+	out, err = pass2.Do(out)
+	require.NoError(t, err)
+	require.Equal(t, exp, out)
+}
+
+func TestHasRoleOrSub(t *testing.T) {
+	sel := HasRoleOrSub(role.Operator)
+	for _, c := range []struct {
+		roles un.Node
+		exp   bool
+		exact bool
+	}{
+		{roles: u.RoleList(role.Operator), exp: true, exact: true},
+		{roles: u.RoleList(role.Binary, role.Relational), exp: true},
+		{roles: u.RoleList(role.Expression, role.LessThan), exp: true},
+		{roles: u.RoleList(role.Expression, role.Literal)},
+		{roles: nil},
+	} {
+		n := un.Object{u.KeyType: un.String("a")}
+		if c.roles != nil {
+			n[u.KeyRoles] = c.roles
+		}
+		ok, err := sel.CheckObj(NewState(), n)
+		require.NoError(t, err)
+		require.Equal(t, c.exp, ok, "%v", c.roles)
+
+		ok, err = HasRole(role.Operator).CheckObj(NewState(), n)
+		require.NoError(t, err)
+		require.Equal(t, c.exact, ok, "%v", c.roles)
+	}
+}
+
+func TestReplaceMatches(t *testing.T) {
+	ident := func(name string) un.Object {
+		return un.Object{u.KeyType: un.String("ident"), "name": un.String(name)}
+	}
+	inp := un.Array{
+		ident("a"),
+		un.Object{
+			u.KeyType: un.String("call"),
+			"func":    ident("b"),
+			"args":    un.Array{ident("c")},
+		},
+	}
+	// wrap the node and record the order of calls
+	var calls []string
+	wrap := func(n un.External) (un.Node, error) {
+		o := n.(un.Object)
+		calls = append(calls, string(o[u.KeyType].(un.String)))
+		return un.Object{u.KeyType: un.String("wrap"), "node": o}, nil
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		calls = nil
+		out, err := ReplaceMatches("//loop", wrap).Do(inp)
+		require.NoError(t, err)
+		require.Equal(t, inp, out)
+		require.Empty(t, calls)
+	})
+	t.Run("one", func(t *testing.T) {
+		calls = nil
+		out, err := ReplaceMatches("//call", wrap).Do(inp)
+		require.NoError(t, err)
+		require.Equal(t, un.Array{
+			inp[0],
+			un.Object{u.KeyType: un.String("wrap"), "node": inp[1]},
+		}, out)
+		require.Equal(t, []string{"call"}, calls)
+	})
+	t.Run("many", func(t *testing.T) {
+		calls = nil
+		out, err := ReplaceMatches("//ident", wrap).Do(inp)
+		require.NoError(t, err)
+		w := func(name string) un.Object {
+			return un.Object{u.KeyType: un.String("wrap"), "node": ident(name)}
+		}
+		require.Equal(t, un.Array{
+			w("a"),
+			un.Object{
+				u.KeyType: un.String("call"),
+				"func":    w("b"),
+				"args":    un.Array{w("c")},
+			},
+		}, out)
+		require.Len(t, calls, 3)
+	})
+	t.Run("nested", func(t *testing.T) {
+		calls = nil
+		out, err := ReplaceMatches("//call | //call//ident", wrap).Do(inp)
+		require.NoError(t, err)
+		w := func(name string) un.Object {
+			return un.Object{u.KeyType: un.String("wrap"), "node": ident(name)}
+		}
+		require.Equal(t, un.Array{
+			inp[0],
+			un.Object{u.KeyType: un.String("wrap"), "node": un.Object{
+				u.KeyType: un.String("call"),
+				"func":    w("b"),
+				"args":    un.Array{w("c")},
+			}},
+		}, out)
+		// inner matches are processed first
+		require.Equal(t, []string{"ident", "ident", "call"}, calls)
+	})
+	t.Run("value", func(t *testing.T) {
+		_, err := ReplaceMatches("count(//ident)", wrap).Do(inp)
+		require.Error(t, err)
+	})
+}
+
+func TestFlattenArray(t *testing.T) {
+	a, b, c := un.String("a"), un.String("b"), un.String("c")
+	obj := func(v un.Node) un.Object {
+		return un.Object{u.KeyType: un.String("block"), "stmts": v}
+	}
+	inp := un.Array{
+		obj(un.Array{un.Array{a, b}, un.Array{c}}),
+		obj(un.Array{un.Array{a, b}, c}),
+		obj(un.Array{a, b}),
+		obj(a),
+		un.Object{u.KeyType: un.String("other")},
+	}
+
+	out, err := FlattenArray("stmts").Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		obj(un.Array{a, b, c}),
+		obj(un.Array{a, b, c}),
+		obj(un.Array{a, b}),
+		obj(a),
+		un.Object{u.KeyType: un.String("other")},
+	}, out)
+
+	out, err = FlattenArrayStrict("stmts").Do(inp[:1])
+	require.NoError(t, err)
+	require.Equal(t, un.Array{obj(un.Array{a, b, c})}, out)
+
+	for _, n := range inp[1:4] {
+		_, err = FlattenArrayStrict("stmts").Do(n)
+		require.True(t, ErrExpectedList.Is(err), "%v", err)
+	}
+}
+
+func TestNormalizeKeywordLiterals(t *testing.T) {
+	pos := u.Positions{
+		u.KeyStart: {Offset: 0, Line: 1, Col: 1},
+		u.KeyEnd:   {Offset: 4, Line: 1, Col: 5},
+	}.ToObject()
+	native := func(typ, tok string) un.Object {
+		return un.Object{
+			u.KeyType:  un.String(typ),
+			u.KeyToken: un.String(tok),
+			u.KeyPos:   pos,
+			"extra":    un.Int(1),
+		}
+	}
+	boolean := func(v bool) un.Object {
+		return un.Object{
+			u.KeyType:  un.String("uast:Bool"),
+			u.KeyRoles: u.RoleList(role.Literal, role.Boolean),
+			u.KeyPos:   pos,
+			"Value":    un.Bool(v),
+		}
+	}
+	null := func(typ, tok string) un.Object {
+		return un.Object{
+			u.KeyType:  un.String(typ),
+			u.KeyToken: un.String(tok),
+			u.KeyRoles: u.RoleList(role.Literal, role.Null),
+			u.KeyPos:   pos,
+		}
+	}
+
+	python := KeywordLiterals{
+		"NameConstant": {"True": KeywordTrue, "False": KeywordFalse, "None": KeywordNull},
+	}
+	ruby := KeywordLiterals{
+		"true":  {"true": KeywordTrue},
+		"false": {"false": KeywordFalse},
+		"nil":   {"nil": KeywordNull},
+	}
+
+	inp := un.Array{
+		native("NameConstant", "True"),
+		native("NameConstant", "False"),
+		native("NameConstant", "None"),
+		native("NameConstant", "true"),
+		// nil is a regular identifier in Python
+		native("Name", "nil"),
+	}
+
+	out, err := NormalizeKeywordLiterals(python).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		boolean(true),
+		boolean(false),
+		null("NameConstant", "None"),
+		inp[3],
+		inp[4],
+	}, out)
+
+	out, err = NormalizeKeywordLiteralsFold(python).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		boolean(true),
+		boolean(false),
+		null("NameConstant", "None"),
+		boolean(true),
+		inp[4],
+	}, out)
+
+	inp = un.Array{
+		native("true", "true"),
+		native("nil", "nil"),
+		native("NameConstant", "True"),
+	}
+	out, err = NormalizeKeywordLiterals(ruby).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		boolean(true),
+		null("nil", "nil"),
+		inp[2],
+	}, out)
+}
+
+func TestFillRootPositionFromChildren(t *testing.T) {
+	pos := func(start, end uint32) un.Object {
+		return u.Positions{
+			u.KeyStart: {Offset: start, Line: 1, Col: start + 1},
+			u.KeyEnd:   {Offset: end, Line: 1, Col: end + 1},
+		}.ToObject()
+	}
+	stmt := func(start, end uint32) un.Object {
+		return un.Object{u.KeyType: un.String("Stmt"), u.KeyPos: pos(start, end)}
+	}
+	inp := un.Object{
+		u.KeyType: un.String("Func"),
+		"name":    un.Object{u.KeyType: un.String("Ident"), u.KeyPos: pos(5, 8)},
+		"body": un.Object{
+			u.KeyType: un.String("Block"),
+			"stmts":   un.Array{stmt(12, 20), un.Array{stmt(22, 30)}, stmt(31, 40)},
+		},
+		// no positioned descendants
+		"doc": un.Object{
+			u.KeyType: un.String("Doc"),
+			"text":    un.String("func"),
+		},
+		// already positioned
+		"recv": un.Object{
+			u.KeyType: un.String("Recv"),
+			u.KeyPos:  pos(0, 1),
+			"typ":     stmt(50, 60),
+		},
+	}
+	exp := inp.CloneObject()
+	body := inp["body"].(un.Object).CloneObject()
+	body[u.KeyPos] = pos(12, 40)
+	exp["body"] = body
+	exp[u.KeyPos] = pos(0, 40)
+
+	out, err := FillRootPositionFromChildren().Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, exp, out)
+}
+
+func sortedRoles(roles ...role.Role) []role.Role {
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i] < roles[j]
+	})
+	return roles
+}
+
 func TestMappings(t *testing.T) {
 	for _, c := range mappingCases {
 		if c.exp == nil {
@@ -290,3 +899,1133 @@ func TestMappings(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckRoleTypeConstraints(t *testing.T) {
+	check := CheckRoleTypeConstraints(map[role.Role][]string{
+		role.Return: {"ReturnStmt"},
+	})
+	ret := un.Object{
+		u.KeyType:  un.String("ReturnStmt"),
+		u.KeyRoles: u.RoleList(role.Statement, role.Return),
+	}
+	ok := un.Object{
+		u.KeyType: un.String("Block"),
+		"body":    un.Array{ret},
+		"other": un.Object{
+			u.KeyType:  un.String("Ident"),
+			u.KeyRoles: u.RoleList(role.Identifier),
+		},
+	}
+	out, err := check.Do(ok)
+	require.NoError(t, err)
+	require.Equal(t, ok, out)
+
+	bad := ok.CloneObject()
+	bad["body"] = un.Array{ret, un.Object{
+		u.KeyType:  un.String("BreakStmt"),
+		u.KeyRoles: u.RoleList(role.Statement, role.Return),
+	}}
+	_, err = check.Do(bad)
+	require.True(t, ErrRoleNotAllowed.Is(err), "%v", err)
+	require.Contains(t, err.Error(), ".body[1]")
+	require.Contains(t, err.Error(), "Return")
+	require.Contains(t, err.Error(), "BreakStmt")
+
+	_, err = CheckRoleTypeConstraints(nil).Do(bad)
+	require.NoError(t, err)
+}
+
+func TestInheritPos(t *testing.T) {
+	m := Mappings(Map(
+		Check(Has{u.KeyType: String("Expr")}, Var("x")),
+		JoinObj(
+			Obj{
+				u.KeyType: String("Wrapper"),
+				"expr":    Var("x"),
+			},
+			InheritPos("x"),
+		),
+	))
+	pos := func() un.Object {
+		return u.Positions{
+			u.KeyStart: {Offset: 1, Line: 1, Col: 2},
+			u.KeyEnd:   {Offset: 3, Line: 1, Col: 4},
+		}.ToObject()
+	}
+	inp := un.Array{
+		un.Object{u.KeyType: un.String("Expr"), u.KeyPos: pos()},
+		un.Object{u.KeyType: un.String("Expr")},
+	}
+	exp := un.Array{
+		un.Object{
+			u.KeyType: un.String("Wrapper"),
+			u.KeyPos:  pos(),
+			"expr":    un.Object{u.KeyType: un.String("Expr"), u.KeyPos: pos()},
+		},
+		un.Object{
+			u.KeyType: un.String("Wrapper"),
+			"expr":    un.Object{u.KeyType: un.String("Expr")},
+		},
+	}
+	orig := inp.Clone()
+	out, err := m.Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, exp, out)
+	require.Equal(t, orig, inp)
+
+	// positions are shared with the source node, since transformations never modify them in-place
+	arr := out.(un.Array)
+	wpos := arr[0].(un.Object)[u.KeyPos]
+	require.True(t, un.Same(wpos, arr[0].(un.Object)["expr"].(un.Object)[u.KeyPos]))
+}
+
+func TestSplitToRoles(t *testing.T) {
+	// access modifiers, as they are used together with role.Visibility
+	table := map[string]role.Role{
+		"public":    role.World,
+		"protected": role.Subtype,
+		"internal":  role.Module,
+		"private":   role.Instance,
+	}
+	inp := func() un.Object {
+		return un.Object{
+			u.KeyType:   un.String("Method"),
+			u.KeyRoles:  u.RoleList(role.Function, role.Visibility),
+			"modifiers": un.String("protected  internal static"),
+		}
+	}
+
+	out, err := SplitToRoles("modifiers", "", table).Do(inp())
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType:  un.String("Method"),
+		u.KeyRoles: u.RoleList(role.Function, role.Visibility, role.Subtype, role.Module),
+	}, out)
+
+	out, err = SplitToRolesOpt("modifiers", "", table, SplitRolesOptions{KeepField: true}).Do(inp())
+	require.NoError(t, err)
+	exp := inp()
+	exp[u.KeyRoles] = u.RoleList(role.Function, role.Visibility, role.Subtype, role.Module)
+	require.Equal(t, exp, out)
+
+	_, err = SplitToRolesOpt("modifiers", "", table, SplitRolesOptions{Strict: true}).Do(inp())
+	require.True(t, ErrUnhandledValueIn.Is(err), "%v", err)
+
+	out, err = SplitToRolesOpt("modifiers", ",", table, SplitRolesOptions{Strict: true}).Do(un.Object{
+		"modifiers": un.String("private, internal,"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyRoles: u.RoleList(role.Instance, role.Module),
+	}, out)
+}
+
+func TestSortArray(t *testing.T) {
+	imp := func(name string) un.Object {
+		return un.Object{
+			u.KeyType: un.String("Import"),
+			"Path":    un.Object{u.KeyType: un.String("Ident"), "Name": un.String(name)},
+		}
+	}
+	dot := un.Object{u.KeyType: un.String("Import"), "Dot": un.Bool(true)}
+	blank := un.Object{u.KeyType: un.String("Import"), "Path": nil}
+
+	inp := un.Object{
+		u.KeyType: un.String("File"),
+		"Imports": un.Array{imp("os"), dot, imp("fmt"), blank, imp("bytes"), imp("fmt")},
+		"Body":    un.Array{imp("z"), imp("a")},
+	}
+	out, err := SortArray("Imports", ByField("Path", "Name")).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"Imports": un.Array{imp("bytes"), imp("fmt"), imp("fmt"), imp("os"), dot, blank},
+		"Body":    un.Array{imp("z"), imp("a")},
+	}, out)
+
+	// mixed kinds are ordered by kind, tokens are used as a key
+	tok := func(v un.Value) un.Object {
+		return un.Object{u.KeyToken: v}
+	}
+	out, err = SortArray("List", ByToken()).Do(un.Object{
+		"List": un.Array{tok(un.String("b")), tok(un.Int(2)), un.String("x"), tok(un.Int(-1)), tok(un.String("a"))},
+	})
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		"List": un.Array{tok(un.String("a")), tok(un.String("b")), tok(un.Int(-1)), tok(un.Int(2)), un.String("x")},
+	}, out)
+}
+
+func TestDedupArray(t *testing.T) {
+	decl := func(name string, off uint32) un.Object {
+		return un.Object{
+			u.KeyType: un.String("Decl"),
+			u.KeyPos: u.Positions{
+				u.KeyStart: {Offset: off},
+			}.ToObject(),
+			"Name": un.String(name),
+		}
+	}
+	a, b := decl("a", 0), decl("b", 5)
+	inp := un.Object{
+		u.KeyType: un.String("File"),
+		"Decls":   un.Array{a, b, a.Clone(), decl("a", 10), un.String("x")},
+	}
+	orig := inp.Clone()
+
+	// position-only differences are considered duplicates by default
+	out, err := DedupArray("Decls", nil).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"Decls":   un.Array{a, b, un.String("x")},
+	}, out)
+
+	// structural equality keeps nodes with different positions
+	out, err = DedupArray("Decls", StructuralEqual()).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"Decls":   un.Array{a, b, decl("a", 10), un.String("x")},
+	}, out)
+	require.Equal(t, orig, inp)
+
+	// nothing to remove
+	out, err = DedupArray("Decls", nil).Do(un.Object{"Decls": un.Array{a, b}})
+	require.NoError(t, err)
+	require.Equal(t, un.Object{"Decls": un.Array{a, b}}, out)
+}
+
+func TestNormalizeComments(t *testing.T) {
+	comment := func(typ, text string, start, end u.Position) un.Object {
+		return un.Object{
+			u.KeyType:  un.String(typ),
+			u.KeyToken: un.String(text),
+			u.KeyPos:   u.Positions{u.KeyStart: start, u.KeyEnd: end}.ToObject(),
+		}
+	}
+	block := "/*\n * multi\n * line\n */"
+	inp := un.Array{
+		comment("LineComment", "#!/bin/sh", u.Position{Offset: 0, Line: 1, Col: 1}, u.Position{Offset: 9, Line: 1, Col: 10}),
+		comment("LineComment", "// line", u.Position{Offset: 10, Line: 2, Col: 1}, u.Position{Offset: 17, Line: 2, Col: 8}),
+		comment("LineComment", "/// doc", u.Position{Offset: 18, Line: 3, Col: 1}, u.Position{Offset: 25, Line: 3, Col: 8}),
+		comment("BlockComment", block, u.Position{Offset: 26, Line: 4, Col: 1}, u.Position{Offset: 51, Line: 7, Col: 4}),
+		comment("BlockComment", "/** doc */", u.Position{Offset: 52, Line: 8, Col: 1}, u.Position{Offset: 62, Line: 8, Col: 11}),
+		comment("BlockComment", "/**/", u.Position{Offset: 63, Line: 9, Col: 1}, u.Position{Offset: 67, Line: 9, Col: 5}),
+		comment("JavaDoc", "/* api */", u.Position{Offset: 68, Line: 10, Col: 1}, u.Position{Offset: 77, Line: 10, Col: 10}),
+		un.Object{u.KeyType: un.String("Ident"), u.KeyToken: un.String("// not a comment")},
+	}
+	orig := inp.Clone()
+
+	expect := func(n un.Object, text string, block bool, roles ...role.Role) un.Object {
+		n = n.CloneObject()
+		n[u.KeyToken] = un.String(text)
+		n[u.KeyRoles] = u.RoleList(roles...)
+		n[u.KeyCommentBlock] = un.Bool(block)
+		return n
+	}
+	opts := CommentOptions{
+		Types: map[string]CommentStyle{
+			"LineComment":  {Start: "//"},
+			"BlockComment": {Block: true, Start: "/*", End: "*/"},
+			"JavaDoc":      {Block: true, Doc: true, Start: "/*", End: "*/"},
+		},
+		DocPrefixes:  []string{"///", "/**"},
+		Shebang:      true,
+		StripMarkers: true,
+	}
+	out, err := NormalizeComments(opts).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		expect(inp[0].(un.Object), "/bin/sh", false, role.Comment),
+		expect(inp[1].(un.Object), "line", false, role.Comment),
+		expect(inp[2].(un.Object), "doc", false, role.Comment, role.Documentation),
+		expect(inp[3].(un.Object), "multi\nline", true, role.Comment),
+		expect(inp[4].(un.Object), "doc", true, role.Comment, role.Documentation),
+		expect(inp[5].(un.Object), "", true, role.Comment),
+		expect(inp[6].(un.Object), "api", true, role.Comment, role.Documentation),
+		inp[7],
+	}, out)
+	require.Equal(t, orig, inp)
+
+	// shebang is only recognized at the beginning of the file; markers of other comment types are kept
+	late := comment("LineComment", "#!/bin/sh", u.Position{Offset: 10, Line: 2, Col: 1}, u.Position{Offset: 19, Line: 2, Col: 10})
+	out, err = NormalizeComments(opts).Do(un.Array{late})
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		expect(late, "#!/bin/sh", false, role.Comment),
+	}, out)
+
+	// markers are kept by default, shebang is only recognized when enabled
+	opts.StripMarkers, opts.Shebang = false, false
+	out, err = NormalizeComments(opts).Do(inp[:1])
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		expect(inp[0].(un.Object), "#!/bin/sh", false, role.Comment),
+	}, out)
+}
+
+func TestRenameTypes(t *testing.T) {
+	inp := un.Array{
+		un.Object{u.KeyType: un.String("Name"), "Name": un.String("a")},
+		un.Object{u.KeyType: un.String("Other")},
+		un.Object{"Name": un.String("b")},
+	}
+	orig := inp.Clone()
+	table := map[string]string{"Name": "Ident"}
+
+	out, err := RenameTypes(table).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		un.Object{u.KeyType: un.String("Ident"), "Name": un.String("a")},
+		inp[1], inp[2],
+	}, out)
+
+	out, err = RenameTypesOpt(table, RenameTypesOptions{NativeTypeKey: KeyNativeType}).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		un.Object{u.KeyType: un.String("Ident"), KeyNativeType: un.String("Name"), "Name": un.String("a")},
+		inp[1], inp[2],
+	}, out)
+	require.Equal(t, orig, inp)
+
+	// the original type can be recovered
+	back, err := Mappings(Map(
+		Obj{u.KeyType: String("Ident"), KeyNativeType: Var("typ"), "Name": Var("name")},
+		Obj{u.KeyType: Var("typ"), "Name": Var("name")},
+	)).Do(out)
+	require.NoError(t, err)
+	require.Equal(t, inp, back)
+}
+
+func TestRenameFields(t *testing.T) {
+	inp := un.Object{
+		u.KeyType: un.String("Func"),
+		"name":    un.Object{u.KeyType: un.String("Ident"), "id": un.String("f")},
+		"params":  un.Array{un.Object{"id": un.String("a")}},
+		"left":    un.Int(1),
+		"right":   un.Int(2),
+	}
+	orig := inp.Clone()
+	rename := RenameFields(map[string]string{
+		"name":   "Name",
+		"params": "Params",
+		"left":   "right",
+		"right":  "left",
+	})
+	out, err := rename.Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("Func"),
+		"Name":    un.Object{u.KeyType: un.String("Ident"), "id": un.String("f")},
+		"Params":  un.Array{un.Object{"id": un.String("a")}},
+		"left":    un.Int(2),
+		"right":   un.Int(1),
+	}, out)
+	require.Equal(t, orig, inp)
+
+	// a renamed field collides with an existing one
+	_, err = rename.Do(un.Object{"name": un.String("a"), "Name": un.String("b")})
+	require.True(t, ErrDuplicateField.Is(err), "%v", err)
+
+	// two fields are renamed to the same name
+	require.Panics(t, func() {
+		RenameFields(map[string]string{"name": "Name", "id": "Name"})
+	})
+}
+
+func TestMapSemanticKeepType(t *testing.T) {
+	inp := un.Object{
+		u.KeyType: un.String("Name"),
+		u.KeyPos:  u.Positions{u.KeyStart: {Offset: 1, Line: 1, Col: 2}}.ToObject(),
+		"id":      un.String("a"),
+	}
+	fields := MapObj(Obj{"id": Var("name")}, Obj{"Name": Var("name")})
+
+	m := MapSemantic("Name", u.Identifier{}, fields)
+	out, err := Mappings(m).Do(inp.CloneObject())
+	require.NoError(t, err)
+	require.Equal(t, un.String(u.TypeOf(u.Identifier{})), out.(un.Object)[u.KeyType])
+	_, ok := out.(un.Object)[KeyNativeType]
+	require.False(t, ok, "native type should not be stored by default")
+
+	m = MapSemanticKeepType("Name", u.Identifier{}, "", fields)
+	out, err = Mappings(m).Do(inp.CloneObject())
+	require.NoError(t, err)
+	obj := out.(un.Object)
+	require.Equal(t, un.String(u.TypeOf(u.Identifier{})), obj[u.KeyType])
+	require.Equal(t, un.String("Name"), obj[KeyNativeType])
+
+	back, err := Mappings(Reverse(m)).Do(out)
+	require.NoError(t, err)
+	require.Equal(t, inp, back)
+}
+
+func TestAnnotateByParentField(t *testing.T) {
+	expr := func(name string) un.Object {
+		return un.Object{u.KeyType: un.String("Expr"), "name": un.String(name)}
+	}
+	withRoles := func(n un.Object, roles ...role.Role) un.Object {
+		n = n.CloneObject()
+		n[u.KeyRoles] = u.RoleList(roles...)
+		return n
+	}
+	shared := expr("shared")
+	inp := un.Object{
+		u.KeyType:   un.String("If"),
+		"condition": shared,
+		"body":      un.Array{expr("a"), un.Array{expr("b")}, un.String("c")},
+		"else": un.Object{
+			u.KeyType:  un.String("Block"),
+			u.KeyRoles: u.RoleList(role.Block),
+			"body":     un.Array{shared},
+		},
+	}
+	orig := inp.Clone()
+
+	tr := AnnotateByParentField(map[string]role.Role{
+		"condition": role.Condition,
+		"body":      role.Body,
+		"else":      role.Else,
+	})
+	out, err := tr.Do(inp)
+	require.NoError(t, err)
+	sharedOut := withRoles(shared, role.Body, role.Condition)
+	require.Equal(t, un.Object{
+		u.KeyType:   un.String("If"),
+		"condition": sharedOut,
+		"body":      un.Array{withRoles(expr("a"), role.Body), un.Array{withRoles(expr("b"), role.Body)}, un.String("c")},
+		"else": un.Object{
+			u.KeyType:  un.String("Block"),
+			u.KeyRoles: u.RoleList(role.Block, role.Else),
+			"body":     un.Array{sharedOut},
+		},
+	}, out)
+	require.Equal(t, orig, inp)
+
+	// the annotated node is still shared
+	obj := out.(un.Object)
+	require.True(t, un.Same(obj["condition"], obj["else"].(un.Object)["body"].(un.Array)[0]))
+
+	roles, dynamic := ListRoles(tr)
+	require.False(t, dynamic)
+	require.Equal(t, []role.Role{role.Body, role.Condition, role.Else}, roles)
+}
+
+func TestOpFunc(t *testing.T) {
+	// qualifiedName joins package, type and member names into a single qualified name
+	qualifiedName := OpFunc(func(st *State) (un.Node, error) {
+		var parts []string
+		for _, name := range []string{"pkg", "typ", "member"} {
+			v, err := st.MustGetVar(name)
+			if err != nil {
+				return nil, err
+			}
+			s, ok := v.(un.String)
+			if !ok {
+				return nil, ErrExpectedValue.New(v)
+			}
+			parts = append(parts, string(s))
+		}
+		return un.String(strings.Join(parts, ".")), nil
+	})
+	m := Map(
+		Obj{
+			u.KeyType: String("Member"),
+			"pkg":     Var("pkg"),
+			"type":    Var("typ"),
+			"name":    Var("member"),
+		},
+		Obj{
+			u.KeyType: String("QualifiedName"),
+			"Name":    qualifiedName,
+		},
+	)
+	inp := un.Object{
+		u.KeyType: un.String("Member"),
+		"pkg":     un.String("io"),
+		"type":    un.String("Reader"),
+		"name":    un.String("Read"),
+	}
+	out, err := Mappings(m).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("QualifiedName"),
+		"Name":    un.String("io.Reader.Read"),
+	}, out)
+
+	_, err = Mappings(Reverse(m)).Do(out)
+	require.True(t, ErrConstructOnly.Is(err), "%v", err)
+
+	// missing variables are reported by the function
+	_, err = Mappings(Map(
+		Obj{u.KeyType: String("Member"), "pkg": Var("pkg"), "type": AnyNode(nil), "name": AnyNode(nil)},
+		Obj{u.KeyType: String("QualifiedName"), "Name": qualifiedName},
+	)).Do(inp)
+	require.True(t, ErrVariableNotDefined.Is(err), "%v", err)
+}
+
+func TestAssignIDs(t *testing.T) {
+	pos := u.Positions{u.KeyStart: {Offset: 1, Line: 1, Col: 2}}.ToObject()
+	ident := un.Object{
+		u.KeyType: un.String("Ident"),
+		u.KeyPos:  pos,
+		"Name":    un.String("a"),
+	}
+	inp := un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			ident,
+			un.Object{u.KeyType: un.String("Expr"), "x": ident},
+		},
+		"name": un.Object{u.KeyType: un.String("Ident"), "id": un.Int(42)},
+	}
+	orig := inp.CloneObject()
+
+	tr := AssignIDs("id")
+	out, err := tr.Do(inp)
+	require.NoError(t, err)
+	require.True(t, un.Equal(orig, inp), "input was modified")
+
+	withID := func(n un.Object, id int64) un.Object {
+		n = n.CloneObject()
+		n["id"] = un.Int(id)
+		return n
+	}
+	expExpr := withID(un.Object{u.KeyType: un.String("Expr"), "x": withID(ident, 3)}, 2)
+	exp := un.Object{
+		u.KeyType: un.String("File"),
+		"id":      un.Int(0),
+		"body": un.Array{
+			withID(ident, 1),
+			expExpr,
+		},
+		"name": un.Object{u.KeyType: un.String("Ident"), "id": un.Int(42)},
+	}
+	require.Equal(t, exp, out)
+
+	// identical trees get identical IDs
+	out2, err := tr.Do(orig.CloneObject())
+	require.NoError(t, err)
+	require.Equal(t, out, out2)
+
+	// running it again doesn't change existing IDs
+	out3, err := tr.Do(out)
+	require.NoError(t, err)
+	require.Equal(t, out, out3)
+
+	out, err = AssignIDsOpt("id", AssignIDsOptions{Overwrite: true}).Do(inp)
+	require.NoError(t, err)
+	exp["name"] = withID(exp["name"].(un.Object), 4)
+	require.Equal(t, exp, out)
+}
+
+func TestRuleSet(t *testing.T) {
+	ident := func(name string) un.Object {
+		return un.Object{u.KeyType: un.String("ident"), "name": un.String(name)}
+	}
+	call := func(fnc un.Node, args ...un.Node) un.Object {
+		return un.Object{u.KeyType: un.String("call"), "func": fnc, "args": un.Array(args)}
+	}
+	inp := un.Array{
+		ident("a"),
+		call(ident("b"), ident("c")),
+	}
+	orig := inp.CloneList()
+
+	wrapAs := func(typ string) func(un.External) (un.Node, error) {
+		return func(n un.External) (un.Node, error) {
+			return un.Object{u.KeyType: un.String(typ), "node": n.(un.Node)}, nil
+		}
+	}
+	rename := func(n un.External) (un.Node, error) {
+		o := n.(un.Object).CloneObject()
+		o["name"] = un.String(strings.ToUpper(string(o["name"].(un.String))))
+		return o, nil
+	}
+
+	t.Run("multiple", func(t *testing.T) {
+		rs, err := NewRuleSet(FirstRuleWins,
+			Rule{XPath: "//ident[@name='a']", Func: rename},
+			Rule{XPath: "//call", Func: wrapAs("wrap")},
+		)
+		require.NoError(t, err)
+		out, err := rs.Do(inp)
+		require.NoError(t, err)
+		require.Equal(t, un.Array{
+			ident("A"),
+			un.Object{u.KeyType: un.String("wrap"), "node": inp[1]},
+		}, out)
+		require.Equal(t, orig, inp)
+	})
+	t.Run("nested", func(t *testing.T) {
+		// the outer rule receives the output of the inner rule
+		var got un.Node
+		rs, err := NewRuleSet(FirstRuleWins,
+			Rule{XPath: "//call", Func: func(n un.External) (un.Node, error) {
+				got = n.(un.Node)
+				return wrapAs("wrap")(n)
+			}},
+			Rule{XPath: "//call//ident", Func: rename},
+		)
+		require.NoError(t, err)
+		out, err := rs.Do(inp)
+		require.NoError(t, err)
+		exp := call(ident("B"), ident("C"))
+		require.Equal(t, exp, got)
+		require.Equal(t, un.Array{
+			ident("a"),
+			un.Object{u.KeyType: un.String("wrap"), "node": exp},
+		}, out)
+	})
+	t.Run("no rematch", func(t *testing.T) {
+		// rules only match nodes of the input tree
+		rs, err := NewRuleSet(ConflictError,
+			Rule{XPath: "//ident", Func: wrapAs("wrap")},
+			Rule{XPath: "//wrap", Func: wrapAs("outer")},
+		)
+		require.NoError(t, err)
+		out, err := rs.Do(un.Array{ident("a")})
+		require.NoError(t, err)
+		require.Equal(t, un.Array{
+			un.Object{u.KeyType: un.String("wrap"), "node": ident("a")},
+		}, out)
+	})
+	t.Run("first wins", func(t *testing.T) {
+		rs, err := NewRuleSet(FirstRuleWins,
+			Rule{XPath: "//ident[@name='b']", Func: rename},
+			Rule{XPath: "//ident", Func: wrapAs("wrap")},
+		)
+		require.NoError(t, err)
+		out, err := rs.Do(inp)
+		require.NoError(t, err)
+		require.Equal(t, un.Array{
+			un.Object{u.KeyType: un.String("wrap"), "node": ident("a")},
+			call(ident("B"), un.Object{u.KeyType: un.String("wrap"), "node": ident("c")}),
+		}, out)
+	})
+	t.Run("conflict", func(t *testing.T) {
+		rs, err := NewRuleSet(ConflictError,
+			Rule{Name: "rename", XPath: "//ident[@name='b']", Func: rename},
+			Rule{XPath: "//ident", Func: wrapAs("wrap")},
+		)
+		require.NoError(t, err)
+		_, err = rs.Do(inp)
+		require.True(t, ErrRuleConflict.Is(err), "%v", err)
+		require.Contains(t, err.Error(), `"rename" and "//ident"`)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewRuleSet(FirstRuleWins, Rule{XPath: "//ident[", Func: rename})
+		require.Error(t, err)
+	})
+}
+
+func TestKeepSemantic(t *testing.T) {
+	at := func(off uint32) un.Object {
+		return u.Positions{u.KeyStart: {Offset: off, Line: 1, Col: off + 1}}.ToObject()
+	}
+	ident := func(name string, off uint32) un.Object {
+		return un.Object{
+			u.KeyType:  un.String("Ident"),
+			u.KeyRoles: u.RoleList(role.Identifier),
+			u.KeyPos:   at(off),
+			"Name":     un.String(name),
+		}
+	}
+	// the wrapper has no roles; its children are stored in fields that are not ordered by position
+	wrapper := un.Object{
+		u.KeyType: un.String("Paren"),
+		u.KeyPos:  at(2),
+		"a":       ident("y", 8),
+		"b":       ident("x", 3),
+		"lparen":  un.String("("),
+	}
+	inp := un.Object{
+		u.KeyType:  un.String("Call"),
+		u.KeyRoles: u.RoleList(role.Call),
+		u.KeyPos:   at(0),
+		"Name":     un.String("f"),
+		"Args":     wrapper,
+		"Syntax":   un.Object{u.KeyType: un.String("Semicolon")},
+		"Mods":     un.Array{un.String("static"), un.String("final")},
+		"Empty":    un.Array{},
+		"List": un.Array{
+			un.Object{u.KeyType: un.String("Expr"), "x": ident("z", 12)},
+			un.Object{u.KeyType: un.String("Comma")},
+		},
+	}
+	orig := inp.CloneObject()
+
+	out, err := KeepSemantic().Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType:  un.String("Call"),
+		u.KeyRoles: u.RoleList(role.Call),
+		u.KeyPos:   at(0),
+		"Name":     un.String("f"),
+		"Args":     un.Array{ident("x", 3), ident("y", 8)},
+		"List":     un.Array{ident("z", 12)},
+		"Mods":     un.Array{un.String("static"), un.String("final")},
+		"Empty":    un.Array{},
+	}, out)
+	require.True(t, un.Equal(orig, inp), "input was modified")
+
+	// the root without roles
+	out, err = KeepSemantic().Do(wrapper)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{ident("x", 3), ident("y", 8)}, out)
+
+	out, err = KeepSemantic().Do(un.Object{"x": ident("x", 3)})
+	require.NoError(t, err)
+	require.Equal(t, ident("x", 3), out)
+
+	out, err = KeepSemantic().Do(un.Object{u.KeyType: un.String("Empty")})
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestShiftLineCol(t *testing.T) {
+	raw := func(off, line, col int64) un.Object {
+		return un.Object{
+			u.KeyType:    un.String(u.TypePosition),
+			u.KeyPosOff:  un.Int(off),
+			u.KeyPosLine: un.Int(line),
+			u.KeyPosCol:  un.Int(col),
+		}
+	}
+	tree := func(first un.Object, line, col uint32) un.Object {
+		return un.Object{
+			u.KeyType: un.String("File"),
+			u.KeyPos: un.Object{
+				u.KeyType:  un.String(u.TypePositions),
+				u.KeyStart: first,
+			},
+			"body": un.Array{
+				un.Object{
+					u.KeyType: un.String("Ident"),
+					u.KeyPos:  u.Positions{u.KeyStart: {Offset: 7, Line: line, Col: col}}.ToObject(),
+				},
+			},
+		}
+	}
+	// 0-based tree with both raw integer and unsigned positions
+	inp := tree(raw(0, 0, 0), 1, 3)
+	orig := inp.CloneObject()
+
+	out, err := ToOneBased().Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, tree(raw(0, 1, 1), 2, 4), out)
+	require.True(t, un.Equal(orig, inp), "input was modified")
+
+	out, err = ToZeroBased().Do(out)
+	require.NoError(t, err)
+	require.Equal(t, inp, out)
+
+	// the first line doesn't underflow
+	out, err = ToZeroBased().Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, tree(raw(0, 0, 0), 0, 2), out)
+
+	_, err = ShiftLineColOpt(-1, -1, ShiftLineColOptions{Strict: true}).Do(inp)
+	require.True(t, ErrNegativePosition.Is(err), "%v", err)
+
+	// only columns are shifted
+	out, err = ShiftLineCol(0, 2).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, tree(raw(0, 0, 2), 1, 5), out)
+}
+
+func TestSetLanguage(t *testing.T) {
+	inp := un.Object{
+		u.KeyType: un.String("File"),
+		"body":    un.Array{un.Object{u.KeyType: un.String("Ident")}},
+	}
+	out, err := SetLanguage("go").Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		u.KeyLang: un.String("go"),
+		"body":    un.Array{un.Object{u.KeyType: un.String("Ident")}},
+	}, out)
+	require.Equal(t, "go", u.LanguageOf(out))
+	require.Equal(t, "", u.LanguageOf(inp))
+
+	_, err = SetLanguage("go").Do(un.Array{})
+	require.True(t, ErrExpectedObject.Is(err))
+}
+
+func TestDropEmptyFields(t *testing.T) {
+	inp := un.Object{
+		u.KeyType: un.String("Call"),
+		"args":    un.Array{},
+		"kwargs":  un.Object{},
+		"star":    nil,
+		"name":    un.Object{u.KeyType: un.String("Ident"), "ctx": un.Array{}},
+		"body":    un.Array{un.Object{"decorators": un.Object{"list": un.Array{}}}},
+	}
+
+	out, err := DropEmptyFields(EmptyFieldsOptions{}).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("Call"),
+		"name":    un.Object{u.KeyType: un.String("Ident")},
+		"body":    un.Array{un.Object{}},
+	}, out)
+
+	out, err = DropEmptyFields(EmptyFieldsOptions{
+		KeepNulls: true,
+		Keep:      []string{"args"},
+	}).Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("Call"),
+		"args":    un.Array{},
+		"star":    nil,
+		"name":    un.Object{u.KeyType: un.String("Ident")},
+		"body":    un.Array{un.Object{}},
+	}, out)
+}
+
+func TestBuildPipeline(t *testing.T) {
+	base := []Mapping{
+		AnnotateType("Ident", nil, role.Identifier),
+	}
+	extra := []Mapping{
+		AnnotateType("Ident", nil, role.Identifier, role.Name),
+		AnnotateType("Call", nil, role.Call),
+	}
+	inp := un.Object{
+		u.KeyType: un.String("Call"),
+		"args":    un.Array{un.Object{u.KeyType: un.String("Ident")}},
+	}
+	var out un.Node = inp
+	for _, tr := range BuildPipeline(base, extra) {
+		var err error
+		out, err = tr.Do(out)
+		require.NoError(t, err)
+	}
+	require.Equal(t, un.Object{
+		u.KeyType:  un.String("Call"),
+		u.KeyRoles: u.RoleList(role.Call),
+		"args": un.Array{un.Object{
+			u.KeyType:  un.String("Ident"),
+			u.KeyRoles: u.RoleList(role.Identifier, role.Name),
+		}},
+	}, out)
+
+	roles, _ := ListRoles(BuildPipeline(base, extra)...)
+	require.Equal(t, []role.Role{role.Identifier, role.Name, role.Call}, roles)
+}
+
+func TestMarkScopes(t *testing.T) {
+	pos := u.Positions{
+		u.KeyStart: {Offset: 3, Line: 1, Col: 4},
+		u.KeyEnd:   {Offset: 9, Line: 1, Col: 10},
+	}.ToObject()
+	inp := un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			un.Object{
+				u.KeyType:  un.String("Func"),
+				u.KeyRoles: u.RoleList(role.Function),
+				u.KeyPos:   pos,
+				"body": un.Object{
+					u.KeyType: un.String("Func"),
+					"body":    un.Array{un.Object{u.KeyType: un.String("Block")}},
+				},
+			},
+			un.Object{u.KeyType: un.String("Ident")},
+		},
+	}
+	tr := MarkScopes(map[string]role.Role{
+		"Func":  role.Scope,
+		"Block": role.Scope,
+	})
+	out, err := tr.Do(inp)
+	require.NoError(t, err)
+	exp := un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			un.Object{
+				u.KeyType:    un.String("Func"),
+				u.KeyRoles:   u.RoleList(role.Function, role.Scope),
+				u.KeyPos:     pos,
+				u.KeyScopeID: un.Int(1),
+				"body": un.Object{
+					u.KeyType:    un.String("Func"),
+					u.KeyRoles:   u.RoleList(role.Scope),
+					u.KeyScopeID: un.Int(2),
+					"body": un.Array{un.Object{
+						u.KeyType:    un.String("Block"),
+						u.KeyRoles:   u.RoleList(role.Scope),
+						u.KeyScopeID: un.Int(3),
+					}},
+				},
+			},
+			un.Object{u.KeyType: un.String("Ident")},
+		},
+	}
+	require.Equal(t, exp, out)
+
+	out2, err := tr.Do(out)
+	require.NoError(t, err)
+	require.Equal(t, exp, out2)
+
+	roles, _ := ListRoles(tr)
+	require.Equal(t, []role.Role{role.Scope}, roles)
+}
+
+func TestTagCategory(t *testing.T) {
+	obj := func(typ string, roles ...role.Role) un.Object {
+		n := un.Object{u.KeyType: un.String(typ)}
+		if len(roles) != 0 {
+			n[u.KeyRoles] = u.RoleList(roles...)
+		}
+		return n
+	}
+	with := func(n un.Object, c role.Category) un.Object {
+		n = n.CloneObject()
+		n["@category"] = un.String(c)
+		return n
+	}
+	fnc := obj("FuncDecl", role.Function, role.Declaration)
+	call := obj("Call", role.Expression, role.Call)
+	ifs := obj("If", role.Statement, role.If)
+	str := obj("Str", role.Expression, role.Literal, role.String)
+	ident := obj("Ident", role.Expression, role.Identifier)
+	stmt := obj("ExprStmt", role.Statement)
+	raw := obj("Raw")
+
+	inp := un.Array{fnc, call, ifs, str, ident, stmt, raw}
+	out, err := TagCategory("@category").Do(inp)
+	require.NoError(t, err)
+	require.Equal(t, un.Array{
+		with(fnc, role.CategoryDeclaration),
+		with(call, role.CategoryCallable),
+		with(ifs, role.CategoryControlFlow),
+		with(str, role.CategoryLiteral),
+		with(ident, role.CategoryIdentifier),
+		with(stmt, role.CategoryExpression),
+		raw,
+	}, out)
+}
+
+func TestRequireAnnotated(t *testing.T) {
+	tr := RequireAnnotated([]string{"Allowed"})
+	tree := un.Object{
+		u.KeyType:  un.String("File"),
+		u.KeyRoles: u.RoleList(role.File),
+		u.KeyPos:   u.Positions{u.KeyStart: {Offset: 1, Line: 1, Col: 2}}.ToObject(),
+		"body": un.Array{
+			un.Object{u.KeyType: un.String("Allowed")},
+			un.Object{u.KeyType: un.String("Missing"), "value": un.Int(1)},
+			un.Object{u.KeyType: un.String("Missing"), u.KeyRoles: un.Array{}},
+			un.String("value"),
+			un.Object{"untyped": un.Int(2)},
+		},
+	}
+	_, err := tr.Do(tree)
+	require.True(t, ErrUnannotatedTypes.Is(err), "%v", err)
+	require.Contains(t, err.Error(), `"Missing"`)
+	require.NotContains(t, err.Error(), "Allowed")
+
+	tree["body"] = un.Array{un.Object{u.KeyType: un.String("Allowed")}, un.String("value")}
+	out, err := tr.Do(tree)
+	require.NoError(t, err)
+	require.True(t, un.Same(tree, out))
+}
+
+func TestFilterTypes(t *testing.T) {
+	ident := func(name string, off uint32) un.Object {
+		return un.Object{
+			u.KeyType: un.String("Ident"),
+			u.KeyPos: u.Positions{
+				u.KeyStart: {Offset: off, Line: 1, Col: off + 1},
+			}.ToObject(),
+			"Name": un.String(name),
+		}
+	}
+	call := func(callee un.Object, args ...un.Node) un.Object {
+		return un.Object{u.KeyType: un.String("Call"), "callee": callee, "args": append(un.Array{}, args...)}
+	}
+	callA := call(ident("a", 0), call(ident("b", 2)))
+	tree := un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			callA,
+			un.Object{u.KeyType: un.String("Wrapper"), "inner": call(ident("c", 6)), "value": un.Int(1)},
+			ident("d", 9),
+		},
+	}
+	orig := tree.Clone()
+
+	do := func(tr Transformer) un.Node {
+		out, err := tr.Do(tree)
+		require.NoError(t, err)
+		require.Equal(t, orig, tree)
+		return out
+	}
+
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			callA,
+			un.Object{u.KeyType: un.String("Wrapper"), "inner": call(ident("c", 6))},
+		},
+	}, do(KeepTypes([]string{"Call"})))
+	require.Nil(t, do(KeepTypes([]string{"Missing"})))
+
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"body":    un.Array{callA, ident("d", 9)},
+	}, do(DropTypes([]string{"Wrapper"}, DropTypesOptions{})))
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"body":    un.Array{callA, call(ident("c", 6)), ident("d", 9)},
+	}, do(DropTypes([]string{"Wrapper"}, DropTypesOptions{Reparent: true})))
+
+	// nested matches
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			un.Object{u.KeyType: un.String("Wrapper"), "value": un.Int(1)},
+			ident("d", 9),
+		},
+	}, do(DropTypes([]string{"Call"}, DropTypesOptions{})))
+	require.Equal(t, un.Object{
+		u.KeyType: un.String("File"),
+		"body": un.Array{
+			ident("a", 0), ident("b", 2),
+			un.Object{u.KeyType: un.String("Wrapper"), "inner": ident("c", 6), "value": un.Int(1)},
+			ident("d", 9),
+		},
+	}, do(DropTypes([]string{"Call"}, DropTypesOptions{Reparent: true})))
+
+	// root removal
+	all := []string{"File", "Wrapper", "Call", "Ident"}
+	require.Nil(t, do(DropTypes(all, DropTypesOptions{})))
+	require.Equal(t, un.Array{
+		ident("a", 0), ident("b", 2), ident("c", 6), ident("d", 9),
+	}, do(DropTypes(all[:3], DropTypesOptions{Reparent: true})))
+}
+
+func TestComputeQualifiedNames(t *testing.T) {
+	obj := func(typ string, name un.Node, fields un.Object) un.Object {
+		o := un.Object{u.KeyType: un.String(typ)}
+		if name != nil {
+			o["Name"] = name
+		}
+		for k, v := range fields {
+			o[k] = v
+		}
+		return o
+	}
+	qname := func(o un.Object, name string) un.Object {
+		o = o.CloneObject()
+		o[u.KeyQualifiedName] = un.String(name)
+		return o
+	}
+	local := obj("Var", un.String("x"), nil)
+	anon := obj("Class", nil, un.Object{"Body": un.Array{obj("Method", un.String("call"), nil)}})
+	method := obj("Method", un.Object{u.KeyType: un.String("uast:Identifier"), "Name": un.String("run")}, un.Object{
+		"Body": un.Array{local, anon},
+	})
+	field := obj("Field", un.Object{u.KeyType: un.String("Ident"), u.KeyToken: un.String("count")}, nil)
+	inner := obj("Class", un.String("Inner"), un.Object{"Body": un.Array{method}})
+	outer := obj("Class", un.String("Outer"), un.Object{"Body": un.Array{inner, field}})
+	tree := obj("Package", un.String("pkg"), un.Object{"Body": un.Array{outer}})
+	orig := tree.Clone()
+
+	tr := ComputeQualifiedNames(QualifiedNamesOptions{
+		Types: map[string]NameSegment{
+			"Package": {Field: "Name"},
+			"Class":   {Field: "Name"},
+			"Method":  {Field: "Name"},
+			"Field":   {Field: "Name", Leaf: true},
+			"Var":     {Field: "Name", Leaf: true},
+		},
+	})
+	out, err := tr.Do(tree)
+	require.NoError(t, err)
+	require.Equal(t, orig, tree)
+
+	anon2 := anon.CloneObject()
+	anon2["Body"] = un.Array{qname(anon["Body"].(un.Array)[0].(un.Object), "pkg.Outer.Inner.run.call")}
+	method2 := qname(method, "pkg.Outer.Inner.run")
+	method2["Body"] = un.Array{qname(local, "pkg.Outer.Inner.run.x"), anon2}
+	inner2 := qname(inner, "pkg.Outer.Inner")
+	inner2["Body"] = un.Array{method2}
+	outer2 := qname(outer, "pkg.Outer")
+	outer2["Body"] = un.Array{inner2, qname(field, "pkg.Outer.count")}
+	exp := qname(tree, "pkg")
+	exp["Body"] = un.Array{outer2}
+	require.Equal(t, exp, out)
+
+	// idempotent
+	out2, err := tr.Do(out)
+	require.NoError(t, err)
+	require.True(t, un.Same(out, out2))
+
+	// custom key and separator
+	out, err = ComputeQualifiedNames(QualifiedNamesOptions{
+		Key:       "fqn",
+		Separator: "::",
+		Types:     map[string]NameSegment{"Class": {Field: "Name"}},
+	}).Do(outer)
+	require.NoError(t, err)
+	require.Equal(t, un.String("Outer::Inner"), out.(un.Object)["Body"].(un.Array)[0].(un.Object)["fqn"])
+}
+
+func TestDoImmutable(t *testing.T) {
+	ident := func(name string, start, end uint32) un.Object {
+		return un.Object{
+			u.KeyType:  un.String("Ident"),
+			u.KeyRoles: u.RoleList(role.Identifier, role.Identifier),
+			u.KeyPos: u.Positions{
+				u.KeyStart: {Offset: start},
+				u.KeyEnd:   {Offset: end},
+			}.ToObject(),
+			"Name": un.String(name),
+		}
+	}
+	inp := un.Object{
+		u.KeyType:   un.String("Decl"),
+		"modifiers": un.String("public static"),
+		"Names":     un.Array{ident("b", 3, 4), ident("a", 1, 2)},
+	}
+	orig := inp.Clone()
+
+	list := []Transformer{
+		Mappings(
+			AnnotateType("Ident", nil, role.Name),
+		),
+		RolesDedup(),
+		SplitToRoles("modifiers", "", map[string]role.Role{"public": role.Visibility}),
+		SortArray("Names", ByField("Name")),
+		FillRootPositionFromChildren(),
+	}
+	run := func() un.Node {
+		var n un.Node = inp
+		for _, tr := range list {
+			var err error
+			n, err = tr.Do(n)
+			require.NoError(t, err)
+		}
+		return n
+	}
+	out1 := run()
+	require.Equal(t, orig, inp)
+	out2 := run()
+	require.Equal(t, out1, out2)
+	require.NotEqual(t, orig, out1)
+
+	// in-place transformations should use DoCopy
+	mutate := TransformObjFunc(func(n un.Object) (un.Object, bool, error) {
+		n["mutated"] = un.Bool(true)
+		return n, false, nil
+	})
+	out, err := DoCopy(mutate, inp)
+	require.NoError(t, err)
+	require.Equal(t, orig, inp)
+	require.Equal(t, un.Bool(true), out.(un.Object)["mutated"])
+}
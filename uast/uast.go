@@ -66,6 +66,11 @@ const (
 	KeyToken = "@token" // token of the UAST node (Native and Annotated nodes only)
 	KeyRoles = "@role"  // roles of UAST node (Annotated nodes only); for representations see RoleList
 	KeyPos   = "@pos"   // positional information is stored in this field, see Positions
+	KeyLang  = "@lang"  // source language of the tree; only set on the root node, see LanguageOf
+
+	KeyCommentBlock  = "@block" // distinguishes block comments from line comments, see transformer.NormalizeComments
+	KeyScopeID       = "@scope" // unique ID of the scope introduced by the node, see transformer.MarkScopes
+	KeyQualifiedName = "@qname" // qualified name of the declaration, see transformer.ComputeQualifiedNames
 )
 
 const (
@@ -233,6 +238,33 @@ func RoleList(roles ...role.Role) nodes.Array {
 	return arr
 }
 
+// AnnotatedRoles returns roles stored in the KeyRoles field of an object. Contrary to RolesOf, it accepts external
+// nodes and returns no roles for objects without the roles field, instead of role.Unannotated.
+// Values in the roles field that are not strings are skipped.
+func AnnotatedRoles(n nodes.External) role.Roles {
+	obj, ok := n.(nodes.ExternalObject)
+	if !ok {
+		return nil
+	}
+	v, _ := obj.ValueAt(KeyRoles)
+	arr, ok := v.(nodes.ExternalArray)
+	if !ok || arr.Size() == 0 {
+		return nil
+	}
+	sz := arr.Size()
+	out := make(role.Roles, 0, sz)
+	for i := 0; i < sz; i++ {
+		e := arr.ValueAt(i)
+		if e == nil {
+			continue
+		}
+		if s, ok := e.Value().(nodes.String); ok {
+			out = append(out, role.FromString(string(s)))
+		}
+	}
+	return out
+}
+
 // RolesOf is a helper for getting node UAST roles (see KeyRoles).
 // The function will returns nil roles array for non-object nodes like arrays and values.
 func RolesOf(n nodes.Node) role.Roles {
@@ -279,6 +311,21 @@ func TokenOf(n nodes.Node) string {
 	return ""
 }
 
+// LanguageOf returns the source language of the tree stored in the root node (see KeyLang).
+// It returns an empty string if the root is not an object, or the language is not set.
+func LanguageOf(root nodes.External) string {
+	obj, ok := root.(nodes.ExternalObject)
+	if !ok || root.Kind() != nodes.KindObject {
+		return ""
+	}
+	v, _ := obj.ValueAt(KeyLang)
+	if v == nil {
+		return ""
+	}
+	lang, _ := v.Value().(nodes.String)
+	return string(lang)
+}
+
 // Tokens collects all tokens of the tree recursively (pre-order). See TokenOf.
 func Tokens(n nodes.Node) []string {
 	var tokens []string
@@ -412,3 +412,21 @@ func TestWalkPreOrder(t *testing.T) {
 
 	require.Equal([]string{"a", "aa", "ab", "aba", "ac"}, result)
 }
+
+func TestAnnotatedRoles(t *testing.T) {
+	obj := nodes.Object{
+		KeyType:  nodes.String("Ident"),
+		KeyRoles: nodes.Array{nodes.String(role.Identifier.String()), nodes.Int(1), nil},
+	}
+	exp := role.Roles{role.Identifier}
+	require.Equal(t, exp, AnnotatedRoles(obj))
+	require.Equal(t, exp, RolesOf(obj))
+
+	// contrary to RolesOf, objects without roles are not considered unannotated
+	obj = nodes.Object{KeyType: nodes.String("Ident")}
+	require.Nil(t, AnnotatedRoles(obj))
+	require.Equal(t, role.Roles{role.Unannotated}, RolesOf(obj))
+
+	require.Nil(t, AnnotatedRoles(nodes.String("a")))
+	require.Nil(t, AnnotatedRoles(nil))
+}
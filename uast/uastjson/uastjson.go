@@ -0,0 +1,341 @@
+// Package uastjson implements a JSON encoding for UAST with a configurable representation of roles and order of fields.
+package uastjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+// ErrUnknownRole is returned when a role name or ID is not defined in the role package.
+var ErrUnknownRole = errors.NewKind("unknown role: %v")
+
+// RoleFormat is a representation of roles (see uast.KeyRoles) in the JSON output.
+type RoleFormat int
+
+const (
+	// RoleNames encodes roles as their string names. This is the same representation as used in the UAST.
+	// It is convenient for human-readable output.
+	RoleNames RoleFormat = iota
+	// RoleIDs encodes roles as their numeric IDs. It results in a more compact output.
+	RoleIDs
+)
+
+// Marshal encodes the UAST to JSON, with roles encoded as names.
+func Marshal(n nodes.Node) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// NewEncoder creates a JSON encoder for UAST.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encoder is a JSON encoder for UAST.
+type Encoder struct {
+	w       io.Writer
+	prefix  string
+	indent  string
+	roles   RoleFormat
+	ordered bool
+
+	maxArray  int
+	maxString int
+}
+
+// SetRoleFormat sets the representation of roles in the output. Default is RoleNames.
+func (enc *Encoder) SetRoleFormat(f RoleFormat) {
+	enc.roles = f
+}
+
+// SetIndent instructs the encoder to indent the output. See json.Encoder.SetIndent.
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix, enc.indent = prefix, indent
+}
+
+// SetCanonicalOrder enables the canonical order of object fields in the output: the type, token, roles and
+// positions go first, followed by other fields in alphabetical order. Fields of positional information objects
+// are ordered the same way as in uastyaml: start before end, and offset, line, column.
+//
+// By default, all fields are written in alphabetical order.
+func (enc *Encoder) SetCanonicalOrder(enable bool) {
+	enc.ordered = enable
+}
+
+// SetMaxArrayElements limits the number of elements written for each array. The rest of the elements are replaced
+// with a "... (N more)" marker. Zero means no limit.
+//
+// The marker is written outside of any JSON value, thus it cannot be confused with the content of the tree,
+// but the output is no longer a valid JSON. It is intended for human-readable dumps and cannot be decoded.
+func (enc *Encoder) SetMaxArrayElements(n int) {
+	enc.maxArray = n
+}
+
+// SetMaxStringLen limits the number of characters written for each string value. The rest of the characters are
+// replaced with a "... (N more chars)" marker after the closing quote. Object keys are never truncated.
+// Zero means no limit.
+//
+// As with SetMaxArrayElements, the output is not a valid JSON if any of the strings were truncated.
+func (enc *Encoder) SetMaxStringLen(n int) {
+	enc.maxString = n
+}
+
+// Encode writes the JSON encoding of the node to the stream, followed by a newline.
+func (enc *Encoder) Encode(n nodes.Node) error {
+	if enc.roles == RoleIDs {
+		var err error
+		n, err = mapRoles(n, roleToID)
+		if err != nil {
+			return err
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := enc.writeNode(buf, n, 0); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := enc.w.Write(buf.Bytes())
+	return err
+}
+
+func (enc *Encoder) writeNode(buf *bytes.Buffer, n nodes.Node, depth int) error {
+	switch n := n.(type) {
+	case nodes.Object:
+		keys := enc.keys(n)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			enc.newline(buf, depth+1)
+			if err := writeJSON(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if enc.indented() {
+				buf.WriteByte(' ')
+			}
+			if err := enc.writeNode(buf, n[k], depth+1); err != nil {
+				return err
+			}
+		}
+		if len(keys) != 0 {
+			enc.newline(buf, depth)
+		}
+		buf.WriteByte('}')
+		return nil
+	case nodes.Array:
+		buf.WriteByte('[')
+		sz := len(n)
+		if enc.maxArray > 0 && sz > enc.maxArray {
+			sz = enc.maxArray
+		}
+		for i, v := range n[:sz] {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			enc.newline(buf, depth+1)
+			if err := enc.writeNode(buf, v, depth+1); err != nil {
+				return err
+			}
+		}
+		if sz < len(n) {
+			if sz != 0 {
+				buf.WriteByte(',')
+			}
+			enc.newline(buf, depth+1)
+			fmt.Fprintf(buf, "... (%d more)", len(n)-sz)
+		}
+		if len(n) != 0 {
+			enc.newline(buf, depth)
+		}
+		buf.WriteByte(']')
+		return nil
+	case nodes.String:
+		if enc.maxString > 0 {
+			if r := []rune(string(n)); len(r) > enc.maxString {
+				if err := writeJSON(buf, string(r[:enc.maxString])); err != nil {
+					return err
+				}
+				fmt.Fprintf(buf, "... (%d more chars)", len(r)-enc.maxString)
+				return nil
+			}
+		}
+	}
+	return writeJSON(buf, n)
+}
+
+func (enc *Encoder) indented() bool {
+	return enc.prefix != "" || enc.indent != ""
+}
+
+// newline starts a new line with a given indentation level, if the indentation is enabled.
+func (enc *Encoder) newline(buf *bytes.Buffer, depth int) {
+	if !enc.indented() {
+		return
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(enc.prefix)
+	for i := 0; i < depth; i++ {
+		buf.WriteString(enc.indent)
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// keys returns the fields of the object in the order they should be written.
+func (enc *Encoder) keys(obj nodes.Object) []string {
+	keys := obj.Keys()
+	if !enc.ordered {
+		return keys
+	}
+	var first []string
+	switch uast.TypeOf(obj) {
+	case uast.TypePositions:
+		first = []string{uast.KeyType, uast.KeyStart, uast.KeyEnd}
+	case uast.TypePosition:
+		first = []string{uast.KeyType, uast.KeyPosOff, uast.KeyPosLine, uast.KeyPosCol}
+	default:
+		first = []string{uast.KeyType, uast.KeyToken, uast.KeyRoles, uast.KeyPos}
+	}
+	out := make([]string, 0, len(keys))
+	for _, k := range first {
+		if _, ok := obj[k]; ok {
+			out = append(out, k)
+		}
+	}
+	for _, k := range keys {
+		if !containsKey(first, k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func containsKey(keys []string, k string) bool {
+	for _, k2 := range keys {
+		if k == k2 {
+			return true
+		}
+	}
+	return false
+}
+
+// Unmarshal decodes JSON to a UAST. Roles are accepted both as names and as numeric IDs.
+func Unmarshal(data []byte) (nodes.Node, error) {
+	return NewDecoder(bytes.NewReader(data)).Decode()
+}
+
+// NewDecoder creates a JSON decoder for UAST.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// Decoder is a JSON decoder for UAST.
+type Decoder struct {
+	dec *json.Decoder
+}
+
+// Decode reads the next UAST from the stream. Roles are accepted both as names and as numeric IDs,
+// and are always stored as names in the resulting tree. Unknown roles are rejected with ErrUnknownRole.
+func (dec *Decoder) Decode() (nodes.Node, error) {
+	var o interface{}
+	if err := dec.dec.Decode(&o); err != nil {
+		return nil, err
+	}
+	n, err := uast.ToNode(o)
+	if err != nil {
+		return nil, err
+	}
+	return mapRoles(n, roleToName)
+}
+
+// mapRoles converts each element of role lists in the tree with a given function.
+func mapRoles(root nodes.Node, fnc func(v nodes.Node) (nodes.Node, error)) (nodes.Node, error) {
+	var last error
+	out, changed := nodes.Apply(root, func(n nodes.Node) (nodes.Node, bool) {
+		obj, ok := n.(nodes.Object)
+		if !ok || last != nil {
+			return n, false
+		}
+		arr, ok := obj[uast.KeyRoles].(nodes.Array)
+		if !ok {
+			return n, false
+		}
+		roles := make(nodes.Array, 0, len(arr))
+		for _, v := range arr {
+			r, err := fnc(v)
+			if err != nil {
+				last = err
+				return n, false
+			}
+			roles = append(roles, r)
+		}
+		obj = obj.CloneObject()
+		obj[uast.KeyRoles] = roles
+		return obj, true
+	})
+	if last != nil {
+		return nil, last
+	} else if !changed {
+		return root, nil
+	}
+	return out, nil
+}
+
+// roleToID converts a role name to its numeric ID.
+func roleToID(v nodes.Node) (nodes.Node, error) {
+	s, ok := v.(nodes.String)
+	if !ok {
+		return nil, fmt.Errorf("expected a role name, got: %T", v)
+	}
+	r := role.FromString(string(s))
+	if !r.Valid() {
+		return nil, ErrUnknownRole.New(string(s))
+	}
+	return nodes.Int(r), nil
+}
+
+// roleToName converts a numeric role ID to its name. Role names are validated, but left unchanged.
+func roleToName(v nodes.Node) (nodes.Node, error) {
+	var r role.Role
+	switch v := v.(type) {
+	case nodes.String:
+		if r = role.FromString(string(v)); !r.Valid() {
+			return nil, ErrUnknownRole.New(string(v))
+		}
+		return v, nil
+	case nodes.Int:
+		r = role.Role(v)
+		if int64(r) != int64(v) || !r.Valid() {
+			return nil, ErrUnknownRole.New(int64(v))
+		}
+	case nodes.Uint:
+		r = role.Role(v)
+		if uint64(r) != uint64(v) || !r.Valid() {
+			return nil, ErrUnknownRole.New(uint64(v))
+		}
+	default:
+		return nil, fmt.Errorf("expected a role name or ID, got: %T", v)
+	}
+	return nodes.String(r.String()), nil
+}
@@ -0,0 +1,124 @@
+package uastjson
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bblfsh/sdk/v3/uast"
+	"github.com/bblfsh/sdk/v3/uast/nodes"
+	"github.com/bblfsh/sdk/v3/uast/role"
+)
+
+var testTree = nodes.Object{
+	uast.KeyType:  nodes.String("Ident"),
+	uast.KeyRoles: uast.RoleList(role.Identifier, role.Expression),
+	"Name":        nodes.String("a"),
+	"Args": nodes.Array{
+		nodes.Object{
+			uast.KeyType:  nodes.String("Literal"),
+			uast.KeyRoles: uast.RoleList(role.Literal),
+			"Value":       nodes.Int(1),
+		},
+	},
+}
+
+func TestRoleFormat(t *testing.T) {
+	data, err := Marshal(testTree)
+	require.NoError(t, err)
+	require.Equal(t, `{"@role":["Identifier","Expression"],"@type":"Ident","Args":[{"@role":["Literal"],"@type":"Literal","Value":1}],"Name":"a"}`, string(data))
+
+	out, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, testTree, out)
+
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	enc.SetRoleFormat(RoleIDs)
+	err = enc.Encode(testTree)
+	require.NoError(t, err)
+	require.Equal(t, `{"@role":[1,18],"@type":"Ident","Args":[{"@role":[88],"@type":"Literal","Value":1}],"Name":"a"}`+"\n", buf.String())
+
+	out, err = Unmarshal(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, testTree, out)
+}
+
+func TestUnknownRole(t *testing.T) {
+	for _, data := range []string{
+		`{"@type":"Ident","@role":["Identifier","Bogus"]}`,
+		`{"@type":"Ident","@role":[0]}`,
+		`{"@type":"Ident","@role":[100000]}`,
+	} {
+		_, err := Unmarshal([]byte(data))
+		require.True(t, ErrUnknownRole.Is(err), "%s: %v", data, err)
+	}
+
+	enc := NewEncoder(bytes.NewBuffer(nil))
+	enc.SetRoleFormat(RoleIDs)
+	err := enc.Encode(nodes.Object{uast.KeyRoles: nodes.Array{nodes.String("Bogus")}})
+	require.True(t, ErrUnknownRole.Is(err), "%v", err)
+}
+
+func TestCanonicalOrder(t *testing.T) {
+	n := nodes.Object{
+		"body":        nodes.Array{nodes.Object{"b": nodes.Int(1), "a": nil}},
+		"Name":        nodes.String("f"),
+		uast.KeyToken: nodes.String("f"),
+		uast.KeyRoles: uast.RoleList(role.Function),
+		uast.KeyType:  nodes.String("Func"),
+		uast.KeyPos: uast.Positions{
+			uast.KeyStart: {Offset: 1, Line: 2, Col: 3},
+			uast.KeyEnd:   {Offset: 4, Line: 5, Col: 6},
+		}.ToObject(),
+	}
+
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	err := enc.Encode(n)
+	require.NoError(t, err)
+	require.Equal(t, `{"@pos":{"@type":"uast:Positions","end":{"@type":"uast:Position","col":6,"line":5,"offset":4},"start":{"@type":"uast:Position","col":3,"line":2,"offset":1}},"@role":["Function"],"@token":"f","@type":"Func","Name":"f","body":[{"a":null,"b":1}]}`+"\n", buf.String())
+
+	buf.Reset()
+	enc.SetCanonicalOrder(true)
+	err = enc.Encode(n)
+	require.NoError(t, err)
+	require.Equal(t, `{"@type":"Func","@token":"f","@role":["Function"],"@pos":{"@type":"uast:Positions","start":{"@type":"uast:Position","offset":1,"line":2,"col":3},"end":{"@type":"uast:Position","offset":4,"line":5,"col":6}},"Name":"f","body":[{"a":null,"b":1}]}`+"\n", buf.String())
+
+	buf.Reset()
+	enc.SetIndent("", "  ")
+	err = enc.Encode(nodes.Object{"b": nodes.Int(1), uast.KeyType: nodes.String("T")})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"@type\": \"T\",\n  \"b\": 1\n}\n", buf.String())
+}
+
+func TestTruncate(t *testing.T) {
+	n := nodes.Object{
+		uast.KeyType: nodes.String("File"),
+		"body":       nodes.Array{nodes.Int(1), nodes.Int(2), nodes.Int(3), nodes.Int(4), nodes.Int(5)},
+		"text":       nodes.String("abcdéfgh"),
+		"empty":      nodes.Array{},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	enc.SetMaxArrayElements(5)
+	enc.SetMaxStringLen(8)
+	err := enc.Encode(n)
+	require.NoError(t, err)
+	require.Equal(t, `{"@type":"File","body":[1,2,3,4,5],"empty":[],"text":"abcdéfgh"}`+"\n", buf.String())
+
+	buf.Reset()
+	enc.SetMaxArrayElements(2)
+	enc.SetMaxStringLen(5)
+	err = enc.Encode(n)
+	require.NoError(t, err)
+	require.Equal(t, `{"@type":"File","body":[1,2,... (3 more)],"empty":[],"text":"abcdé"... (3 more chars)}`+"\n", buf.String())
+
+	buf.Reset()
+	enc.SetIndent("", "  ")
+	err = enc.Encode(nodes.Object{"body": n["body"]})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"body\": [\n    1,\n    2,\n    ... (3 more)\n  ]\n}\n", buf.String())
+}